@@ -25,9 +25,10 @@ import (
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 )
 
-func Deploy(operatorConfig OperatorConfig, configPath string, deploymentBytesMap map[string][]byte, force bool) (schema.DeployResponse, error) {
+func Deploy(operatorConfig OperatorConfig, configPath string, deploymentBytesMap map[string][]byte, force bool, dryRun bool) (schema.DeployResponse, error) {
 	params := map[string]string{
 		"force":          s.Bool(force),
+		"dryRun":         s.Bool(dryRun),
 		"configFileName": filepath.Base(configPath),
 	}
 	uploadInput := &HTTPUploadInput{
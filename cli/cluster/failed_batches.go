@@ -0,0 +1,56 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"path"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+func GetFailedBatches(operatorConfig OperatorConfig, apiName string, jobID string) (schema.GetFailedBatchesResponse, error) {
+	endpoint := path.Join("/batch", apiName, jobID, "failed_batches")
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.GetFailedBatchesResponse{}, err
+	}
+
+	var failedBatchesRes schema.GetFailedBatchesResponse
+	if err = json.Unmarshal(httpRes, &failedBatchesRes); err != nil {
+		return schema.GetFailedBatchesResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return failedBatchesRes, nil
+}
+
+func ResubmitFailedBatches(operatorConfig OperatorConfig, apiName string, jobID string) (spec.Job, error) {
+	endpoint := path.Join("/batch", apiName, jobID, "failed_batches")
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint)
+	if err != nil {
+		return spec.Job{}, err
+	}
+
+	var jobSpec spec.Job
+	if err = json.Unmarshal(httpRes, &jobSpec); err != nil {
+		return spec.Job{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return jobSpec, nil
+}
@@ -51,6 +51,21 @@ func GetAPI(operatorConfig OperatorConfig, apiName string) (schema.GetAPIRespons
 	return apiRes, nil
 }
 
+func GetAutoscaling(operatorConfig OperatorConfig, apiName string) (schema.GetAutoscalingResponse, error) {
+	endpoint := path.Join("/autoscaling", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.GetAutoscalingResponse{}, err
+	}
+
+	var autoscalingRes schema.GetAutoscalingResponse
+	if err = json.Unmarshal(httpRes, &autoscalingRes); err != nil {
+		return schema.GetAutoscalingResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return autoscalingRes, nil
+}
+
 func GetJob(operatorConfig OperatorConfig, apiName string, jobID string) (schema.GetJobResponse, error) {
 	endpoint := path.Join("/batch", apiName, jobID)
 	httpRes, err := HTTPGet(operatorConfig, endpoint)
@@ -65,3 +80,19 @@ func GetJob(operatorConfig OperatorConfig, apiName string, jobID string) (schema
 
 	return jobRes, nil
 }
+
+// qParams may include cursor, pageSize, status, submittedAfter, and submittedBefore
+func ListJobs(operatorConfig OperatorConfig, apiName string, qParams map[string]string) (schema.ListJobsResponse, error) {
+	endpoint := path.Join("/batch", apiName, "jobs")
+	httpRes, err := HTTPGet(operatorConfig, endpoint, qParams)
+	if err != nil {
+		return schema.ListJobsResponse{}, err
+	}
+
+	var jobsRes schema.ListJobsResponse
+	if err = json.Unmarshal(httpRes, &jobsRes); err != nil {
+		return schema.ListJobsResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return jobsRes, nil
+}
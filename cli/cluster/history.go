@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"path"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func GetHistory(operatorConfig OperatorConfig, apiName string) (schema.GetHistoryResponse, error) {
+	endpoint := path.Join("/history", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.GetHistoryResponse{}, err
+	}
+
+	var historyRes schema.GetHistoryResponse
+	if err = json.Unmarshal(httpRes, &historyRes); err != nil {
+		return schema.GetHistoryResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return historyRes, nil
+}
+
+func Rollback(operatorConfig OperatorConfig, apiName string, apiID string, force bool) (schema.RollbackResponse, error) {
+	params := map[string]string{
+		"force": s.Bool(force),
+	}
+	if apiID != "" {
+		params["to"] = apiID
+	}
+
+	endpoint := path.Join("/rollback", apiName)
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint, params)
+	if err != nil {
+		return schema.RollbackResponse{}, err
+	}
+
+	var rollbackRes schema.RollbackResponse
+	if err = json.Unmarshal(httpRes, &rollbackRes); err != nil {
+		return schema.RollbackResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return rollbackRes, nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"path"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+func CreateScheduledJob(operatorConfig OperatorConfig, apiName string, submissionBytes []byte) (schema.ScheduledJob, error) {
+	endpoint := path.Join("/schedules", apiName)
+	httpRes, err := HTTPPostJSON(operatorConfig, endpoint, submissionBytes)
+	if err != nil {
+		return schema.ScheduledJob{}, err
+	}
+
+	return unmarshalScheduledJob(endpoint, httpRes)
+}
+
+func GetScheduledJobs(operatorConfig OperatorConfig, apiName string) (schema.GetScheduledJobsResponse, error) {
+	endpoint := path.Join("/schedules", apiName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.GetScheduledJobsResponse{}, err
+	}
+
+	var scheduledJobsRes schema.GetScheduledJobsResponse
+	if err = json.Unmarshal(httpRes, &scheduledJobsRes); err != nil {
+		return schema.GetScheduledJobsResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return scheduledJobsRes, nil
+}
+
+func GetScheduledJob(operatorConfig OperatorConfig, apiName string, scheduleName string) (schema.ScheduledJob, error) {
+	endpoint := path.Join("/schedules", apiName, scheduleName)
+	httpRes, err := HTTPGet(operatorConfig, endpoint)
+	if err != nil {
+		return schema.ScheduledJob{}, err
+	}
+
+	return unmarshalScheduledJob(endpoint, httpRes)
+}
+
+func PauseScheduledJob(operatorConfig OperatorConfig, apiName string, scheduleName string) (schema.ScheduledJob, error) {
+	endpoint := path.Join("/schedules", apiName, scheduleName, "pause")
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint)
+	if err != nil {
+		return schema.ScheduledJob{}, err
+	}
+
+	return unmarshalScheduledJob(endpoint, httpRes)
+}
+
+func ResumeScheduledJob(operatorConfig OperatorConfig, apiName string, scheduleName string) (schema.ScheduledJob, error) {
+	endpoint := path.Join("/schedules", apiName, scheduleName, "resume")
+	httpRes, err := HTTPPostNoBody(operatorConfig, endpoint)
+	if err != nil {
+		return schema.ScheduledJob{}, err
+	}
+
+	return unmarshalScheduledJob(endpoint, httpRes)
+}
+
+func DeleteScheduledJob(operatorConfig OperatorConfig, apiName string, scheduleName string) (schema.DeleteResponse, error) {
+	endpoint := path.Join("/schedules", apiName, scheduleName)
+	httpRes, err := HTTPDelete(operatorConfig, endpoint)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	var deleteRes schema.DeleteResponse
+	if err = json.Unmarshal(httpRes, &deleteRes); err != nil {
+		return schema.DeleteResponse{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+
+	return deleteRes, nil
+}
+
+func unmarshalScheduledJob(endpoint string, httpRes []byte) (schema.ScheduledJob, error) {
+	var scheduledJob schema.ScheduledJob
+	if err := json.Unmarshal(httpRes, &scheduledJob); err != nil {
+		return schema.ScheduledJob{}, errors.Wrap(err, endpoint, string(httpRes))
+	}
+	return scheduledJob, nil
+}
@@ -0,0 +1,361 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/spf13/cobra"
+)
+
+const (
+	_seriesTimeColumn        = "time"
+	_seriesInFlightColumn    = "in_flight"
+	_seriesP90LatencyColumn  = "p90_latency"
+	_seriesRequestRateColumn = "request_rate"
+)
+
+var (
+	_flagSimulateAPI      string
+	_flagSimulateReplicas int32
+	_flagSimulateOutput   string
+	_flagSimulateAll      bool
+)
+
+func autoscalerInit() {
+	_autoscalerSimulateCmd.Flags().SortFlags = false
+	_autoscalerSimulateCmd.Flags().StringVar(&_flagSimulateAPI, "api", "", "name of the api to simulate (required if the config file contains multiple sync apis)")
+	_autoscalerSimulateCmd.Flags().Int32Var(&_flagSimulateReplicas, "replicas", 0, "number of replicas at the start of the simulation (defaults to init_replicas)")
+	_autoscalerSimulateCmd.Flags().StringVarP(&_flagSimulateOutput, "output", "o", "", "export every autoscaler decision to a file (.csv or .json) instead of printing the scaling events")
+	_autoscalerSimulateCmd.Flags().BoolVarP(&_flagSimulateAll, "all", "a", false, "print every autoscaler decision, not just the scaling events")
+	_autoscalerCmd.AddCommand(_autoscalerSimulateCmd)
+}
+
+var _autoscalerCmd = &cobra.Command{
+	Use:   "autoscaler",
+	Short: "tune the autoscaling configuration of apis",
+}
+
+var _autoscalerSimulateCmd = &cobra.Command{
+	Use:   "simulate CONFIG_FILE SERIES_FILE",
+	Short: "simulate the autoscaler against a time series of in-flight requests",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.autoscaler.simulate")
+
+		configPath := files.RelToAbsPath(args[0], _cwd)
+		api, err := readAPIToSimulate(configPath, _flagSimulateAPI)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		seriesPath := files.RelToAbsPath(args[1], _cwd)
+		samples, err := readMetricsSeries(seriesPath)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		initialReplicas := api.Autoscaling.InitReplicas
+		if cmd.Flags().Changed("replicas") {
+			initialReplicas = _flagSimulateReplicas
+		}
+
+		ticks, err := autoscaler.Simulate(api.Name, api.Autoscaling, initialReplicas, samples, spec.AutoscalingTickInterval)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagSimulateOutput != "" {
+			if err := exportAutoscalerTicks(ticks, _flagSimulateOutput); err != nil {
+				exit.Error(err)
+			}
+			print.BoldFirstLine(fmt.Sprintf("exported %d %s to %s", len(ticks), s.PluralS("autoscaler decision", len(ticks)), _flagSimulateOutput))
+			return
+		}
+
+		fmt.Print(simulationStr(ticks, _flagSimulateAll))
+	},
+}
+
+func readAPIToSimulate(configPath string, apiName string) (*userconfig.API, error) {
+	configBytes, err := files.ReadFileBytes(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	apis, err := spec.ExtractAPIConfigs(configBytes, types.AWSProviderType, filepath.Base(configPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var syncAPIs []*userconfig.API
+	for i := range apis {
+		if apis[i].Kind == userconfig.SyncAPIKind && (apiName == "" || apis[i].Name == apiName) {
+			syncAPIs = append(syncAPIs, &apis[i])
+		}
+	}
+
+	if len(syncAPIs) != 1 {
+		var syncAPINames []string
+		for i := range apis {
+			if apis[i].Kind == userconfig.SyncAPIKind {
+				syncAPINames = append(syncAPINames, apis[i].Name)
+			}
+		}
+		return nil, ErrorSpecifyAPIToSimulate(configPath, apiName, syncAPINames)
+	}
+
+	api := syncAPIs[0]
+	if err := spec.ValidateAutoscaling(api); err != nil {
+		return nil, errors.Wrap(err, api.Identify(), userconfig.AutoscalingKey)
+	}
+
+	return api, nil
+}
+
+// The series can be a csv file with a header row, or a json list of objects; the time of each sample is either an RFC 3339 timestamp or a number of seconds
+func readMetricsSeries(seriesPath string) ([]autoscaler.MetricsSample, error) {
+	seriesBytes, err := files.ReadFileBytes(seriesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]string
+	if strings.HasSuffix(strings.ToLower(seriesPath), ".json") {
+		records, err = metricsSeriesJSONRecords(seriesBytes)
+	} else {
+		records, err = metricsSeriesCSVRecords(seriesBytes)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, seriesPath)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.Wrap(ErrorInvalidMetricsSeries("the series does not contain any samples"), seriesPath)
+	}
+
+	// relative times are offsets from the start of the simulation
+	start := time.Now().Truncate(time.Second)
+
+	samples := make([]autoscaler.MetricsSample, len(records))
+	for i, record := range records {
+		sample, err := metricsSample(record, start)
+		if err != nil {
+			return nil, errors.Wrap(err, seriesPath, fmt.Sprintf("sample %d", i+1))
+		}
+		samples[i] = *sample
+	}
+
+	return samples, nil
+}
+
+func metricsSeriesCSVRecords(seriesBytes []byte) ([]map[string]string, error) {
+	rows, err := csv.NewReader(bytes.NewReader(seriesBytes)).ReadAll()
+	if err != nil {
+		return nil, ErrorInvalidMetricsSeries(err.Error())
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	records := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(row) {
+				record[strings.TrimSpace(column)] = strings.TrimSpace(row[i])
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func metricsSeriesJSONRecords(seriesBytes []byte) ([]map[string]string, error) {
+	var objs []map[string]interface{}
+	if err := libjson.Unmarshal(seriesBytes, &objs); err != nil {
+		return nil, ErrorInvalidMetricsSeries("the series must be a list of objects")
+	}
+
+	records := make([]map[string]string, 0, len(objs))
+	for _, obj := range objs {
+		record := make(map[string]string, len(obj))
+		for key, val := range obj {
+			switch typedVal := val.(type) {
+			case string:
+				record[key] = typedVal
+			case float64:
+				record[key] = strconv.FormatFloat(typedVal, 'f', -1, 64)
+			case nil:
+			default:
+				return nil, ErrorInvalidMetricsSeries(fmt.Sprintf("%s must be a string or a number", key))
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func metricsSample(record map[string]string, start time.Time) (*autoscaler.MetricsSample, error) {
+	sample := autoscaler.MetricsSample{}
+
+	timeStr := record[_seriesTimeColumn]
+	if timeStr == "" {
+		return nil, ErrorInvalidMetricsSeries(fmt.Sprintf("%s must be specified", _seriesTimeColumn))
+	}
+	if seconds, err := strconv.ParseFloat(timeStr, 64); err == nil {
+		sample.Time = start.Add(time.Duration(seconds * float64(time.Second)))
+	} else if timestamp, err := time.Parse(time.RFC3339, timeStr); err == nil {
+		sample.Time = timestamp
+	} else {
+		return nil, ErrorInvalidMetricsSeries(fmt.Sprintf("%s must be an RFC 3339 timestamp or a number of seconds (got %s)", _seriesTimeColumn, timeStr))
+	}
+
+	inFlight, err := float64Column(record, _seriesInFlightColumn)
+	if err != nil {
+		return nil, err
+	}
+	if inFlight == nil {
+		return nil, ErrorInvalidMetricsSeries(fmt.Sprintf("%s must be specified", _seriesInFlightColumn))
+	}
+	sample.InFlight = *inFlight
+
+	if sample.P90Latency, err = float64Column(record, _seriesP90LatencyColumn); err != nil {
+		return nil, err
+	}
+	if sample.RequestRate, err = float64Column(record, _seriesRequestRateColumn); err != nil {
+		return nil, err
+	}
+
+	return &sample, nil
+}
+
+// Returns nil if the column is missing or empty
+func float64Column(record map[string]string, column string) (*float64, error) {
+	valStr := record[column]
+	if valStr == "" {
+		return nil, nil
+	}
+
+	val, err := strconv.ParseFloat(valStr, 64)
+	if err != nil || val < 0 {
+		return nil, ErrorInvalidMetricsSeries(fmt.Sprintf("%s must be a non-negative number (got %s)", column, valStr))
+	}
+
+	return &val, nil
+}
+
+func exportAutoscalerTicks(ticks []schema.AutoscalerTick, outPath string) error {
+	if strings.HasSuffix(strings.ToLower(outPath), ".json") {
+		return libjson.WriteJSON(ticks, outPath)
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"time", "current_replicas", "in_flight", "p90_latency", "request_rate", "raw_recommendation", "recommendation", "request", "message"})
+	for _, tick := range ticks {
+		writer.Write([]string{
+			tick.Timestamp.Format(time.RFC3339),
+			s.Int32(tick.CurrentReplicas),
+			csvFloat64Ptr(tick.AvgInFlight),
+			csvFloat64Ptr(tick.P90Latency),
+			csvFloat64Ptr(tick.RequestRate),
+			csvFloat64Ptr(tick.RawRecommendation),
+			csvInt32Ptr(tick.Recommendation),
+			csvInt32Ptr(tick.Request),
+			tick.Message,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return files.WriteFile(buf.Bytes(), outPath)
+}
+
+func csvFloat64Ptr(val *float64) string {
+	if val == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*val, 'f', -1, 64)
+}
+
+func csvInt32Ptr(val *int32) string {
+	if val == nil {
+		return ""
+	}
+	return s.Int32(*val)
+}
+
+func simulationStr(ticks []schema.AutoscalerTick, showAll bool) string {
+	minReplicas, maxReplicas := ticks[0].CurrentReplicas, ticks[0].CurrentReplicas
+	replicaSeconds := 0.0
+	var scalingEvents []schema.AutoscalerTick
+
+	for i, tick := range ticks {
+		if tick.CurrentReplicas < minReplicas {
+			minReplicas = tick.CurrentReplicas
+		}
+		if tick.CurrentReplicas > maxReplicas {
+			maxReplicas = tick.CurrentReplicas
+		}
+		if i+1 < len(ticks) {
+			replicaSeconds += float64(tick.CurrentReplicas) * ticks[i+1].Timestamp.Sub(tick.Timestamp).Seconds()
+		}
+		if tick.Request != nil && *tick.Request != tick.CurrentReplicas {
+			scalingEvents = append(scalingEvents, tick)
+		}
+	}
+
+	out := fmt.Sprintf("simulated %d %s over %s\n", len(ticks), s.PluralS("autoscaler decision", len(ticks)), ticks[len(ticks)-1].Timestamp.Sub(ticks[0].Timestamp))
+	out += fmt.Sprintf("replicas ranged from %d to %d (%s replica-hours)\n", minReplicas, maxReplicas, s.Round(replicaSeconds/3600, 2, 0))
+
+	if showAll {
+		t := autoscalerTicksTable(ticks)
+		return out + "\n" + t.MustFormat()
+	}
+
+	if len(scalingEvents) == 0 {
+		return out + "\nno scaling events occurred\n"
+	}
+
+	out += fmt.Sprintf("%d %s occurred\n\n", len(scalingEvents), s.PluralS("scaling event", len(scalingEvents)))
+	t := autoscalerTicksTable(scalingEvents)
+	return out + t.MustFormat()
+}
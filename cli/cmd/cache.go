@@ -0,0 +1,161 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/prompt"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/spf13/cobra"
+)
+
+var _flagCacheDisallowPrompt bool
+
+func cacheInit() {
+	_cacheListCmd.Flags().SortFlags = false
+	_cacheCmd.AddCommand(_cacheListCmd)
+
+	_cachePruneCmd.Flags().SortFlags = false
+	_cacheCmd.AddCommand(_cachePruneCmd)
+
+	_cacheClearCmd.Flags().SortFlags = false
+	_cacheClearCmd.Flags().BoolVarP(&_flagCacheDisallowPrompt, "yes", "y", false, "skip prompts")
+	_cacheCmd.AddCommand(_cacheClearCmd)
+}
+
+var _cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "manage the local model cache",
+}
+
+var _cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the models in the local model cache",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cache.list")
+
+		cachedModels, err := local.ListCachedModels()
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if len(cachedModels) == 0 {
+			fmt.Println(console.Bold("the model cache is empty"))
+			return
+		}
+
+		var totalSize int64
+		rows := make([][]interface{}, 0, len(cachedModels))
+		for _, cachedModel := range cachedModels {
+			lastUsed := cachedModel.LastUsed
+			apiNames := "-"
+			if len(cachedModel.APINames) > 0 {
+				apiNames = strings.Join(cachedModel.APINames, ", ")
+			}
+			rows = append(rows, []interface{}{
+				cachedModel.ID,
+				s.Int64ToBase2Byte(cachedModel.Size),
+				libtime.SinceStr(&lastUsed),
+				apiNames,
+			})
+			totalSize += cachedModel.Size
+		}
+
+		t := table.Table{
+			Headers: []table.Header{
+				{Title: "model id"},
+				{Title: "size"},
+				{Title: "last used"},
+				{Title: "apis", MaxWidth: 50},
+			},
+			Rows: rows,
+		}
+
+		fmt.Print(t.MustFormat())
+		fmt.Printf("\n%s %s\n", console.Bold("total size:"), s.Int64ToBase2Byte(totalSize))
+	},
+}
+
+var _cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "delete the cached models which aren't used by any local api",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cache.prune")
+
+		prunedModelIDs, err := local.PruneCachedModels()
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if len(prunedModelIDs) == 0 {
+			print.BoldFirstLine("there are no cached models to prune")
+			return
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("deleted %d cached %s", len(prunedModelIDs), s.PluralS("model", len(prunedModelIDs))))
+	},
+}
+
+var _cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "delete all cached models (and the local apis which use them)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		telemetry.Event("cli.cache.clear")
+
+		cachedModels, err := local.ListCachedModels()
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiNames := strset.New()
+		for _, cachedModel := range cachedModels {
+			apiNames.Add(cachedModel.APINames...)
+		}
+
+		if len(apiNames) > 0 {
+			if !_flagCacheDisallowPrompt {
+				prompt.YesOrExit(fmt.Sprintf("%s %s cached models, so %s will be deleted; are you sure you want to continue?", s.StrsAnd(apiNames.SliceSorted()), s.PluralCustom("uses", "use", len(apiNames)), s.PluralCustom("it", "they", len(apiNames))), "", "")
+			}
+
+			for _, apiName := range apiNames.SliceSorted() {
+				if _, err := local.Delete(apiName, false, true); err != nil {
+					exit.Error(err)
+				}
+				fmt.Printf("deleted %s\n", apiName)
+			}
+		}
+
+		if err := local.ClearModelCache(); err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine("cleared the model cache")
+	},
+}
@@ -17,8 +17,6 @@ limitations under the License.
 package cmd
 
 import (
-	"fmt"
-
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
@@ -26,6 +24,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/spf13/cobra"
 )
 
@@ -76,13 +75,16 @@ var _deleteCmd = &cobra.Command{
 			}
 		} else {
 			if len(args) == 2 {
-				exit.Error(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot delete job %s for api %s", args[1], args[0]))
-			}
-
-			// local only supports deploying 1 replica at a time, so _flagDeleteForce is only useful when attempting to delete an API that has been deployed with different CLI version
-			deleteResponse, err = local.Delete(args[0], _flagDeleteKeepCache, _flagDeleteForce)
-			if err != nil {
-				exit.Error(err)
+				deleteResponse, err = local.StopJob(spec.JobKey{ID: args[1], APIName: args[0]})
+				if err != nil {
+					exit.Error(err)
+				}
+			} else {
+				// local only supports deploying 1 replica at a time, so _flagDeleteForce is only useful when attempting to delete an API that has been deployed with different CLI version
+				deleteResponse, err = local.Delete(args[0], _flagDeleteKeepCache, _flagDeleteForce)
+				if err != nil {
+					exit.Error(err)
+				}
 			}
 		}
 
@@ -23,6 +23,7 @@ import (
 
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
@@ -51,6 +52,8 @@ var (
 	_flagDeployEnv            string
 	_flagDeployForce          bool
 	_flagDeployDisallowPrompt bool
+	_flagDeployDryRun         bool
+	_flagDeployWatch          bool
 )
 
 func deployInit() {
@@ -58,6 +61,8 @@ func deployInit() {
 	_deployCmd.Flags().StringVarP(&_flagDeployEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
 	_deployCmd.Flags().BoolVarP(&_flagDeployForce, "force", "f", false, "override the in-progress api update")
 	_deployCmd.Flags().BoolVarP(&_flagDeployDisallowPrompt, "yes", "y", false, "skip prompts")
+	_deployCmd.Flags().BoolVar(&_flagDeployDryRun, "dry-run", false, "validate the configuration and show the changes which would be made, without deploying")
+	_deployCmd.Flags().BoolVarP(&_flagDeployWatch, "watch", "w", false, "re-deploy the apis whenever project files change (local environment only)")
 }
 
 var _deployCmd = &cobra.Command{
@@ -87,6 +92,14 @@ var _deployCmd = &cobra.Command{
 			exit.Error(ErrorDeployFromTopLevelDir("root", env.Provider))
 		}
 
+		if _flagDeployDryRun && env.Provider == types.LocalProviderType {
+			exit.Error(errors.Wrap(ErrorNotSupportedInLocalEnvironment(), "--dry-run"))
+		}
+
+		if _flagDeployWatch && env.Provider != types.LocalProviderType {
+			exit.Error(ErrorFlagOnlySupportedInLocalEnvironment("watch"))
+		}
+
 		var deployResponse schema.DeployResponse
 		if env.Provider == types.AWSProviderType {
 			deploymentBytes, err := getDeploymentBytes(env.Provider, configPath)
@@ -94,7 +107,7 @@ var _deployCmd = &cobra.Command{
 				exit.Error(err)
 			}
 
-			deployResponse, err = cluster.Deploy(MustGetOperatorConfig(env.Name), configPath, deploymentBytes, _flagDeployForce)
+			deployResponse, err = cluster.Deploy(MustGetOperatorConfig(env.Name), configPath, deploymentBytes, _flagDeployForce, _flagDeployDryRun)
 			if err != nil {
 				exit.Error(err)
 			}
@@ -104,11 +117,31 @@ var _deployCmd = &cobra.Command{
 				exit.Error(err)
 			}
 
-			deployResponse, err = local.Deploy(env, configPath, projectFiles, _flagDeployDisallowPrompt)
+			cliConfig, err := readCLIConfig()
 			if err != nil {
 				exit.Error(err)
 			}
+
+			deployResponse, err = local.Deploy(env, configPath, projectFiles, _flagDeployDisallowPrompt, cliConfig.ModelCacheMaxSize)
+			if err != nil {
+				if !_flagDeployWatch {
+					exit.Error(err)
+				}
+				// the project will be deployed once it's fixed
+				errors.PrintError(err)
+				watchLocalDeployment(env, configPath, cliConfig.ModelCacheMaxSize, false)
+			}
+
+			if _flagDeployWatch {
+				print.BoldFirstBlock(deployMessage(deployResponse.Results, env.Name))
+				watchLocalDeployment(env, configPath, cliConfig.ModelCacheMaxSize, !didAnyResultsError(deployResponse.Results))
+			}
 		}
+		if _flagDeployDryRun {
+			fmt.Print(dryRunMessage(deployResponse.Results))
+			return
+		}
+
 		message := deployMessage(deployResponse.Results, env.Name)
 		print.BoldFirstBlock(message)
 	},
@@ -240,6 +273,43 @@ func deployMessage(results []schema.DeployResult, envName string) string {
 	return statusMessage + "\n\n" + apiCommandsMessage
 }
 
+func dryRunMessage(results []schema.DeployResult) string {
+	var out string
+
+	for _, result := range results {
+		if result.Error != "" {
+			out += console.Bold(result.Error) + "\n\n"
+			continue
+		}
+
+		out += console.Bold(result.Message) + "\n"
+		if result.Plan == nil || len(result.Plan.Diff) == 0 {
+			out += "\n"
+			continue
+		}
+
+		rows := make([][]interface{}, len(result.Plan.Diff))
+		for i, fieldDiff := range result.Plan.Diff {
+			rows[i] = []interface{}{fieldDiff.Field, fieldDiff.Prev, fieldDiff.New}
+		}
+		t := table.Table{
+			Headers: []table.Header{
+				{Title: "field"},
+				{Title: "current", MaxWidth: 50},
+				{Title: "new", MaxWidth: 50},
+			},
+			Rows: rows,
+		}
+		out += "\n" + t.MustFormat() + "\n"
+	}
+
+	if didAllResultsError(results) {
+		return out
+	}
+
+	return out + "no changes have been made (run `cortex deploy` without --dry-run to apply them)\n"
+}
+
 func mergeResultMessages(results []schema.DeployResult) string {
 	var okMessages []string
 	var errMessages []string
@@ -266,6 +336,15 @@ func didAllResultsError(results []schema.DeployResult) bool {
 	return true
 }
 
+func didAnyResultsError(results []schema.DeployResult) bool {
+	for _, result := range results {
+		if result.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func getAPICommandsMessage(results []schema.DeployResult, envName string) string {
 	apiName := "<api_name>"
 	if len(results) == 1 {
@@ -81,6 +81,10 @@ const (
 	ErrShellCompletionNotSupported          = "cli.shell_completion_not_supported"
 	ErrNoTerminalWidth                      = "cli.no_terminal_width"
 	ErrDeployFromTopLevelDir                = "cli.deploy_from_top_level_dir"
+	ErrFlagRequiresSingleAPIName            = "cli.flag_requires_single_api_name"
+	ErrFlagOnlySupportedInLocalEnvironment  = "cli.flag_only_supported_in_local_environment"
+	ErrSpecifyAPIToSimulate                 = "cli.specify_api_to_simulate"
+	ErrInvalidMetricsSeries                 = "cli.invalid_metrics_series"
 )
 
 func ErrorInvalidProvider(providerStr string) error {
@@ -350,3 +354,41 @@ func ErrorDeployFromTopLevelDir(genericDirName string, providerType types.Provid
 		Message: fmt.Sprintf("cannot deploy from your %s directory - when deploying your API, cortex sends all files in your project directory (i.e. the directory which contains cortex.yaml) to your %s (see https://docs.cortex.dev/v/%s/deployments/syncapi/predictors#project-files for Sync API and https://docs.cortex.dev/v/%s/deployments/batchapi/predictors#project-files for Batch API); therefore it is recommended to create a subdirectory for your project files", genericDirName, targetStr, consts.CortexVersionMinor, consts.CortexVersionMinor),
 	})
 }
+
+func ErrorFlagRequiresSingleAPIName(flag string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFlagRequiresSingleAPIName,
+		Message: fmt.Sprintf("the --%s flag can only be used when an api name is specified (e.g. `cortex get API_NAME --%s`)", flag, flag),
+	})
+}
+
+func ErrorFlagOnlySupportedInLocalEnvironment(flag string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFlagOnlySupportedInLocalEnvironment,
+		Message: fmt.Sprintf("the --%s flag is only supported in the local environment", flag),
+	})
+}
+
+func ErrorSpecifyAPIToSimulate(configPath string, apiName string, syncAPINames []string) error {
+	var message string
+	switch {
+	case len(syncAPINames) == 0:
+		message = fmt.Sprintf("%s does not contain any apis of kind %s", configPath, userconfig.SyncAPIKind)
+	case apiName != "":
+		message = fmt.Sprintf("%s does not contain an api of kind %s named \"%s\" (%s: %s)", configPath, userconfig.SyncAPIKind, apiName, s.PluralCustom("api", "apis", len(syncAPINames)), strings.Join(syncAPINames, ", "))
+	default:
+		message = fmt.Sprintf("%s contains multiple apis of kind %s; please specify which one to simulate with the --api flag (%s)", configPath, userconfig.SyncAPIKind, strings.Join(syncAPINames, ", "))
+	}
+
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrSpecifyAPIToSimulate,
+		Message: message,
+	})
+}
+
+func ErrorInvalidMetricsSeries(reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidMetricsSeries,
+		Message: fmt.Sprintf("invalid metrics series: %s (see https://docs.cortex.dev/v/%s/deployments/syncapi/autoscaling#simulating-the-autoscaler)", reason, consts.CortexVersionMinor),
+	})
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/spf13/cobra"
+)
+
+var _flagGatewayEnv string
+
+func gatewayInit() {
+	_gatewayCmd.Flags().StringVarP(&_flagGatewayEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+}
+
+// serves the endpoint of a BatchAPI or APISplitter in the local environment; it's started in the background by `cortex deploy`
+var _gatewayCmd = &cobra.Command{
+	Use:    "_gateway API_NAME",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := readEnv(_flagGatewayEnv)
+		if err != nil {
+			exit.Error(err)
+		}
+		if env == nil {
+			exit.Error(ErrorEnvironmentNotFound(_flagGatewayEnv))
+		}
+
+		err = local.ServeGateway(args[0], *env)
+		if err != nil {
+			exit.Error(err)
+		}
+	},
+}
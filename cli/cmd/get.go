@@ -19,6 +19,7 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
 	"github.com/cortexlabs/cortex/cli/local"
@@ -51,14 +52,26 @@ const (
 )
 
 var (
-	_flagGetEnv string
-	_flagWatch  bool
+	_flagGetEnv         string
+	_flagWatch          bool
+	_flagGetAutoscaling bool
+	_flagGetJobs        bool
+	_flagGetJobStatus   string
+	_flagGetJobsSince   string
+	_flagGetJobsLimit   int
+	_flagGetWindow      string
 )
 
 func getInit() {
 	_getCmd.Flags().SortFlags = false
 	_getCmd.Flags().StringVarP(&_flagGetEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
 	_getCmd.Flags().BoolVarP(&_flagWatch, "watch", "w", false, "re-run the command every 2 seconds")
+	_getCmd.Flags().BoolVar(&_flagGetAutoscaling, "autoscaling", false, "show the most recent autoscaling decisions for a sync api")
+	_getCmd.Flags().BoolVar(&_flagGetJobs, "jobs", false, "list the jobs of a batch api (most recently submitted first)")
+	_getCmd.Flags().StringVar(&_flagGetJobStatus, "status", "", "only list jobs with the given status(es), e.g. failed, in_progress, or worker_error (comma-separated; implies --jobs)")
+	_getCmd.Flags().StringVar(&_flagGetJobsSince, "since", "", "only list jobs submitted within the given duration, e.g. 24h (implies --jobs)")
+	_getCmd.Flags().IntVar(&_flagGetJobsLimit, "limit", 20, "the maximum number of jobs to list")
+	_getCmd.Flags().StringVar(&_flagGetWindow, "window", "", "only aggregate metrics which were recorded within the given duration, e.g. 5m (local environment only)")
 }
 
 var _getCmd = &cobra.Command{
@@ -78,6 +91,39 @@ var _getCmd = &cobra.Command{
 			telemetry.Event("cli.get")
 		}
 
+		if _flagGetAutoscaling && len(args) != 1 {
+			exit.Error(ErrorFlagRequiresSingleAPIName("autoscaling"))
+		}
+
+		listJobsFlag := ""
+		for _, flag := range []string{"jobs", "status", "since"} {
+			if cmd.Flags().Changed(flag) {
+				listJobsFlag = flag
+				break
+			}
+		}
+		if listJobsFlag != "" && len(args) != 1 {
+			exit.Error(ErrorFlagRequiresSingleAPIName(listJobsFlag))
+		}
+
+		var jobsSince time.Duration
+		if _flagGetJobsSince != "" {
+			var err error
+			jobsSince, err = time.ParseDuration(_flagGetJobsSince)
+			if err != nil {
+				exit.Error(errors.Wrap(errors.WithStack(err), "--since"))
+			}
+		}
+
+		var metricsWindow time.Duration
+		if _flagGetWindow != "" {
+			var err error
+			metricsWindow, err = time.ParseDuration(_flagGetWindow)
+			if err != nil {
+				exit.Error(errors.Wrap(errors.WithStack(err), "--window"))
+			}
+		}
+
 		rerun(func() (string, error) {
 			if len(args) == 1 {
 				env, err := ReadOrConfigureEnv(_flagGetEnv)
@@ -89,7 +135,34 @@ var _getCmd = &cobra.Command{
 				if err != nil {
 					return "", err
 				}
-				apiTable, err := getAPI(env, args[0])
+
+				if _flagGetAutoscaling {
+					if env.Provider == types.LocalProviderType {
+						return "", errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot get autoscaling decisions for api %s", args[0]))
+					}
+					autoscalingTable, err := getAutoscaling(env, args[0])
+					if err != nil {
+						return "", err
+					}
+					return out + autoscalingTable, nil
+				}
+
+				if listJobsFlag != "" {
+					if env.Provider == types.LocalProviderType {
+						return "", errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot list jobs for api %s", args[0]))
+					}
+					jobsTable, err := listJobs(env, args[0], _flagGetJobStatus, jobsSince, _flagGetJobsLimit)
+					if err != nil {
+						return "", err
+					}
+					return out + jobsTable, nil
+				}
+
+				if _flagGetWindow != "" && env.Provider != types.LocalProviderType {
+					return "", ErrorFlagOnlySupportedInLocalEnvironment("window")
+				}
+
+				apiTable, err := getAPI(env, args[0], metricsWindow)
 				if err != nil {
 					return "", err
 				}
@@ -105,10 +178,6 @@ var _getCmd = &cobra.Command{
 					return "", err
 				}
 
-				if env.Provider == types.LocalProviderType {
-					return "", errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot get status of job %s for api %s", args[1], args[0]))
-				}
-
 				apiTable, err := getJob(env, args[0], args[1])
 				if err != nil {
 					return "", err
@@ -126,14 +195,18 @@ var _getCmd = &cobra.Command{
 						return "", err
 					}
 
-					apiTable, err := getAPIsByEnv(env, false)
+					if _flagGetWindow != "" && env.Provider != types.LocalProviderType {
+						return "", ErrorFlagOnlySupportedInLocalEnvironment("window")
+					}
+
+					apiTable, err := getAPIsByEnv(env, false, metricsWindow)
 					if err != nil {
 						return "", err
 					}
 					return out + apiTable, nil
 				}
 
-				out, err := getAPIsInAllEnvironments()
+				out, err := getAPIsInAllEnvironments(metricsWindow)
 				if err != nil {
 					return "", err
 				}
@@ -144,7 +217,7 @@ var _getCmd = &cobra.Command{
 	},
 }
 
-func getAPIsInAllEnvironments() (string, error) {
+func getAPIsInAllEnvironments(metricsWindow time.Duration) (string, error) {
 	cliConfig, err := readCLIConfig()
 	if err != nil {
 		return "", err
@@ -165,7 +238,7 @@ func getAPIsInAllEnvironments() (string, error) {
 		if env.Provider == types.AWSProviderType {
 			apisRes, err = cluster.GetAPIs(MustGetOperatorConfig(env.Name))
 		} else {
-			apisRes, err = local.GetAPIs()
+			apisRes, err = local.GetAPIs(metricsWindow)
 		}
 
 		if err == nil {
@@ -251,7 +324,7 @@ func hideReplicaCountColumns(t *table.Table) {
 	t.FindHeaderByTitle(_titleFailed).Hidden = true
 }
 
-func getAPIsByEnv(env cliconfig.Environment, printEnv bool) (string, error) {
+func getAPIsByEnv(env cliconfig.Environment, printEnv bool, metricsWindow time.Duration) (string, error) {
 	var apisRes schema.GetAPIsResponse
 	var err error
 
@@ -261,7 +334,7 @@ func getAPIsByEnv(env cliconfig.Environment, printEnv bool) (string, error) {
 			return "", err
 		}
 	} else {
-		apisRes, err = local.GetAPIs()
+		apisRes, err = local.GetAPIs(metricsWindow)
 		if err != nil {
 			return "", err
 		}
@@ -346,7 +419,7 @@ func getLocalVersionMismatchedAPIsMessage() (string, error) {
 	return fmt.Sprintf("apis named %s were deployed in your local environment using a different version of the cortex cli; please delete them using `cortex delete API_NAME` and then redeploy them\n", s.UserStrsAnd(mismatchedAPINames)), nil
 }
 
-func getAPI(env cliconfig.Environment, apiName string) (string, error) {
+func getAPI(env cliconfig.Environment, apiName string, metricsWindow time.Duration) (string, error) {
 	if env.Provider == types.AWSProviderType {
 		apiRes, err := cluster.GetAPI(MustGetOperatorConfig(env.Name), apiName)
 		if err != nil {
@@ -361,12 +434,12 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 			return syncAPITable(apiRes.SyncAPI, env)
 		}
 		if apiRes.APISplitter != nil {
-			return apiSplitterTable(apiRes.APISplitter, env)
+			return apiSplitterTable(apiRes.APISplitter, env, metricsWindow)
 		}
 		return batchAPITable(*apiRes.BatchAPI), nil
 	}
 
-	apiRes, err := local.GetAPI(apiName)
+	apiRes, err := local.GetAPI(apiName, metricsWindow)
 	if err != nil {
 		// note: if modifying this string, search the codebase for it and change all occurrences
 		if strings.HasSuffix(errors.Message(err), "is not deployed") {
@@ -375,6 +448,12 @@ func getAPI(env cliconfig.Environment, apiName string) (string, error) {
 		return "", err
 	}
 
+	if apiRes.BatchAPI != nil {
+		return batchAPITable(*apiRes.BatchAPI), nil
+	}
+	if apiRes.APISplitter != nil {
+		return apiSplitterTable(apiRes.APISplitter, env, metricsWindow)
+	}
 	return syncAPITable(apiRes.SyncAPI, env)
 }
 
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	_titleAPIID         = "api id"
+	_titleDeployed      = "deployed"
+	_titleProjectID     = "project id"
+	_titleCortexVersion = "cortex version"
+	_titleCurrent       = "current"
+)
+
+var (
+	_flagHistoryEnv string
+)
+
+func historyInit() {
+	_historyCmd.Flags().SortFlags = false
+	_historyCmd.Flags().StringVarP(&_flagHistoryEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+}
+
+var _historyCmd = &cobra.Command{
+	Use:   "history API_NAME",
+	Short: "list the deployed versions of an api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagHistoryEnv)
+		if err != nil {
+			telemetry.Event("cli.history")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.history", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		err = printEnvIfNotSpecified(_flagHistoryEnv, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if env.Provider == types.LocalProviderType {
+			exit.Error(errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot get the history of api %s", args[0])))
+		}
+
+		historyRes, err := cluster.GetHistory(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("%s has been deployed %d %s", historyRes.APIName, len(historyRes.History), s.PluralS("time", len(historyRes.History))))
+		fmt.Println()
+		t := apiHistoryTable(historyRes.History)
+		fmt.Print(t.MustFormat())
+		fmt.Println()
+		fmt.Println(console.Bold("to roll back:") + fmt.Sprintf(" cortex rollback %s [--to API_ID]", historyRes.APIName))
+	},
+}
+
+// the most recently deployed version is listed first
+func apiHistoryTable(history []schema.APIVersion) table.Table {
+	rows := make([][]interface{}, 0, len(history))
+
+	for i := len(history) - 1; i >= 0; i-- {
+		version := history[i]
+
+		current := ""
+		if i == len(history)-1 {
+			current = "*"
+		}
+
+		rows = append(rows, []interface{}{
+			version.APIID,
+			version.DeployedAt.Local().Format(_timeFormat),
+			version.ProjectID,
+			version.CortexVersion,
+			current,
+		})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: _titleAPIID},
+			{Title: _titleDeployed},
+			{Title: _titleProjectID},
+			{Title: _titleCortexVersion},
+			{Title: _titleCurrent},
+		},
+		Rows: rows,
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagFailedBatchesEnv      string
+	_flagFailedBatchesOutput   string
+	_flagFailedBatchesResubmit bool
+)
+
+func jobInit() {
+	_jobFailedBatchesCmd.Flags().SortFlags = false
+	_jobFailedBatchesCmd.Flags().StringVarP(&_flagFailedBatchesEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_jobFailedBatchesCmd.Flags().StringVarP(&_flagFailedBatchesOutput, "output", "o", "", "export the failed batches (including their payloads) to a json file")
+	_jobFailedBatchesCmd.Flags().BoolVar(&_flagFailedBatchesResubmit, "resubmit", false, "submit a new job with the same configuration whose items are the items of the failed batches")
+	_jobCmd.AddCommand(_jobFailedBatchesCmd)
+}
+
+var _jobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "inspect batch jobs",
+}
+
+var _jobFailedBatchesCmd = &cobra.Command{
+	Use:   "failed-batches API_NAME JOB_ID",
+	Short: "list the batches of a job which failed on every attempt",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagFailedBatchesEnv)
+		if err != nil {
+			telemetry.Event("cli.job.failed-batches")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.job.failed-batches", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		err = printEnvIfNotSpecified(_flagFailedBatchesEnv, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		apiName := args[0]
+		jobID := args[1]
+
+		if env.Provider == types.LocalProviderType {
+			exit.Error(errors.Wrap(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot get the failed batches of job %s for api %s", jobID, apiName)))
+		}
+
+		if _flagFailedBatchesResubmit {
+			jobSpec, err := cluster.ResubmitFailedBatches(MustGetOperatorConfig(env.Name), apiName, jobID)
+			if err != nil {
+				exit.Error(err)
+			}
+			print.BoldFirstLine(fmt.Sprintf("submitted job %s with %d %s (the failed batches of job %s)", jobSpec.ID, jobSpec.TotalBatchCount, s.PluralEs("batch", jobSpec.TotalBatchCount), jobID))
+			fmt.Println()
+			fmt.Println(console.Bold("to check its status:") + fmt.Sprintf(" cortex get %s %s", apiName, jobSpec.ID))
+			return
+		}
+
+		failedBatchesRes, err := cluster.GetFailedBatches(MustGetOperatorConfig(env.Name), apiName, jobID)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if _flagFailedBatchesOutput != "" {
+			outputPath := files.RelToAbsPath(_flagFailedBatchesOutput, _cwd)
+			if err := libjson.WriteJSON(failedBatchesRes.Batches, outputPath); err != nil {
+				exit.Error(err)
+			}
+			print.BoldFirstLine(fmt.Sprintf("exported %d failed %s to %s", len(failedBatchesRes.Batches), s.PluralEs("batch", len(failedBatchesRes.Batches)), _flagFailedBatchesOutput))
+			return
+		}
+
+		if len(failedBatchesRes.Batches) == 0 {
+			print.BoldFirstLine(fmt.Sprintf("job %s does not have any failed batches", jobID))
+			return
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("job %s has %d failed %s", jobID, len(failedBatchesRes.Batches), s.PluralEs("batch", len(failedBatchesRes.Batches))))
+		fmt.Println()
+		t := failedBatchesTable(failedBatchesRes.Batches)
+		fmt.Print(t.MustFormat())
+		fmt.Println()
+		fmt.Println(console.Bold("to export the payloads:") + fmt.Sprintf(" cortex job failed-batches %s %s --output FILE", apiName, jobID))
+		fmt.Println(console.Bold("to retry them in a new job:") + fmt.Sprintf(" cortex job failed-batches %s %s --resubmit", apiName, jobID))
+	},
+}
+
+func failedBatchesTable(failedBatches []schema.FailedBatch) table.Table {
+	rows := make([][]interface{}, 0, len(failedBatches))
+
+	for _, failedBatch := range failedBatches {
+		failedAt := "-"
+		if failedBatch.FailedAt != nil {
+			failedAt = failedBatch.FailedAt.Local().Format(_timeFormat)
+		}
+
+		rows = append(rows, []interface{}{
+			failedBatch.BatchID,
+			failedAt,
+			failedBatch.Attempts,
+			failedBatch.Error,
+		})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "batch id"},
+			{Title: "failed at"},
+			{Title: "attempts"},
+			{Title: "error", MaxWidth: 80},
+		},
+		Rows: rows,
+	}
+}
@@ -22,12 +22,14 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
 )
 
 const (
@@ -36,12 +38,12 @@ const (
 	_titleAPIs          = "apis"
 )
 
-func apiSplitterTable(apiSplitter *schema.APISplitter, env cliconfig.Environment) (string, error) {
+func apiSplitterTable(apiSplitter *schema.APISplitter, env cliconfig.Environment, metricsWindow time.Duration) (string, error) {
 	var out string
 
 	lastUpdated := time.Unix(apiSplitter.Spec.LastUpdated, 0)
 
-	t, err := trafficSplitTable(*apiSplitter, env)
+	t, err := trafficSplitTable(*apiSplitter, env, metricsWindow)
 	if err != nil {
 		return "", err
 	}
@@ -58,11 +60,17 @@ func apiSplitterTable(apiSplitter *schema.APISplitter, env cliconfig.Environment
 	return out, nil
 }
 
-func trafficSplitTable(apiSplitter schema.APISplitter, env cliconfig.Environment) (table.Table, error) {
+func trafficSplitTable(apiSplitter schema.APISplitter, env cliconfig.Environment, metricsWindow time.Duration) (table.Table, error) {
 	rows := make([][]interface{}, 0, len(apiSplitter.Spec.APIs))
 
 	for _, api := range apiSplitter.Spec.APIs {
-		apiRes, err := cluster.GetAPI(MustGetOperatorConfig(env.Name), api.Name)
+		var apiRes schema.GetAPIResponse
+		var err error
+		if env.Provider == types.LocalProviderType {
+			apiRes, err = local.GetAPI(api.Name, metricsWindow)
+		} else {
+			apiRes, err = cluster.GetAPI(MustGetOperatorConfig(env.Name), api.Name)
+		}
 		if err != nil {
 			return table.Table{}, err
 		}
@@ -86,7 +94,7 @@ func trafficSplitTable(apiSplitter schema.APISplitter, env cliconfig.Environment
 			{Title: _titleAPIs},
 			{Title: _apiSplitterWeights},
 			{Title: _titleStatus},
-			{Title: _titleRequested},
+			{Title: _titleRequested, Hidden: env.Provider == types.LocalProviderType},
 			{Title: _titleLastupdated},
 			{Title: _titleAvgRequest},
 			{Title: _title2XX},
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+const (
+	_maxAutoscalerTicksToShow = 30
+
+	_titleTime          = "time"
+	_titleReplicas      = "replicas"
+	_titleInFlight      = "in-flight"
+	_titleP90Latency    = "p90 latency"
+	_titleRequestRate   = "requests/s"
+	_titleRawRec        = "raw rec"
+	_titleTolerance     = "tolerance"
+	_titleFactorBounds  = "factor bounds"
+	_titleRecommended   = "rec"
+	_titleStabilization = "stabilization"
+	_titleSchedule      = "schedule"
+	_titleRequest       = "request"
+	_titleMessage       = "message"
+)
+
+func getAutoscaling(env cliconfig.Environment, apiName string) (string, error) {
+	autoscalingRes, err := cluster.GetAutoscaling(MustGetOperatorConfig(env.Name), apiName)
+	if err != nil {
+		return "", err
+	}
+
+	if len(autoscalingRes.Ticks) == 0 {
+		return console.Bold("the autoscaler hasn't made any decisions yet") + "\n", nil
+	}
+
+	ticks := autoscalingRes.Ticks
+	out := ""
+	if len(ticks) > _maxAutoscalerTicksToShow {
+		out += fmt.Sprintf("showing the %d most recent of %d autoscaling decisions\n\n", _maxAutoscalerTicksToShow, len(ticks))
+		ticks = ticks[len(ticks)-_maxAutoscalerTicksToShow:]
+	}
+
+	t := autoscalerTicksTable(ticks)
+	out += t.MustFormat()
+
+	return out, nil
+}
+
+func autoscalerTicksTable(ticks []schema.AutoscalerTick) table.Table {
+	rows := make([][]interface{}, 0, len(ticks))
+
+	hasP90Latency := false
+	hasRequestRate := false
+	hasSchedule := false
+	hasMessage := false
+
+	for _, tick := range ticks {
+		tolerance := ""
+		if tick.WithinTolerance {
+			tolerance = "held"
+		}
+
+		rows = append(rows, []interface{}{
+			tick.Timestamp.Local().Format(_timeFormat),
+			tick.CurrentReplicas,
+			float64PtrStr(tick.AvgInFlight),
+			float64PtrStr(tick.P90Latency),
+			float64PtrStr(tick.RequestRate),
+			float64PtrStr(tick.RawRecommendation),
+			tolerance,
+			int32RangeStr(tick.DownscaleFactorFloor, tick.UpscaleFactorCeil),
+			int32PtrStr(tick.Recommendation),
+			int32RangeStr(tick.DownscaleStabilizationFloor, tick.UpscaleStabilizationCeil),
+			int32RangeStr(tick.ScheduleMinReplicas, tick.ScheduleMaxReplicas),
+			int32PtrStr(tick.Request),
+			tick.Message,
+		})
+
+		hasP90Latency = hasP90Latency || tick.P90Latency != nil
+		hasRequestRate = hasRequestRate || tick.RequestRate != nil
+		hasSchedule = hasSchedule || tick.ScheduleMinReplicas != nil || tick.ScheduleMaxReplicas != nil
+		hasMessage = hasMessage || tick.Message != ""
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: _titleTime},
+			{Title: _titleReplicas},
+			{Title: _titleInFlight},
+			{Title: _titleP90Latency, Hidden: !hasP90Latency},
+			{Title: _titleRequestRate, Hidden: !hasRequestRate},
+			{Title: _titleRawRec},
+			{Title: _titleTolerance},
+			{Title: _titleFactorBounds},
+			{Title: _titleRecommended},
+			{Title: _titleStabilization},
+			{Title: _titleSchedule, Hidden: !hasSchedule},
+			{Title: _titleRequest},
+			{Title: _titleMessage, Hidden: !hasMessage, MaxWidth: 60},
+		},
+		Rows: rows,
+	}
+}
+
+func float64PtrStr(val *float64) string {
+	if val == nil {
+		return "-"
+	}
+	return s.Round(*val, 2, 0)
+}
+
+func int32PtrStr(val *int32) string {
+	if val == nil {
+		return "-"
+	}
+	return s.Int32(*val)
+}
+
+// e.g. "3 - 6", or "3 - " if there is no upper bound
+func int32RangeStr(min *int32, max *int32) string {
+	if min == nil && max == nil {
+		return "-"
+	}
+
+	minStr, maxStr := "", ""
+	if min != nil {
+		minStr = s.Int32(*min)
+	}
+	if max != nil {
+		maxStr = s.Int32(*max)
+	}
+	return fmt.Sprintf("%s - %s", minStr, maxStr)
+}
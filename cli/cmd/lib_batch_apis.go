@@ -18,18 +18,22 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/local"
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/pkg/lib/console"
 	"github.com/cortexlabs/cortex/pkg/lib/json"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/table"
 	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
 )
 
@@ -38,6 +42,10 @@ const (
 	_titleJobCount    = "running jobs"
 	_titleLatestJobID = "latest job id"
 	_timeFormat       = "02 Jan 2006 15:04:05 MST"
+	_maxJobsPageSize  = 100
+
+	_progressBarMaxWidth   = 50
+	_progressBarExtraChars = 30 // brackets, percentage, and counts
 )
 
 func batchAPIsTable(batchAPIs []schema.BatchAPI, envNames []string) table.Table {
@@ -82,63 +90,115 @@ func batchAPIsTable(batchAPIs []schema.BatchAPI, envNames []string) table.Table
 }
 
 func batchAPITable(batchAPI schema.BatchAPI) string {
-	jobRows := make([][]interface{}, 0, len(batchAPI.JobStatuses))
-
 	out := ""
 	if len(batchAPI.JobStatuses) == 0 {
 		out = console.Bold("no submitted jobs\n")
 	} else {
-		totalFailed := 0
-		for _, job := range batchAPI.JobStatuses {
-			succeeded := 0
-			failed := 0
+		t := jobStatusesTable(batchAPI.JobStatuses)
+		out += t.MustFormat()
+	}
 
-			if job.BatchMetrics != nil {
-				failed = job.BatchMetrics.Failed
-				succeeded = job.BatchMetrics.Succeeded
-				totalFailed += failed
-			}
+	out += "\n" + console.Bold("endpoint: ") + batchAPI.Endpoint
 
-			jobEndTime := time.Now()
-			if job.EndTime != nil {
-				jobEndTime = *job.EndTime
-			}
+	out += "\n" + titleStr("batch api configuration") + batchAPI.Spec.UserStr(types.AWSProviderType)
+	return out
+}
 
-			duration := jobEndTime.Sub(job.StartTime).Truncate(time.Second).String()
+func jobStatusesTable(jobStatuses []status.JobStatus) table.Table {
+	jobRows := make([][]interface{}, 0, len(jobStatuses))
 
-			jobRows = append(jobRows, []interface{}{
-				job.ID,
-				job.Status.Message(),
-				fmt.Sprintf("%d/%d", succeeded, job.TotalBatchCount),
-				failed,
-				job.StartTime.Format(_timeFormat),
-				duration,
-			})
+	totalFailed := 0
+	for _, job := range jobStatuses {
+		succeeded := 0
+		failed := 0
+
+		if job.BatchMetrics != nil {
+			failed = job.BatchMetrics.Failed
+			succeeded = job.BatchMetrics.Succeeded
+			totalFailed += failed
 		}
 
-		t := table.Table{
-			Headers: []table.Header{
-				{Title: "job id"},
-				{Title: "status"},
-				{Title: "progress"}, // (succeeded/total)
-				{Title: "failed", Hidden: totalFailed == 0},
-				{Title: "start time"},
-				{Title: "duration"},
-			},
-			Rows: jobRows,
+		jobEndTime := time.Now()
+		if job.EndTime != nil {
+			jobEndTime = *job.EndTime
 		}
 
-		out += t.MustFormat()
+		duration := jobEndTime.Sub(job.StartTime).Truncate(time.Second).String()
+
+		jobRows = append(jobRows, []interface{}{
+			job.ID,
+			job.Status.Message(),
+			fmt.Sprintf("%d/%d", succeeded, job.TotalBatchCount),
+			failed,
+			job.StartTime.Format(_timeFormat),
+			duration,
+		})
 	}
 
-	out += "\n" + console.Bold("endpoint: ") + batchAPI.Endpoint
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "job id"},
+			{Title: "status"},
+			{Title: "progress"}, // (succeeded/total)
+			{Title: "failed", Hidden: totalFailed == 0},
+			{Title: "start time"},
+			{Title: "duration"},
+		},
+		Rows: jobRows,
+	}
+}
 
-	out += "\n" + titleStr("batch api configuration") + batchAPI.Spec.UserStr(types.AWSProviderType)
-	return out
+// lists up to limit jobs, following the cursor of each page of jobs; since is ignored if it's 0
+func listJobs(env cliconfig.Environment, apiName string, statusFilter string, since time.Duration, limit int) (string, error) {
+	qParams := map[string]string{}
+	if statusFilter != "" {
+		qParams["status"] = statusFilter
+	}
+	if since != 0 {
+		qParams["submittedAfter"] = time.Now().Add(-since).UTC().Format(time.RFC3339)
+	}
+
+	var jobStatuses []status.JobStatus
+	hasMoreJobs := false
+	for len(jobStatuses) < limit {
+		qParams["pageSize"] = s.Int(libmath.MinInt(limit-len(jobStatuses), _maxJobsPageSize))
+
+		jobsRes, err := cluster.ListJobs(MustGetOperatorConfig(env.Name), apiName, qParams)
+		if err != nil {
+			return "", err
+		}
+		jobStatuses = append(jobStatuses, jobsRes.JobStatuses...)
+
+		if jobsRes.NextCursor == "" {
+			break
+		}
+		hasMoreJobs = true
+		qParams["cursor"] = jobsRes.NextCursor
+	}
+
+	if len(jobStatuses) == 0 {
+		return console.Bold("no jobs found") + "\n", nil
+	}
+
+	out := ""
+	if hasMoreJobs && len(jobStatuses) >= limit {
+		out += fmt.Sprintf("showing the %d most recently submitted jobs (use --limit to show more)\n\n", len(jobStatuses))
+	}
+
+	t := jobStatusesTable(jobStatuses)
+	out += t.MustFormat()
+
+	return out, nil
 }
 
 func getJob(env cliconfig.Environment, apiName string, jobID string) (string, error) {
-	resp, err := cluster.GetJob(MustGetOperatorConfig(env.Name), apiName, jobID)
+	var resp schema.GetJobResponse
+	var err error
+	if env.Provider == types.AWSProviderType {
+		resp, err = cluster.GetJob(MustGetOperatorConfig(env.Name), apiName, jobID)
+	} else {
+		resp, err = local.GetJob(spec.JobKey{ID: jobID, APIName: apiName})
+	}
 	if err != nil {
 		return "", err
 	}
@@ -150,6 +210,13 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 	jobIntroTable := table.KeyValuePairs{}
 	jobIntroTable.Add("job id", job.ID)
 	jobIntroTable.Add("status", job.Status.Message())
+	if len(job.DependsOn) > 0 {
+		dependencies := make([]string, len(job.DependsOn))
+		for i, dependency := range job.DependsOn {
+			dependencies[i] = dependency.UserString()
+		}
+		jobIntroTable.Add("depends on", strings.Join(dependencies, ", "))
+	}
 	out += jobIntroTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
 
 	jobTimingTable := table.KeyValuePairs{}
@@ -200,7 +267,50 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 
 	out += titleStr("batch stats") + t.MustFormat(&table.Opts{BoldHeader: pointer.Bool(false)})
 
-	if job.Status == status.JobEnqueuing {
+	if resp.JobProgress != nil {
+		out += titleStr("progress")
+
+		if _flagWatch && job.TotalBatchCount > 0 {
+			out += progressBar(job.TotalBatchCount-resp.JobProgress.RemainingBatches, job.TotalBatchCount) + "\n\n"
+		}
+
+		perWorkerThroughput := "-"
+		if resp.JobProgress.BatchesPerSecondPerWorker != nil {
+			perWorkerThroughput = s.Round(*resp.JobProgress.BatchesPerSecondPerWorker, 3, 0) + " batches/sec"
+		}
+
+		estimatedCompletionTime := "-"
+		if resp.JobProgress.EstimatedCompletionTime != nil {
+			estimatedCompletionTime = fmt.Sprintf("%s (in %s)", resp.JobProgress.EstimatedCompletionTime.Format(_timeFormat), time.Until(*resp.JobProgress.EstimatedCompletionTime).Truncate(time.Second).String())
+		}
+
+		progressTable := table.KeyValuePairs{}
+		progressTable.Add("throughput", s.Round(resp.JobProgress.BatchesPerSecond, 3, 0)+" batches/sec")
+		progressTable.Add("throughput per worker", perWorkerThroughput)
+		progressTable.Add("remaining batches", resp.JobProgress.RemainingBatches)
+		progressTable.Add("estimated completion", estimatedCompletionTime)
+		out += progressTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
+	}
+
+	if resp.ResultManifest != nil {
+		out += fmt.Sprintf("\n%d result %s written to %s (listed in %s)\n", len(resp.ResultManifest.Results), s.PluralS("file", len(resp.ResultManifest.Results)), job.ResultSink.JobS3Path(job.ID), job.ResultSink.ManifestS3Path(job.ID))
+	} else if job.ResultSink != nil && !job.Status.IsCompleted() {
+		out += fmt.Sprintf("\nresults will be written to %s\n", job.ResultSink.JobS3Path(job.ID))
+	}
+
+	if job.BatchesInDeadLetterQueue > 0 {
+		out += fmt.Sprintf("\n%d failed %s moved to the dead-letter queue after %d %s (run `cortex job failed-batches %s %s` to inspect them)\n", job.BatchesInDeadLetterQueue, s.PluralEs("batch", job.BatchesInDeadLetterQueue), job.MaxRetries+1, s.PluralS("attempt", job.MaxRetries+1), job.APIName, job.ID)
+	}
+
+	if job.Status == status.JobPending {
+		out += "\nwaiting for dependencies to succeed, batches have not been enqueued for this job yet\n"
+	} else if job.Status == status.JobQueued {
+		queuePosition := "-"
+		if job.QueuePosition != nil {
+			queuePosition = s.Int(*job.QueuePosition)
+		}
+		out += fmt.Sprintf("\nqueued until the cluster has capacity for this job (position in queue: %s, priority: %d), batches have not been enqueued for this job yet\n", queuePosition, job.Priority)
+	} else if job.Status == status.JobEnqueuing {
 		out += "\nstill enqueuing, workers have not been allocated for this job yet\n"
 	} else if job.Status.IsCompleted() {
 		out += "\nworker stats are not available because this job is not currently running\n"
@@ -211,6 +321,7 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 			t := table.Table{
 				Headers: []table.Header{
 					{Title: "requested"},
+					{Title: "max", Hidden: !job.IsAutoscaled()},
 					{Title: "pending", Hidden: job.WorkerCounts.Pending == 0},
 					{Title: "initializing", Hidden: job.WorkerCounts.Initializing == 0},
 					{Title: "stalled", Hidden: job.WorkerCounts.Stalled == 0},
@@ -221,6 +332,7 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 				Rows: [][]interface{}{
 					{
 						job.Workers,
+						job.MaxWorkers,
 						job.WorkerCounts.Pending,
 						job.WorkerCounts.Initializing,
 						job.WorkerCounts.Stalled,
@@ -247,3 +359,15 @@ func getJob(env cliconfig.Environment, apiName string, jobID string) (string, er
 
 	return out, nil
 }
+
+func progressBar(completed int, total int) string {
+	width := libmath.MinInt(getTerminalWidth()-_progressBarExtraChars, _progressBarMaxWidth)
+	if width <= 0 {
+		width = _progressBarMaxWidth
+	}
+
+	completed = libmath.MinInt(completed, total)
+	numFilled := completed * width / total
+
+	return fmt.Sprintf("[%s%s] %d%% (%d/%d)", strings.Repeat("#", numFilled), strings.Repeat(".", width-numFilled), completed*100/total, completed, total)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/prompt"
@@ -39,6 +40,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types"
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/yaml"
+	kresource "k8s.io/apimachinery/pkg/api/resource"
 )
 
 var _cachedCLIConfig *cliconfig.CLIConfig
@@ -60,6 +62,15 @@ var _cliConfigValidation = &cr.StructValidation{
 				AllowEmpty: true, // will get set to "local" in validate() if empty
 			},
 		},
+		{
+			StructField: "ModelCacheMaxSize",
+			StringPtrValidation: &cr.StringPtrValidation{
+				AllowExplicitNull: true,
+			},
+			Parser: k8s.QuantityParser(&k8s.QuantityValidation{
+				GreaterThanOrEqualTo: k8s.QuantityPtr(kresource.MustParse("0")),
+			}),
+		},
 		{
 			StructField: "Environments",
 			StructListValidation: &cr.StructListValidation{
@@ -134,6 +145,15 @@ var _oldCLIConfigValidation = &cr.StructValidation{
 				AllowEmpty: true, // will get set to "local" in validate() if empty
 			},
 		},
+		{
+			StructField: "ModelCacheMaxSize",
+			StringPtrValidation: &cr.StringPtrValidation{
+				AllowExplicitNull: true,
+			},
+			Parser: k8s.QuantityParser(&k8s.QuantityValidation{
+				GreaterThanOrEqualTo: k8s.QuantityPtr(kresource.MustParse("0")),
+			}),
+		},
 		{
 			StructField: "Environments",
 			StructListValidation: &cr.StructListValidation{
@@ -547,6 +547,21 @@ func clusterConfigConfirmationStr(clusterConfig clusterconfig.Config, awsCreds A
 	if clusterConfig.APIGatewaySetting != defaultConfig.APIGatewaySetting {
 		items.Add(clusterconfig.APIGatewaySettingUserKey, clusterConfig.APIGatewaySetting)
 	}
+	if clusterConfig.AutoscalerMetricsSource != defaultConfig.AutoscalerMetricsSource {
+		items.Add(clusterconfig.AutoscalerMetricsSourceUserKey, clusterConfig.AutoscalerMetricsSource)
+	}
+	if clusterConfig.PrometheusURL != nil {
+		items.Add(clusterconfig.PrometheusURLUserKey, *clusterConfig.PrometheusURL)
+	}
+	if clusterConfig.BatchMaxConcurrentJobs != nil {
+		items.Add(clusterconfig.BatchMaxConcurrentJobsUserKey, *clusterConfig.BatchMaxConcurrentJobs)
+	}
+	if clusterConfig.BatchMaxConcurrentWorkers != nil {
+		items.Add(clusterconfig.BatchMaxConcurrentWorkersUserKey, *clusterConfig.BatchMaxConcurrentWorkers)
+	}
+	if clusterConfig.BatchJobStateStore != defaultConfig.BatchJobStateStore {
+		items.Add(clusterconfig.BatchJobStateStoreUserKey, clusterConfig.BatchJobStateStore)
+	}
 
 	if clusterConfig.Spot != nil && *clusterConfig.Spot != *defaultConfig.Spot {
 		items.Add(clusterconfig.SpotUserKey, s.YesNo(clusterConfig.Spot != nil && *clusterConfig.Spot))
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/local"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+)
+
+const (
+	_deployWatchPollInterval = time.Second
+	_deployWatchDebounce     = time.Second // the project must be unchanged for this long before it's redeployed, so that a burst of edits results in a single redeploy
+)
+
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+}
+
+// projectSnapshot contains the state of the api configuration file and of each project file (i.e. the files which would be deployed)
+type projectSnapshot map[string]watchedFile
+
+func takeProjectSnapshot(configPath string) (projectSnapshot, error) {
+	projectPaths, err := findProjectFiles(types.LocalProviderType, configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := projectSnapshot{}
+	for _, path := range append(projectPaths, configPath) {
+		fileInfo, err := os.Stat(path)
+		if err != nil {
+			// the file was deleted after the project was listed
+			continue
+		}
+		snapshot[path] = watchedFile{
+			modTime: fileInfo.ModTime(),
+			size:    fileInfo.Size(),
+		}
+	}
+
+	return snapshot, nil
+}
+
+// changedPaths returns the paths which were added, modified, or deleted since prevSnapshot
+func (snapshot projectSnapshot) changedPaths(prevSnapshot projectSnapshot) []string {
+	changedPaths := strset.New()
+	for path, file := range snapshot {
+		if prevFile, ok := prevSnapshot[path]; !ok || file != prevFile {
+			changedPaths.Add(path)
+		}
+	}
+	for path := range prevSnapshot {
+		if _, ok := snapshot[path]; !ok {
+			changedPaths.Add(path)
+		}
+	}
+	return changedPaths.Slice()
+}
+
+// watchLocalDeployment redeploys the apis in the local environment which are affected by changes to the project, until the command is interrupted
+// (if isDeployed is false, i.e. the initial deployment failed, all apis are redeployed after the first change)
+func watchLocalDeployment(env cliconfig.Environment, configPath string, modelCacheMaxSize *k8s.Quantity, isDeployed bool) {
+	prevSnapshot, err := takeProjectSnapshot(configPath)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	fmt.Println("\nwatching project files for changes (press ctrl+c to stop)")
+
+	pendingChanges := strset.New()
+	undeployedChanges := strset.New() // changes which were not deployed because the previous redeployment failed
+	var lastChangeTime time.Time
+	for {
+		time.Sleep(_deployWatchPollInterval)
+
+		snapshot, err := takeProjectSnapshot(configPath)
+		if err != nil {
+			// e.g. the .cortexignore file is invalid; the project will be checked again on the next poll
+			continue
+		}
+
+		if changedPaths := snapshot.changedPaths(prevSnapshot); len(changedPaths) > 0 {
+			pendingChanges.Add(changedPaths...)
+			lastChangeTime = time.Now()
+		}
+		prevSnapshot = snapshot
+
+		if len(pendingChanges) == 0 || time.Since(lastChangeTime) < _deployWatchDebounce {
+			continue
+		}
+
+		fmt.Printf("\n%s %s\n\n", console.Bold(libtime.LocalHourNow()), changedPathsStr(pendingChanges.Slice(), files.Dir(configPath)))
+		undeployedChanges.Merge(pendingChanges)
+		pendingChanges = strset.New()
+
+		var changedPaths []string // nil indicates that all apis should be redeployed
+		if isDeployed {
+			changedPaths = undeployedChanges.Slice()
+		}
+
+		if redeployLocal(env, configPath, changedPaths, modelCacheMaxSize) {
+			undeployedChanges = strset.New()
+			isDeployed = true
+		}
+	}
+}
+
+// Returns whether all of the affected apis were redeployed successfully
+func redeployLocal(env cliconfig.Environment, configPath string, changedPaths []string, modelCacheMaxSize *k8s.Quantity) bool {
+	projectFiles, err := findProjectFiles(types.LocalProviderType, configPath)
+	if err != nil {
+		errors.PrintError(err)
+		return false
+	}
+
+	// validation errors are printed, and the project will be redeployed once it changes again
+	var deployResponse schema.DeployResponse
+	if changedPaths == nil {
+		deployResponse, err = local.Deploy(env, configPath, projectFiles, _flagDeployDisallowPrompt, modelCacheMaxSize)
+	} else {
+		deployResponse, err = local.Redeploy(env, configPath, projectFiles, changedPaths, _flagDeployDisallowPrompt, modelCacheMaxSize)
+	}
+	if err != nil {
+		errors.PrintError(err)
+		return false
+	}
+
+	fmt.Println(mergeResultMessages(deployResponse.Results))
+
+	return !didAnyResultsError(deployResponse.Results)
+}
+
+func changedPathsStr(changedPaths []string, projectRoot string) string {
+	relPaths := make([]string, len(changedPaths))
+	for i, changedPath := range changedPaths {
+		relPaths[i] = files.PathRelativeToDir(changedPath, projectRoot)
+	}
+	sort.Strings(relPaths)
+
+	if len(relPaths) > 3 {
+		return fmt.Sprintf("%s, and %d other files changed", strings.Join(relPaths[:3], ", "), len(relPaths)-3)
+	}
+	return s.StrsAnd(relPaths) + " changed"
+}
@@ -53,7 +53,7 @@ func syncAPITable(syncAPI *schema.SyncAPI, env cliconfig.Environment) (string, e
 
 	out += t.MustFormat()
 
-	if env.Provider != types.LocalProviderType && syncAPI.Spec.Monitoring != nil {
+	if syncAPI.Spec.Monitoring != nil {
 		switch syncAPI.Spec.Monitoring.ModelType {
 		case userconfig.ClassificationModelType:
 			out += "\n" + classificationMetricsStr(&syncAPI.Metrics)
@@ -62,6 +62,14 @@ func syncAPITable(syncAPI *schema.SyncAPI, env cliconfig.Environment) (string, e
 		}
 	}
 
+	if env.Provider != types.LocalProviderType && syncAPI.Spec.Autoscaling != nil {
+		out += activeSchedulesStr(syncAPI.Spec.Autoscaling)
+	}
+
+	if syncAPI.Rollout != nil {
+		out += "\n" + rolloutStr(syncAPI.Rollout) + "\n"
+	}
+
 	if syncAPI.DashboardURL != "" {
 		out += "\n" + console.Bold("metrics dashboard: ") + syncAPI.DashboardURL + "\n"
 	}
@@ -79,6 +87,47 @@ func syncAPITable(syncAPI *schema.SyncAPI, env cliconfig.Environment) (string, e
 	return out, nil
 }
 
+func activeSchedulesStr(autoscaling *userconfig.Autoscaling) string {
+	var out string
+	now := time.Now()
+	for _, schedule := range autoscaling.Schedules {
+		activeSince, err := schedule.ActiveSince(now)
+		if err != nil || activeSince == nil {
+			continue
+		}
+
+		var overrides []string
+		if schedule.MinReplicas != nil {
+			overrides = append(overrides, fmt.Sprintf("%s: %d", userconfig.MinReplicasKey, *schedule.MinReplicas))
+		}
+		if schedule.MaxReplicas != nil {
+			overrides = append(overrides, fmt.Sprintf("%s: %d", userconfig.MaxReplicasKey, *schedule.MaxReplicas))
+		}
+
+		activeUntil := activeSince.Add(schedule.Duration)
+		out += "\n" + console.Bold("active schedule: ") + fmt.Sprintf("%s (%s) until %s", schedule.Cron, strings.Join(overrides, ", "), libtime.LocalTimestamp(&activeUntil))
+	}
+
+	if out != "" {
+		out += "\n"
+	}
+	return out
+}
+
+func rolloutStr(rollout *status.Rollout) string {
+	switch rollout.Code {
+	case status.RolloutRolledBack:
+		return console.Bold("canary rollout: ") + fmt.Sprintf("%s at %s (%s)", rollout.Code.Message(), libtime.LocalTimestamp(rollout.EndTime), rollout.Message)
+	case status.RolloutPromoting:
+		return console.Bold("canary rollout: ") + fmt.Sprintf("%s (%d%% of traffic is routed to the canary)", rollout.Code.Message(), rollout.Weight)
+	}
+
+	if rollout.Step < 0 {
+		return console.Bold("canary rollout: ") + fmt.Sprintf("%s, waiting for the canary to become ready", rollout.Code.Message())
+	}
+	return console.Bold("canary rollout: ") + fmt.Sprintf("%s, step %d/%d (%d%% of traffic is routed to the canary since %s)", rollout.Code.Message(), rollout.Step+1, len(rollout.Steps), rollout.Weight, libtime.LocalTimestamp(&rollout.StepStartTime))
+}
+
 func syncAPIsTable(syncAPIs []schema.SyncAPI, envNames []string) table.Table {
 	rows := make([][]interface{}, 0, len(syncAPIs))
 
@@ -232,8 +281,12 @@ func classificationMetricsStr(metrics *metrics.Metrics) string {
 	return out
 }
 
-func describeModelInput(status *status.Status, apiEndpoint string) string {
-	if status.Updated.Ready+status.Stale.Ready == 0 {
+func describeModelInput(apiStatus *status.Status, apiEndpoint string) string {
+	if apiStatus.Code == status.ScaledToZero {
+		return "the model's input schema will be available when the api has scaled up\n"
+	}
+
+	if apiStatus.Updated.Ready+apiStatus.Stale.Ready == 0 {
 		return "the model's input schema will be available when the api is live\n"
 	}
 
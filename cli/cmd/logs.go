@@ -28,6 +28,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/exit"
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/spf13/cobra"
 )
 
@@ -69,9 +70,10 @@ var _logsCmd = &cobra.Command{
 			}
 		} else {
 			if len(args) == 2 {
-				exit.Error(ErrorNotSupportedInLocalEnvironment(), fmt.Sprintf("cannot stream logs for job %s for api %s", args[1], args[0]))
+				err = local.StreamJobLogs(spec.JobKey{ID: args[1], APIName: apiName})
+			} else {
+				err = local.StreamLogs(apiName)
 			}
-			err := local.StreamLogs(apiName)
 			if err != nil {
 				exit.Error(err)
 			}
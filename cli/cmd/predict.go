@@ -30,6 +30,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/spf13/cobra"
 )
 
@@ -69,7 +70,7 @@ var _predictCmd = &cobra.Command{
 				exit.Error(err)
 			}
 		} else {
-			apiRes, err = local.GetAPI(apiName)
+			apiRes, err = local.GetAPI(apiName, 0)
 			if err != nil {
 				exit.Error(err)
 			}
@@ -82,7 +83,7 @@ var _predictCmd = &cobra.Command{
 		syncAPI := apiRes.SyncAPI
 
 		totalReady := syncAPI.Status.Updated.Ready + syncAPI.Status.Stale.Ready
-		if totalReady == 0 {
+		if totalReady == 0 && syncAPI.Status.Code != status.ScaledToZero {
 			exit.Error(ErrorAPINotReady(apiName, syncAPI.Status.Message()))
 		}
 
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	_flagRollbackEnv   string
+	_flagRollbackTo    string
+	_flagRollbackForce bool
+)
+
+func rollbackInit() {
+	_rollbackCmd.Flags().SortFlags = false
+	_rollbackCmd.Flags().StringVarP(&_flagRollbackEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+	_rollbackCmd.Flags().StringVar(&_flagRollbackTo, "to", "", "the api id of the version to roll back to (see `cortex history API_NAME`); defaults to the previous version")
+	_rollbackCmd.Flags().BoolVarP(&_flagRollbackForce, "force", "f", false, "override the in-progress api update")
+}
+
+var _rollbackCmd = &cobra.Command{
+	Use:   "rollback API_NAME",
+	Short: "redeploy a previous version of an api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env, err := ReadOrConfigureEnv(_flagRollbackEnv)
+		if err != nil {
+			telemetry.Event("cli.rollback")
+			exit.Error(err)
+		}
+		telemetry.Event("cli.rollback", map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+		err = printEnvIfNotSpecified(_flagRollbackEnv, cmd)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if env.Provider == types.LocalProviderType {
+			print.BoldFirstLine("`cortex rollback` is not supported in the local environment; use `cortex deploy` instead")
+			return
+		}
+
+		rollbackResponse, err := cluster.Rollback(MustGetOperatorConfig(env.Name), args[0], _flagRollbackTo, _flagRollbackForce)
+		if err != nil {
+			exit.Error(err)
+		}
+		print.BoldFirstLine(rollbackResponse.Message)
+	},
+}
@@ -97,15 +97,22 @@ func init() {
 		initTelemetry()
 	}
 
+	autoscalerInit()
+	cacheInit()
+	gatewayInit()
 	clusterInit()
 	completionInit()
 	deleteInit()
 	deployInit()
 	envInit()
 	getInit()
+	historyInit()
+	jobInit()
+	scheduleInit()
 	logsInit()
 	predictInit()
 	refreshInit()
+	rollbackInit()
 	versionInit()
 }
 
@@ -136,15 +143,22 @@ func Execute() {
 
 	_rootCmd.AddCommand(_deployCmd)
 	_rootCmd.AddCommand(_refreshCmd)
+	_rootCmd.AddCommand(_rollbackCmd)
 	_rootCmd.AddCommand(_getCmd)
+	_rootCmd.AddCommand(_historyCmd)
+	_rootCmd.AddCommand(_jobCmd)
+	_rootCmd.AddCommand(_scheduleCmd)
 	_rootCmd.AddCommand(_logsCmd)
 	_rootCmd.AddCommand(_predictCmd)
 	_rootCmd.AddCommand(_deleteCmd)
+	_rootCmd.AddCommand(_autoscalerCmd)
+	_rootCmd.AddCommand(_gatewayCmd)
 
 	_rootCmd.AddCommand(_clusterCmd)
 	_rootCmd.AddCommand(_versionCmd)
 
 	_rootCmd.AddCommand(_envCmd)
+	_rootCmd.AddCommand(_cacheCmd)
 	_rootCmd.AddCommand(_completionCmd)
 
 	updateRootUsage()
@@ -0,0 +1,301 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/cluster"
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/lib/console"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/exit"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/lib/print"
+	"github.com/cortexlabs/cortex/pkg/lib/table"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// how far ahead to look for a scheduled job's next run
+const _scheduleLookahead = 366 * 24 * time.Hour
+
+var (
+	_flagScheduleEnv string
+)
+
+func scheduleInit() {
+	for _, cmd := range []*cobra.Command{_scheduleCreateCmd, _scheduleListCmd, _scheduleGetCmd, _schedulePauseCmd, _scheduleResumeCmd, _scheduleDeleteCmd} {
+		cmd.Flags().SortFlags = false
+		cmd.Flags().StringVarP(&_flagScheduleEnv, "env", "e", getDefaultEnv(_generalCommandType), "environment to use")
+		_scheduleCmd.AddCommand(cmd)
+	}
+}
+
+var _scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "manage jobs which are submitted to batch apis on a schedule",
+}
+
+var _scheduleCreateCmd = &cobra.Command{
+	Use:   "create API_NAME SCHEDULE_FILE",
+	Short: "create or update a scheduled job from a json file",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnv(cmd, "create")
+
+		submissionPath := files.RelToAbsPath(args[1], _cwd)
+		submissionBytes, err := files.ReadFileBytes(submissionPath)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		scheduledJob, err := cluster.CreateScheduledJob(MustGetOperatorConfig(env.Name), args[0], submissionBytes)
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("scheduled job %s will submit jobs to %s on the schedule \"%s\" (next run: %s)", scheduledJob.Name, scheduledJob.APIName, scheduledJob.Schedule, nextScheduledRunStr(scheduledJob)))
+	},
+}
+
+var _scheduleListCmd = &cobra.Command{
+	Use:   "list API_NAME",
+	Short: "list the scheduled jobs of a batch api",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnv(cmd, "list")
+
+		scheduledJobsRes, err := cluster.GetScheduledJobs(MustGetOperatorConfig(env.Name), args[0])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		if len(scheduledJobsRes.ScheduledJobs) == 0 {
+			print.BoldFirstLine(fmt.Sprintf("%s does not have any scheduled jobs", args[0]))
+			return
+		}
+
+		t := scheduledJobsTable(scheduledJobsRes.ScheduledJobs)
+		fmt.Print(t.MustFormat())
+	},
+}
+
+var _scheduleGetCmd = &cobra.Command{
+	Use:   "get API_NAME SCHEDULE_NAME",
+	Short: "get a scheduled job and the jobs it has submitted",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnv(cmd, "get")
+
+		scheduledJob, err := cluster.GetScheduledJob(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		fmt.Print(scheduledJobStr(scheduledJob))
+	},
+}
+
+var _schedulePauseCmd = &cobra.Command{
+	Use:   "pause API_NAME SCHEDULE_NAME",
+	Short: "stop a scheduled job from submitting jobs until it is resumed",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnv(cmd, "pause")
+
+		scheduledJob, err := cluster.PauseScheduledJob(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("paused scheduled job %s (runs which are due while it is paused will be skipped)", scheduledJob.Name))
+	},
+}
+
+var _scheduleResumeCmd = &cobra.Command{
+	Use:   "resume API_NAME SCHEDULE_NAME",
+	Short: "resume a paused scheduled job",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnv(cmd, "resume")
+
+		scheduledJob, err := cluster.ResumeScheduledJob(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(fmt.Sprintf("resumed scheduled job %s (next run: %s)", scheduledJob.Name, nextScheduledRunStr(scheduledJob)))
+	},
+}
+
+var _scheduleDeleteCmd = &cobra.Command{
+	Use:   "delete API_NAME SCHEDULE_NAME",
+	Short: "delete a scheduled job (jobs which it has submitted are not stopped)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		env := scheduleEnv(cmd, "delete")
+
+		deleteRes, err := cluster.DeleteScheduledJob(MustGetOperatorConfig(env.Name), args[0], args[1])
+		if err != nil {
+			exit.Error(err)
+		}
+
+		print.BoldFirstLine(deleteRes.Message)
+	},
+}
+
+func scheduleEnv(cmd *cobra.Command, subcommand string) cliconfig.Environment {
+	event := "cli.schedule." + subcommand
+
+	env, err := ReadOrConfigureEnv(_flagScheduleEnv)
+	if err != nil {
+		telemetry.Event(event)
+		exit.Error(err)
+	}
+	telemetry.Event(event, map[string]interface{}{"provider": env.Provider.String(), "env_name": env.Name})
+
+	err = printEnvIfNotSpecified(_flagScheduleEnv, cmd)
+	if err != nil {
+		exit.Error(err)
+	}
+
+	if env.Provider == types.LocalProviderType {
+		exit.Error(errors.Wrap(ErrorNotSupportedInLocalEnvironment(), "cannot schedule jobs"))
+	}
+
+	return env
+}
+
+func scheduledJobsTable(scheduledJobs []schema.ScheduledJob) table.Table {
+	rows := make([][]interface{}, 0, len(scheduledJobs))
+
+	for _, scheduledJob := range scheduledJobs {
+		lastJobID := "-"
+		if len(scheduledJob.History) > 0 && scheduledJob.History[len(scheduledJob.History)-1].JobID != "" {
+			lastJobID = scheduledJob.History[len(scheduledJob.History)-1].JobID
+		}
+
+		rows = append(rows, []interface{}{
+			scheduledJob.Name,
+			scheduledJob.Schedule,
+			scheduledJob.ConcurrencyPolicy.String(),
+			scheduledJobStatusStr(scheduledJob),
+			lastScheduledRunStr(scheduledJob),
+			nextScheduledRunStr(scheduledJob),
+			lastJobID,
+		})
+	}
+
+	return table.Table{
+		Headers: []table.Header{
+			{Title: "name"},
+			{Title: "schedule"},
+			{Title: "concurrency"},
+			{Title: "status"},
+			{Title: "last run"},
+			{Title: "next run"},
+			{Title: "last job id"},
+		},
+		Rows: rows,
+	}
+}
+
+func scheduledJobStr(scheduledJob schema.ScheduledJob) string {
+	out := ""
+
+	introTable := table.KeyValuePairs{}
+	introTable.Add("name", scheduledJob.Name)
+	introTable.Add("api", scheduledJob.APIName)
+	introTable.Add("schedule", scheduledJob.Schedule+" (utc)")
+	introTable.Add("concurrency policy", scheduledJob.ConcurrencyPolicy.String())
+	introTable.Add("status", scheduledJobStatusStr(scheduledJob))
+	introTable.Add("last run", lastScheduledRunStr(scheduledJob))
+	introTable.Add("next run", nextScheduledRunStr(scheduledJob))
+	out += introTable.String(&table.KeyValuePairOpts{BoldKeys: pointer.Bool(true)})
+
+	if len(scheduledJob.History) == 0 {
+		return out
+	}
+
+	// the most recent run is listed first
+	rows := make([][]interface{}, 0, len(scheduledJob.History))
+	for i := len(scheduledJob.History) - 1; i >= 0; i-- {
+		run := scheduledJob.History[i]
+
+		jobID := run.JobID
+		if jobID == "" {
+			jobID = "-"
+		}
+
+		rows = append(rows, []interface{}{
+			run.ScheduledTime.Local().Format(_timeFormat),
+			jobID,
+			run.Message,
+		})
+	}
+
+	t := table.Table{
+		Headers: []table.Header{
+			{Title: "scheduled for"},
+			{Title: "job id"},
+			{Title: "message", MaxWidth: 80},
+		},
+		Rows: rows,
+	}
+
+	out += titleStr("history") + t.MustFormat()
+	out += "\n" + console.Bold("to get the status of a job:") + fmt.Sprintf(" cortex get %s JOB_ID\n", scheduledJob.APIName)
+
+	return out
+}
+
+func scheduledJobStatusStr(scheduledJob schema.ScheduledJob) string {
+	if scheduledJob.Paused {
+		return "paused"
+	}
+	return "active"
+}
+
+func lastScheduledRunStr(scheduledJob schema.ScheduledJob) string {
+	if len(scheduledJob.History) == 0 {
+		return "-"
+	}
+	return scheduledJob.History[len(scheduledJob.History)-1].ScheduledTime.Local().Format(_timeFormat)
+}
+
+func nextScheduledRunStr(scheduledJob schema.ScheduledJob) string {
+	if scheduledJob.Paused {
+		return "-"
+	}
+
+	cronSchedule, err := cron.ParseSchedule(scheduledJob.Schedule)
+	if err != nil {
+		return "-"
+	}
+
+	nextRun := cronSchedule.NextActivation(time.Now(), _scheduleLookahead)
+	if nextRun == nil {
+		return "-"
+	}
+	return nextRun.Local().Format(_timeFormat)
+}
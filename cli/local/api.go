@@ -19,12 +19,14 @@ package local
 import (
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/msgpack"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/lib/prompt"
@@ -35,7 +37,7 @@ import (
 
 var _deploymentID = "local"
 
-func UpdateAPI(apiConfig *userconfig.API, configPath string, projectID string, deployDisallowPrompt bool, awsClient *aws.Client) (*spec.API, string, error) {
+func UpdateAPI(apiConfig *userconfig.API, configPath string, projectID string, deployDisallowPrompt bool, envName string, modelCacheMaxSize *k8s.Quantity, awsClient *aws.Client) (*spec.API, string, error) {
 	var incompatibleVersion string
 	encounteredVersionMismatch := false
 	prevAPISpec, err := FindAPISpec(apiConfig.Name)
@@ -74,8 +76,8 @@ func UpdateAPI(apiConfig *userconfig.API, configPath string, projectID string, d
 	newAPISpec := spec.GetAPISpec(apiConfig, projectID, _deploymentID)
 
 	// apiConfig.Predictor.ModelPath was already added to apiConfig.Predictor.Models for ease of use
-	if len(apiConfig.Predictor.Models) > 0 {
-		localModelCaches, err := CacheModels(newAPISpec, awsClient)
+	if apiConfig.Predictor != nil && len(apiConfig.Predictor.Models) > 0 {
+		localModelCaches, err := CacheModels(newAPISpec, awsClient, modelCacheMaxSize)
 		if err != nil {
 			return nil, "", err
 		}
@@ -85,6 +87,12 @@ func UpdateAPI(apiConfig *userconfig.API, configPath string, projectID string, d
 	newAPISpec.LocalProjectDir = files.Dir(configPath)
 
 	if areAPIsEqual(newAPISpec, prevAPISpec) {
+		// the gateway isn't running if the machine was restarted since the api was deployed
+		if isServedByGateway(newAPISpec.Kind) && !isGatewayRunning(newAPISpec.Name) {
+			if err := startGateway(newAPISpec, envName); err != nil {
+				return nil, "", err
+			}
+		}
 		return newAPISpec, fmt.Sprintf("%s is up to date", newAPISpec.Resource.UserString()), nil
 	}
 
@@ -105,7 +113,13 @@ func UpdateAPI(apiConfig *userconfig.API, configPath string, projectID string, d
 		return nil, "", err
 	}
 
-	if err := DeployContainers(newAPISpec, awsClient); err != nil {
+	if isServedByGateway(newAPISpec.Kind) {
+		// BatchAPI workers are started when a job is submitted
+		err = startGateway(newAPISpec, envName)
+	} else {
+		err = DeployContainers(newAPISpec, awsClient)
+	}
+	if err != nil {
 		DeleteAPI(newAPISpec.Name)
 		DeleteCachedModels(newAPISpec.Name, newAPISpec.ModelIDs())
 		return nil, "", err
@@ -157,7 +171,13 @@ func areAPIsEqual(a1, a2 *spec.API) bool {
 	if !pointer.AreIntsEqual(a1.Networking.LocalPort, a2.Networking.LocalPort) {
 		return false
 	}
-	if !a1.Compute.Equals(a2.Compute) {
+	if !reflect.DeepEqual(a1.APIs, a2.APIs) {
+		return false
+	}
+	if (a1.Compute == nil) != (a2.Compute == nil) {
+		return false
+	}
+	if a1.Compute != nil && !a1.Compute.Equals(a2.Compute) {
 		return false
 	}
 	if !strset.FromSlice(a1.ModelIDs()).IsEqual(strset.FromSlice(a2.ModelIDs())) {
@@ -169,6 +189,10 @@ func areAPIsEqual(a1, a2 *spec.API) bool {
 func DeleteAPI(apiName string) error {
 	errList := []error{}
 
+	if err := stopGateway(apiName); err != nil {
+		errList = append(errList, err)
+	}
+
 	containers, err := GetContainersByAPI(apiName)
 	if err == nil {
 		if len(containers) > 0 {
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// apiSplitterHandler proxies each request to one of the APISplitter's SyncAPIs, chosen randomly according to the traffic split weights
+func apiSplitterHandler(apiSplitter *spec.API) http.Handler {
+	// the package-level source is safe for concurrent use (unlike a *rand.Rand), since requests are handled concurrently
+	rand.Seed(time.Now().UnixNano())
+
+	fmt.Printf("serving %s on %s\n", apiSplitter.Identify(), gatewayEndpoint(apiSplitter))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiName := pickTrafficSplitAPI(apiSplitter.APIs, rand.Intn(100))
+
+		// the api is looked up on each request since it may be redeployed on a different port while the splitter is running
+		apiSpec, err := FindAPISpec(apiName)
+		if err != nil {
+			respondError(w, err, http.StatusServiceUnavailable)
+			return
+		}
+		if apiSpec.Kind != userconfig.SyncAPIKind || apiSpec.Networking.LocalPort == nil {
+			respondError(w, ErrorAPINotDeployed(apiName), http.StatusServiceUnavailable)
+			return
+		}
+
+		target := &url.URL{Scheme: "http", Host: fmt.Sprintf("localhost:%d", *apiSpec.Networking.LocalPort)}
+		httputil.NewSingleHostReverseProxy(target).ServeHTTP(w, r)
+	})
+}
+
+// pickTrafficSplitAPI returns the name of the api whose cumulative weight range contains roll (which is in [0, 100))
+func pickTrafficSplitAPI(trafficSplits []*userconfig.TrafficSplit, roll int) string {
+	cumulativeWeight := 0
+	for _, trafficSplit := range trafficSplits {
+		cumulativeWeight += trafficSplit.Weight
+		if roll < cumulativeWeight {
+			return trafficSplit.Name
+		}
+	}
+
+	// unreachable since the weights add up to 100
+	return trafficSplits[len(trafficSplits)-1].Name
+}
@@ -0,0 +1,493 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	libjson "github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/metrics"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// a local job's files are stored in ~/.cortex/workspace/apis/<api_name>/jobs/<job_id>/, which is mounted into the job's workers
+const (
+	_jobSpecFileName      = "spec.json"
+	_jobStateFileName     = "state.json"   // the job status set by the cli; while the job is running, its status is derived from its workers
+	_jobQueueDirName      = "queue"        // batches waiting to be processed (a worker claims a batch by moving it to in_progress/)
+	_jobInProgressDirName = "in_progress"  // batches which are being processed
+	_jobDeadLetterDirName = "dead_letter"  // batches which failed on every attempt
+	_jobMetricsDirName    = "metrics"      // batch metrics, one file per worker
+	_jobCompleteFileName  = "job_complete" // claimed by the worker which runs on_job_complete()
+)
+
+var _jobIDMutex = sync.Mutex{}
+
+type localJobState struct {
+	Status  status.JobCode `json:"status"`
+	EndTime *time.Time     `json:"end_time"`
+}
+
+type localBatch struct {
+	BatchID  string          `json:"batch_id"`
+	Attempts int             `json:"attempts"` // the number of times the batch has failed
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// the batch metrics reported by a single worker
+type localWorkerMetrics struct {
+	Succeeded int     `json:"succeeded"`
+	Failed    int     `json:"failed"`
+	TotalTime float64 `json:"total_time"` // seconds
+}
+
+func jobsDir(apiName string) string {
+	return filepath.Join(_localWorkspaceDir, "apis", apiName, "jobs")
+}
+
+func jobDir(jobKey spec.JobKey) string {
+	return filepath.Join(jobsDir(jobKey.APIName), jobKey.ID)
+}
+
+// same format as the job ids generated by the operator, which are monotonically decreasing
+func newJobID() string {
+	_jobIDMutex.Lock()
+	defer _jobIDMutex.Unlock()
+
+	return fmt.Sprintf("%x", math.MaxInt64-time.Now().UnixNano())
+}
+
+func validateJobSubmission(submission *schema.JobSubmission) error {
+	if submission.FilePathLister != nil {
+		return ErrorFieldNotSupportedLocally(schema.FilePathListerKey)
+	}
+	if submission.DelimitedFiles != nil {
+		return ErrorFieldNotSupportedLocally(schema.DelimitedFilesKey)
+	}
+	if len(submission.DependsOn) > 0 {
+		return ErrorFieldNotSupportedLocally(schema.DependsOnKey)
+	}
+	if submission.MaxWorkers != 0 {
+		return ErrorFieldNotSupportedLocally(schema.MaxWorkersKey)
+	}
+
+	if submission.ItemList == nil {
+		return errors.Wrap(cr.ErrorMustBeDefined(), schema.ItemListKey)
+	}
+	if len(submission.ItemList.Items) == 0 {
+		return errors.Wrap(cr.ErrorTooFewElements(1), schema.ItemListKey, schema.ItemsKey)
+	}
+	if submission.ItemList.BatchSize < 1 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.ItemList.BatchSize, 1), schema.ItemListKey, schema.BatchSizeKey)
+	}
+
+	if submission.Workers < 1 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.Workers, 1), schema.WorkersKey)
+	}
+
+	if submission.MaxRetries < 0 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.MaxRetries, 0), schema.MaxRetriesKey)
+	}
+
+	if submission.ResultSink != nil {
+		if !aws.IsValidS3Path(submission.ResultSink.S3Path) {
+			return errors.Wrap(aws.ErrorInvalidS3Path(submission.ResultSink.S3Path), schema.ResultSinkKey, schema.S3PathKey)
+		}
+		if submission.ResultSink.Format == spec.UnknownResultFormat {
+			return errors.Wrap(cr.ErrorInvalidStr("", spec.ResultFormatStrings()[0], spec.ResultFormatStrings()[1:]...), schema.ResultSinkKey, schema.FormatKey)
+		}
+	}
+
+	return nil
+}
+
+// SubmitJob enqueues the submission's batches in a local queue and starts the job's workers
+func SubmitJob(apiSpec *spec.API, submission *schema.JobSubmission, awsClient *aws.Client) (*spec.Job, error) {
+	err := validateJobSubmission(submission)
+	if err != nil {
+		return nil, errors.Append(err, fmt.Sprintf("\n\njob submission schema can be found at https://docs.cortex.dev/v/%s/deployments/batchapi/endpoints", consts.CortexVersionMinor))
+	}
+
+	jobSpec := spec.Job{
+		JobKey: spec.JobKey{
+			ID:      newJobID(),
+			APIName: apiSpec.Name,
+		},
+		RuntimeJobConfig: submission.RuntimeJobConfig,
+		APIID:            apiSpec.ID,
+		StartTime:        time.Now(),
+	}
+
+	for _, dirName := range []string{_jobQueueDirName, _jobInProgressDirName, _jobDeadLetterDirName, _jobMetricsDirName} {
+		if err := files.CreateDir(filepath.Join(jobDir(jobSpec.JobKey), dirName)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeJobSpec(&jobSpec); err != nil {
+		return nil, err
+	}
+	if err := writeJobState(jobSpec.JobKey, status.JobEnqueuing); err != nil {
+		return nil, err
+	}
+
+	totalBatchCount, err := enqueueItemList(jobSpec.JobKey, submission.ItemList)
+	if err != nil {
+		writeJobState(jobSpec.JobKey, status.JobEnqueueFailed)
+		return nil, err
+	}
+
+	jobSpec.TotalBatchCount = totalBatchCount
+	if err := writeJobSpec(&jobSpec); err != nil {
+		writeJobState(jobSpec.JobKey, status.JobUnexpectedError)
+		return nil, err
+	}
+
+	if err := files.MakeEmptyFile(filepath.Join(jobDir(jobSpec.JobKey), _jobCompleteFileName)); err != nil {
+		writeJobState(jobSpec.JobKey, status.JobUnexpectedError)
+		return nil, err
+	}
+
+	if err := DeployWorkerContainers(apiSpec, &jobSpec, awsClient); err != nil {
+		DeleteJobContainers(jobSpec.JobKey)
+		writeJobState(jobSpec.JobKey, status.JobUnexpectedError)
+		return nil, err
+	}
+
+	if err := writeJobState(jobSpec.JobKey, status.JobRunning); err != nil {
+		return nil, err
+	}
+
+	return &jobSpec, nil
+}
+
+func enqueueItemList(jobKey spec.JobKey, itemList *schema.ItemList) (int, error) {
+	queueDir := filepath.Join(jobDir(jobKey), _jobQueueDirName)
+
+	batchCount := 0
+	for start := 0; start < len(itemList.Items); start += itemList.BatchSize {
+		end := start + itemList.BatchSize
+		if end > len(itemList.Items) {
+			end = len(itemList.Items)
+		}
+
+		payload, err := libjson.Marshal(itemList.Items[start:end])
+		if err != nil {
+			return 0, errors.Wrap(err, schema.ItemListKey)
+		}
+
+		// zero-padded so that batches are processed in the order that they were submitted
+		batch := localBatch{
+			BatchID: fmt.Sprintf("%09d", batchCount),
+			Payload: payload,
+		}
+
+		if err := libjson.WriteJSON(batch, filepath.Join(queueDir, batch.BatchID+".json")); err != nil {
+			return 0, err
+		}
+		batchCount++
+	}
+
+	return batchCount, nil
+}
+
+func writeJobSpec(jobSpec *spec.Job) error {
+	return libjson.WriteJSON(jobSpec, filepath.Join(jobDir(jobSpec.JobKey), _jobSpecFileName))
+}
+
+func writeJobState(jobKey spec.JobKey, jobCode status.JobCode) error {
+	var endTime *time.Time
+	if jobCode.IsCompleted() {
+		endTime = pointer.Time(time.Now())
+	}
+	return writeJobStateWithEndTime(jobKey, jobCode, endTime)
+}
+
+func writeJobStateWithEndTime(jobKey spec.JobKey, jobCode status.JobCode, endTime *time.Time) error {
+	jobState := localJobState{Status: jobCode, EndTime: endTime}
+	return libjson.WriteJSON(jobState, filepath.Join(jobDir(jobKey), _jobStateFileName))
+}
+
+func readJobSpec(jobKey spec.JobKey) (*spec.Job, error) {
+	jobSpecPath := filepath.Join(jobDir(jobKey), _jobSpecFileName)
+	if !files.IsFile(jobSpecPath) {
+		return nil, ErrorJobNotFound(jobKey)
+	}
+
+	jobSpecBytes, err := files.ReadFileBytes(jobSpecPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobSpec spec.Job
+	if err := libjson.Unmarshal(jobSpecBytes, &jobSpec); err != nil {
+		return nil, errors.Wrap(err, jobKey.UserString())
+	}
+
+	return &jobSpec, nil
+}
+
+func readJobState(jobKey spec.JobKey) (*localJobState, error) {
+	jobStateBytes, err := files.ReadFileBytes(filepath.Join(jobDir(jobKey), _jobStateFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	var jobState localJobState
+	if err := libjson.Unmarshal(jobStateBytes, &jobState); err != nil {
+		return nil, errors.Wrap(err, jobKey.UserString())
+	}
+
+	return &jobState, nil
+}
+
+func countFilesInJobDir(jobKey spec.JobKey, dirName string) int {
+	fileNames, err := files.ListDir(filepath.Join(jobDir(jobKey), dirName), true)
+	if err != nil {
+		return 0
+	}
+	return len(fileNames)
+}
+
+func getJobBatchMetrics(jobKey spec.JobKey) (*metrics.BatchMetrics, error) {
+	metricsDir := filepath.Join(jobDir(jobKey), _jobMetricsDirName)
+	if !files.IsDir(metricsDir) {
+		return &metrics.BatchMetrics{}, nil
+	}
+
+	metricsPaths, err := files.ListDir(metricsDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	batchMetrics := metrics.BatchMetrics{}
+	for _, metricsPath := range metricsPaths {
+		metricsBytes, err := files.ReadFileBytes(metricsPath)
+		if err != nil {
+			return nil, err
+		}
+
+		var workerMetrics localWorkerMetrics
+		if err := libjson.Unmarshal(metricsBytes, &workerMetrics); err != nil {
+			continue // the worker may be writing to the file
+		}
+
+		workerBatchMetrics := metrics.BatchMetrics{
+			Succeeded: workerMetrics.Succeeded,
+			Failed:    workerMetrics.Failed,
+		}
+		if workerBatchMetrics.TotalCompleted() > 0 {
+			workerBatchMetrics.AverageTimePerBatch = pointer.Float64(workerMetrics.TotalTime / float64(workerBatchMetrics.TotalCompleted()))
+		}
+
+		batchMetrics.MergeInPlace(workerBatchMetrics)
+	}
+
+	return &batchMetrics, nil
+}
+
+func GetJobStatus(jobKey spec.JobKey) (*status.JobStatus, error) {
+	jobSpec, err := readJobSpec(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	jobState, err := readJobState(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	batchMetrics, err := getJobBatchMetrics(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	jobStatus := status.JobStatus{
+		Job:                      *jobSpec,
+		Status:                   jobState.Status,
+		EndTime:                  jobState.EndTime,
+		BatchesInQueue:           countFilesInJobDir(jobKey, _jobQueueDirName) + countFilesInJobDir(jobKey, _jobInProgressDirName),
+		BatchesInDeadLetterQueue: countFilesInJobDir(jobKey, _jobDeadLetterDirName),
+		BatchMetrics:             batchMetrics,
+	}
+
+	if jobState.Status != status.JobRunning {
+		return &jobStatus, nil
+	}
+
+	containers, err := GetContainersByJob(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	workerCounts := status.WorkerCounts{}
+	workerOOM := false
+	var lastFinishedAt *time.Time
+	for _, container := range containers {
+		if container.Labels["type"] != _workerContainerName {
+			continue
+		}
+
+		containerInfo, err := docker.MustDockerClient().ContainerInspect(context.Background(), container.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, jobKey.UserString())
+		}
+
+		switch {
+		case containerInfo.State.Running:
+			workerCounts.Running++
+		case containerInfo.State.Status == "created":
+			workerCounts.Pending++
+		case containerInfo.State.ExitCode == 0:
+			workerCounts.Succeeded++
+		default:
+			workerCounts.Failed++
+			if containerInfo.State.OOMKilled {
+				workerOOM = true
+			}
+		}
+
+		if finishedAt, err := time.Parse(time.RFC3339Nano, containerInfo.State.FinishedAt); err == nil && !finishedAt.IsZero() {
+			if lastFinishedAt == nil || finishedAt.After(*lastFinishedAt) {
+				lastFinishedAt = &finishedAt
+			}
+		}
+	}
+
+	if workerCounts.Running > 0 || workerCounts.Pending > 0 {
+		jobStatus.WorkerCounts = &workerCounts
+		return &jobStatus, nil
+	}
+
+	// all of the workers have exited (or their containers were removed)
+	if lastFinishedAt == nil {
+		lastFinishedAt = pointer.Time(time.Now())
+	}
+	jobStatus.EndTime = lastFinishedAt
+	switch {
+	case workerOOM && jobStatus.BatchesInQueue > 0:
+		jobStatus.Status = status.JobWorkerOOM
+	case jobStatus.BatchesInQueue > 0:
+		jobStatus.Status = status.JobWorkerError
+	case jobStatus.BatchesInDeadLetterQueue > 0 || batchMetrics.Failed > 0:
+		jobStatus.Status = status.JobCompletedWithFailures
+	default:
+		jobStatus.Status = status.JobSucceeded
+	}
+
+	// the final status is persisted, since it can't be derived once the worker containers are removed
+	if err := writeJobStateWithEndTime(jobKey, jobStatus.Status, jobStatus.EndTime); err != nil {
+		return nil, err
+	}
+
+	return &jobStatus, nil
+}
+
+func GetJob(jobKey spec.JobKey) (schema.GetJobResponse, error) {
+	apiSpec, err := FindAPISpec(jobKey.APIName)
+	if err != nil {
+		return schema.GetJobResponse{}, err
+	}
+
+	jobStatus, err := GetJobStatus(jobKey)
+	if err != nil {
+		return schema.GetJobResponse{}, err
+	}
+
+	return schema.GetJobResponse{
+		APISpec:   *apiSpec,
+		JobStatus: *jobStatus,
+		Endpoint:  gatewayEndpoint(apiSpec) + "/" + jobKey.ID,
+	}, nil
+}
+
+// returns the statuses of the api's jobs, most recently submitted first
+func listJobStatuses(apiName string) ([]status.JobStatus, error) {
+	if !files.IsDir(jobsDir(apiName)) {
+		return nil, nil
+	}
+
+	jobIDs, err := files.ListDir(jobsDir(apiName), true)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(jobIDs) // job ids are monotonically decreasing
+
+	jobStatuses := []status.JobStatus{}
+	for _, jobID := range jobIDs {
+		jobStatus, err := GetJobStatus(spec.JobKey{ID: jobID, APIName: apiName})
+		if err != nil {
+			if errors.GetKind(err) == ErrJobNotFound {
+				continue
+			}
+			return nil, err
+		}
+		jobStatuses = append(jobStatuses, *jobStatus)
+	}
+
+	return jobStatuses, nil
+}
+
+func StopJob(jobKey spec.JobKey) (schema.DeleteResponse, error) {
+	_, err := docker.GetDockerClient()
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	jobStatus, err := GetJobStatus(jobKey)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	if !jobStatus.Status.IsInProgress() {
+		return schema.DeleteResponse{}, ErrorJobIsNotInProgress()
+	}
+
+	err = errors.FirstError(
+		writeJobState(jobKey, status.JobStopped),
+		DeleteJobContainers(jobKey),
+	)
+	if err != nil {
+		return schema.DeleteResponse{}, err
+	}
+
+	// remove the remaining batches so that they aren't counted as being in the queue
+	for _, dirName := range []string{_jobQueueDirName, _jobInProgressDirName} {
+		os.RemoveAll(filepath.Join(jobDir(jobKey), dirName))
+	}
+
+	return schema.DeleteResponse{
+		Message: fmt.Sprintf("stopped job %s", jobKey.ID),
+	}, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/gorilla/mux"
+)
+
+// batchAPIHandler serves the job endpoint of a local BatchAPI
+func batchAPIHandler(apiSpec *spec.API, awsClient *aws.Client) http.Handler {
+	router := mux.NewRouter()
+
+	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		// max payload size, same as API Gateway
+		bodyBytes, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, 10<<20))
+		if err != nil {
+			respondError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		submission := schema.JobSubmission{}
+		if err := json.Unmarshal(bodyBytes, &submission); err != nil {
+			respondError(w, errors.Append(err, fmt.Sprintf("\n\njob submission schema can be found at https://docs.cortex.dev/v/%s/deployments/batchapi/endpoints", consts.CortexVersionMinor)), http.StatusBadRequest)
+			return
+		}
+
+		jobSpec, err := SubmitJob(apiSpec, &submission, awsClient)
+		if err != nil {
+			respondError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		respond(w, jobSpec)
+	}).Methods("POST")
+
+	router.HandleFunc("/{jobID}", func(w http.ResponseWriter, r *http.Request) {
+		jobResponse, err := GetJob(spec.JobKey{ID: mux.Vars(r)["jobID"], APIName: apiSpec.Name})
+		if err != nil {
+			respondError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		respond(w, jobResponse)
+	}).Methods("GET")
+
+	router.HandleFunc("/{jobID}", func(w http.ResponseWriter, r *http.Request) {
+		deleteResponse, err := StopJob(spec.JobKey{ID: mux.Vars(r)["jobID"], APIName: apiSpec.Name})
+		if err != nil {
+			respondError(w, err, http.StatusBadRequest)
+			return
+		}
+
+		respond(w, deleteResponse)
+	}).Methods("DELETE")
+
+	fmt.Printf("serving the job endpoint for %s on %s\n", apiSpec.Identify(), gatewayEndpoint(apiSpec))
+
+	return router
+}
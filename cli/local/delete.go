@@ -26,6 +26,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/prompt"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
 func Delete(apiName string, keepCache, deleteForce bool) (schema.DeleteResponse, error) {
@@ -66,6 +67,12 @@ func Delete(apiName string, keepCache, deleteForce bool) (schema.DeleteResponse,
 		return schema.DeleteResponse{}, DeleteAPI(apiName)
 	}
 
+	if apiSpec.Kind == userconfig.SyncAPIKind {
+		if err := checkIfUsedByAPISplitter(apiName); err != nil {
+			return schema.DeleteResponse{}, err
+		}
+	}
+
 	if keepCache {
 		err = DeleteAPI(apiName)
 	} else {
@@ -82,3 +89,28 @@ func Delete(apiName string, keepCache, deleteForce bool) (schema.DeleteResponse,
 		Message: fmt.Sprintf("deleting %s", apiName),
 	}, nil
 }
+
+// checkIfUsedByAPISplitter checks if the api is used by a deployed APISplitter
+func checkIfUsedByAPISplitter(apiName string) error {
+	apiSpecs, err := ListAPISpecs()
+	if err != nil {
+		return err
+	}
+
+	var usedByAPISplitters []string
+	for _, apiSpec := range apiSpecs {
+		if apiSpec.Kind != userconfig.APISplitterKind {
+			continue
+		}
+		for _, trafficSplit := range apiSpec.APIs {
+			if trafficSplit.Name == apiName {
+				usedByAPISplitters = append(usedByAPISplitters, apiSpec.Name)
+			}
+		}
+	}
+
+	if len(usedByAPISplitters) > 0 {
+		return ErrorAPIUsedByAPISplitter(usedByAPISplitters)
+	}
+	return nil
+}
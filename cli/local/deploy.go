@@ -19,6 +19,7 @@ package local
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/cortexlabs/cortex/cli/types/cliconfig"
 	"github.com/cortexlabs/cortex/pkg/consts"
@@ -26,12 +27,26 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/docker"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func Deploy(env cliconfig.Environment, configPath string, projectFileList []string, deployDisallowPrompt bool) (schema.DeployResponse, error) {
+func Deploy(env cliconfig.Environment, configPath string, projectFileList []string, deployDisallowPrompt bool, modelCacheMaxSize *k8s.Quantity) (schema.DeployResponse, error) {
+	return deploy(env, configPath, projectFileList, nil, deployDisallowPrompt, modelCacheMaxSize)
+}
+
+// Redeploy updates the apis which are affected by the changed paths: apis whose configuration changed, and apis which use
+// one of the changed files (e.g. a file in the predictor's directory, or a local model). The other apis are left running.
+func Redeploy(env cliconfig.Environment, configPath string, projectFileList []string, changedPaths []string, deployDisallowPrompt bool, modelCacheMaxSize *k8s.Quantity) (schema.DeployResponse, error) {
+	return deploy(env, configPath, projectFileList, changedPaths, deployDisallowPrompt, modelCacheMaxSize)
+}
+
+// if changedPaths is nil, all apis are updated
+func deploy(env cliconfig.Environment, configPath string, projectFileList []string, changedPaths []string, deployDisallowPrompt bool, modelCacheMaxSize *k8s.Quantity) (schema.DeployResponse, error) {
 	configFileName := filepath.Base(configPath)
 
 	_, err := docker.GetDockerClient()
@@ -49,17 +64,9 @@ func Deploy(env cliconfig.Environment, configPath string, projectFileList []stri
 		return schema.DeployResponse{}, err
 	}
 
-	var awsClient *aws.Client
-	if env.AWSAccessKeyID != nil {
-		awsClient, err = aws.NewFromCreds(*env.AWSRegion, *env.AWSAccessKeyID, *env.AWSSecretAccessKey)
-		if err != nil {
-			return schema.DeployResponse{}, err
-		}
-	} else {
-		awsClient, err = aws.NewAnonymousClient()
-		if err != nil {
-			return schema.DeployResponse{}, err
-		}
+	awsClient, err := newAWSClient(env)
+	if err != nil {
+		return schema.DeployResponse{}, err
 	}
 
 	apiConfigs, err := spec.ExtractAPIConfigs(configBytes, types.LocalProviderType, configFileName)
@@ -69,7 +76,7 @@ func Deploy(env cliconfig.Environment, configPath string, projectFileList []stri
 
 	err = ValidateLocalAPIs(apiConfigs, projectFiles, awsClient)
 	if err != nil {
-		err = errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Sync API can be found at https://docs.cortex.dev/v/%s/deployments/syncapi/api-configuration", consts.CortexVersionMinor))
+		err = errors.Append(err, fmt.Sprintf("\n\napi configuration schema for:\n\nSync API can be found at https://docs.cortex.dev/v/%s/deployments/syncapi/api-configuration\nBatch API can be found at https://docs.cortex.dev/v/%s/deployments/batchapi/api-configuration", consts.CortexVersionMinor, consts.CortexVersionMinor))
 		return schema.DeployResponse{}, err
 	}
 
@@ -80,7 +87,15 @@ func Deploy(env cliconfig.Environment, configPath string, projectFileList []stri
 
 	results := make([]schema.DeployResult, len(apiConfigs))
 	for i, apiConfig := range apiConfigs {
-		api, msg, err := UpdateAPI(&apiConfig, configPath, projectID, deployDisallowPrompt, awsClient)
+		apiProjectID := projectID
+		if changedPaths != nil && !isAPIAffectedByPaths(&apiConfig, configPath, changedPaths) {
+			// the api is only updated if its configuration changed (in which case its ID won't match the deployed api's ID)
+			if prevAPISpec, err := FindAPISpec(apiConfig.Name); err == nil {
+				apiProjectID = prevAPISpec.ProjectID
+			}
+		}
+
+		api, msg, err := UpdateAPI(&apiConfig, configPath, apiProjectID, deployDisallowPrompt, env.Name, modelCacheMaxSize, awsClient)
 		results[i].Message = msg
 		if err != nil {
 			results[i].Error = errors.Message(err)
@@ -93,3 +108,54 @@ func Deploy(env cliconfig.Environment, configPath string, projectFileList []stri
 		Results: results,
 	}, nil
 }
+
+// files in the root of the project which are used by every api (see run.sh)
+var _sharedProjectFileNames = []string{"requirements.txt", "conda-packages.txt", "dependencies.sh", ".env"}
+
+// changes to the api configuration file are detected by comparing each api's configuration with the deployed api
+func isAPIAffectedByPaths(apiConfig *userconfig.API, configPath string, changedPaths []string) bool {
+	if apiConfig.Predictor == nil {
+		return false
+	}
+
+	projectRoot := filepath.Dir(configPath)
+
+	// if python_path isn't set, the project root is on the python path, so the predictor can import any file in the project
+	if apiConfig.Predictor.PythonPath == nil {
+		for _, changedPath := range changedPaths {
+			if changedPath != configPath {
+				return true
+			}
+		}
+		return false
+	}
+
+	var affectedPaths []string
+	for _, fileName := range _sharedProjectFileNames {
+		affectedPaths = append(affectedPaths, filepath.Join(projectRoot, fileName))
+	}
+	affectedPaths = append(affectedPaths, filepath.Dir(filepath.Join(projectRoot, apiConfig.Predictor.Path)))
+	affectedPaths = append(affectedPaths, filepath.Join(projectRoot, *apiConfig.Predictor.PythonPath))
+	for _, modelResource := range apiConfig.Predictor.Models {
+		if !strings.HasPrefix(modelResource.ModelPath, "s3://") {
+			affectedPaths = append(affectedPaths, files.RelToAbsPath(modelResource.ModelPath, projectRoot))
+		}
+	}
+
+	for _, changedPath := range changedPaths {
+		for _, affectedPath := range affectedPaths {
+			// affected paths can be files or directories
+			if changedPath == affectedPath || strings.HasPrefix(changedPath, s.EnsureSuffix(affectedPath, "/")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newAWSClient(env cliconfig.Environment) (*aws.Client, error) {
+	if env.AWSAccessKeyID != nil {
+		return aws.NewFromCreds(*env.AWSRegion, *env.AWSAccessKeyID, *env.AWSSecretAccessKey)
+	}
+	return aws.NewAnonymousClient()
+}
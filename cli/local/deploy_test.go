@@ -0,0 +1,58 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAPIAffectedByPaths(t *testing.T) {
+	configPath := "/project/cortex.yaml"
+
+	apiConfig := &userconfig.API{
+		Predictor: &userconfig.Predictor{
+			Path: "iris/predictor.py",
+		},
+	}
+
+	// without python_path, any project file can be imported by the predictor
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/iris/predictor.py"}))
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/shared/utils.py"}))
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/requirements.txt"}))
+	require.False(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/cortex.yaml"}))
+
+	apiConfig.Predictor.PythonPath = pointer.String("lib")
+	apiConfig.Predictor.Models = []*userconfig.ModelResource{
+		{Name: "local", ModelPath: "models/iris"},
+		{Name: "remote", ModelPath: "s3://cortex-examples/iris"},
+	}
+
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/iris/predictor.py"}))
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/iris/sub/helpers.py"}))
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/lib/utils.py"}))
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/models/iris/1/saved_model.pb"}))
+	require.True(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/requirements.txt"}))
+	require.False(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/shared/utils.py"}))
+	require.False(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/iris2/predictor.py"}))
+	require.False(t, isAPIAffectedByPaths(apiConfig, configPath, []string{"/project/cortex.yaml"}))
+
+	require.False(t, isAPIAffectedByPaths(&userconfig.API{}, configPath, []string{"/project/iris/predictor.py"}))
+}
@@ -41,6 +41,7 @@ import (
 const (
 	_apiContainerName          = "api"
 	_tfServingContainerName    = "serve"
+	_workerContainerName       = "worker"
 	_defaultPortStr            = "8888"
 	_tfServingPortStr          = "9000"
 	_tfServingEmptyModelConfig = "/etc/tfs/model_config_server.conf"
@@ -49,6 +50,7 @@ const (
 	_cacheDir                  = "/mnt/cache"
 	_modelDir                  = "/mnt/model"
 	_workspaceDir              = "/mnt/workspace"
+	_jobDir                    = "/mnt/job"
 )
 
 type ModelCaches []*spec.LocalModelCache
@@ -287,72 +289,19 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client) error {
 		}
 	}
 
-	mounts := []mount.Mount{}
-	for _, modelCache := range api.LocalModelCaches {
-		mounts = append(mounts, mount.Mount{
-			Type:   mount.TypeBind,
-			Source: modelCache.HostPath,
-			Target: filepath.Join(_modelDir, modelCache.TargetPath),
-		})
-	}
-
-	serveHostConfig := &container.HostConfig{
-		Runtime:   serveRuntime,
-		Resources: serveResources,
-		Mounts:    mounts,
-	}
-
-	envVars := []string{}
-	cmdArgs := []string{
-		"--port=" + _tfServingPortStr,
-		"--model_config_file=" + _tfServingEmptyModelConfig,
-	}
-	if api.Predictor.ServerSideBatching != nil {
-		envVars = append(envVars,
-			"TF_MAX_BATCH_SIZE="+s.Int32(api.Predictor.ServerSideBatching.MaxBatchSize),
-			"TF_BATCH_TIMEOUT_MICROS="+s.Int64(api.Predictor.ServerSideBatching.BatchInterval.Microseconds()),
-			"TF_NUM_BATCHED_THREADS="+s.Int32(api.Predictor.ProcessesPerReplica),
-		)
-		cmdArgs = append(cmdArgs,
-			"--enable_batching=true",
-			"--batching_parameters_file="+_tfServingBatchConfig,
-		)
-	}
-
-	serveContainerConfig := &container.Config{
-		Image: api.Predictor.TensorFlowServingImage,
-		Tty:   true,
-		Env:   envVars,
-		Cmd:   cmdArgs,
-		ExposedPorts: nat.PortSet{
-			_tfServingPortStr + "/tcp": struct{}{},
-		},
-		Labels: map[string]string{
-			"cortex":   "true",
-			"type":     _tfServingContainerName,
-			"apiID":    api.ID,
-			"apiName":  api.Name,
-			"modelIDs": ModelCaches(api.LocalModelCaches).IDs(),
-		},
-	}
-
-	containerCreateRequest, err := docker.MustDockerClient().ContainerCreate(context.Background(), serveContainerConfig, serveHostConfig, nil, "")
-	if err != nil {
-		return errors.Wrap(err, api.Identify())
-	}
-
-	err = docker.MustDockerClient().ContainerStart(context.Background(), containerCreateRequest.ID, dockertypes.ContainerStartOptions{})
-	if err != nil {
-		return errors.Wrap(err, api.Identify())
-	}
+	mounts := modelCacheMounts(api)
 
-	containerInfo, err := docker.MustDockerClient().ContainerInspect(context.Background(), containerCreateRequest.ID)
+	tfContainerHost, err := deployTensorFlowServingContainer(api, serveResources, serveRuntime, map[string]string{
+		"cortex":   "true",
+		"type":     _tfServingContainerName,
+		"apiID":    api.ID,
+		"apiName":  api.Name,
+		"modelIDs": ModelCaches(api.LocalModelCaches).IDs(),
+	})
 	if err != nil {
-		return errors.Wrap(err, api.Identify())
+		return err
 	}
 
-	tfContainerHost := containerInfo.NetworkSettings.Networks["bridge"].IPAddress
-
 	portBinding := nat.PortBinding{}
 	if api.Networking.LocalPort != nil {
 		portBinding.HostPort = fmt.Sprintf("%d", *api.Networking.LocalPort)
@@ -395,7 +344,7 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client) error {
 			"modelIDs": ModelCaches(api.LocalModelCaches).IDs(),
 		},
 	}
-	containerCreateRequest, err = docker.MustDockerClient().ContainerCreate(context.Background(), apiContainerConfig, apiHostConfig, nil, "")
+	containerCreateRequest, err := docker.MustDockerClient().ContainerCreate(context.Background(), apiContainerConfig, apiHostConfig, nil, "")
 	if err != nil {
 		return errors.Wrap(err, api.Identify())
 	}
@@ -408,6 +357,178 @@ func deployTensorFlowContainers(api *spec.API, awsClient *aws.Client) error {
 	return nil
 }
 
+// DeployWorkerContainers starts a job's workers, which process the batches in the job's local queue and then exit
+func DeployWorkerContainers(api *spec.API, job *spec.Job, awsClient *aws.Client) error {
+	runtime := ""
+	resources := container.Resources{}
+	serveResources := container.Resources{}
+	if api.Compute != nil {
+		if api.Compute.CPU != nil {
+			resources.NanoCPUs = api.Compute.CPU.MilliValue() * 1000 * 1000
+		}
+		if api.Compute.Mem != nil {
+			resources.Memory = api.Compute.Mem.Quantity.Value()
+		}
+		if api.Compute.GPU > 0 {
+			runtime = "nvidia"
+		}
+	}
+
+	labels := func(containerType string) map[string]string {
+		return map[string]string{
+			"cortex":   "true",
+			"type":     containerType,
+			"apiID":    api.ID,
+			"apiName":  api.Name,
+			"jobID":    job.ID,
+			"modelIDs": ModelCaches(api.LocalModelCaches).IDs(),
+		}
+	}
+
+	envs := append(
+		getAPIEnv(api, awsClient),
+		"CORTEX_JOB_SPEC="+filepath.Join(_jobDir, "spec.json"),
+	)
+
+	mounts := []mount.Mount{
+		{
+			Type:   mount.TypeBind,
+			Source: api.LocalProjectDir,
+			Target: _projectDir,
+		},
+		{
+			Type:   mount.TypeBind,
+			Source: filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key)),
+			Target: _workspaceDir,
+		},
+		{
+			Type:   mount.TypeBind,
+			Source: jobDir(job.JobKey),
+			Target: _jobDir,
+		},
+	}
+
+	if api.Predictor.Type == userconfig.TensorFlowPredictorType {
+		// the job's workers share a single tensorflow serving container, which gets half of each worker's compute
+		serveRuntime := runtime
+		runtime = ""
+		if resources.NanoCPUs > 0 {
+			resources.NanoCPUs = resources.NanoCPUs / 2
+			serveResources.NanoCPUs = resources.NanoCPUs * int64(job.Workers)
+		}
+		if resources.Memory > 0 {
+			resources.Memory = resources.Memory / 2
+			serveResources.Memory = resources.Memory * int64(job.Workers)
+		}
+
+		tfContainerHost, err := deployTensorFlowServingContainer(api, serveResources, serveRuntime, labels(_tfServingContainerName))
+		if err != nil {
+			return err
+		}
+
+		envs = append(envs,
+			"CORTEX_TF_BASE_SERVING_PORT="+_tfServingPortStr,
+			"CORTEX_TF_SERVING_HOST="+tfContainerHost,
+		)
+	} else {
+		mounts = append(mounts, modelCacheMounts(api)...)
+	}
+
+	hostConfig := &container.HostConfig{
+		Runtime:   runtime,
+		Resources: resources,
+		Mounts:    mounts,
+	}
+
+	containerConfig := &container.Config{
+		Image:  api.Predictor.Image,
+		Tty:    true,
+		Env:    envs,
+		Labels: labels(_workerContainerName),
+	}
+
+	for i := 0; i < job.Workers; i++ {
+		containerInfo, err := docker.MustDockerClient().ContainerCreate(context.Background(), containerConfig, hostConfig, nil, "")
+		if err != nil {
+			return errors.Wrap(err, job.UserString())
+		}
+
+		err = docker.MustDockerClient().ContainerStart(context.Background(), containerInfo.ID, dockertypes.ContainerStartOptions{})
+		if err != nil {
+			return errors.Wrap(err, job.UserString())
+		}
+	}
+
+	return nil
+}
+
+func modelCacheMounts(api *spec.API) []mount.Mount {
+	mounts := []mount.Mount{}
+	for _, modelCache := range api.LocalModelCaches {
+		mounts = append(mounts, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: modelCache.HostPath,
+			Target: filepath.Join(_modelDir, modelCache.TargetPath),
+		})
+	}
+	return mounts
+}
+
+// returns the container's ip address on the bridge network
+func deployTensorFlowServingContainer(api *spec.API, resources container.Resources, runtime string, labels map[string]string) (string, error) {
+	serveHostConfig := &container.HostConfig{
+		Runtime:   runtime,
+		Resources: resources,
+		Mounts:    modelCacheMounts(api),
+	}
+
+	envVars := []string{}
+	cmdArgs := []string{
+		"--port=" + _tfServingPortStr,
+		"--model_config_file=" + _tfServingEmptyModelConfig,
+	}
+	if api.Predictor.ServerSideBatching != nil {
+		envVars = append(envVars,
+			"TF_MAX_BATCH_SIZE="+s.Int32(api.Predictor.ServerSideBatching.MaxBatchSize),
+			"TF_BATCH_TIMEOUT_MICROS="+s.Int64(api.Predictor.ServerSideBatching.BatchInterval.Microseconds()),
+			"TF_NUM_BATCHED_THREADS="+s.Int32(api.Predictor.ProcessesPerReplica),
+		)
+		cmdArgs = append(cmdArgs,
+			"--enable_batching=true",
+			"--batching_parameters_file="+_tfServingBatchConfig,
+		)
+	}
+
+	serveContainerConfig := &container.Config{
+		Image: api.Predictor.TensorFlowServingImage,
+		Tty:   true,
+		Env:   envVars,
+		Cmd:   cmdArgs,
+		ExposedPorts: nat.PortSet{
+			_tfServingPortStr + "/tcp": struct{}{},
+		},
+		Labels: labels,
+	}
+
+	containerCreateRequest, err := docker.MustDockerClient().ContainerCreate(context.Background(), serveContainerConfig, serveHostConfig, nil, "")
+	if err != nil {
+		return "", errors.Wrap(err, api.Identify())
+	}
+
+	err = docker.MustDockerClient().ContainerStart(context.Background(), containerCreateRequest.ID, dockertypes.ContainerStartOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, api.Identify())
+	}
+
+	containerInfo, err := docker.MustDockerClient().ContainerInspect(context.Background(), containerCreateRequest.ID)
+	if err != nil {
+		return "", errors.Wrap(err, api.Identify())
+	}
+
+	return containerInfo.NetworkSettings.Networks["bridge"].IPAddress, nil
+
+}
+
 func GetContainersByAPI(apiName string) ([]dockertypes.Container, error) {
 	dargs := filters.NewArgs()
 	dargs.Add("label", "cortex=true")
@@ -438,6 +559,23 @@ func GetAllRunningContainers() ([]dockertypes.Container, error) {
 	return containers, nil
 }
 
+func GetContainersByJob(jobKey spec.JobKey) ([]dockertypes.Container, error) {
+	dargs := filters.NewArgs()
+	dargs.Add("label", "cortex=true")
+	dargs.Add("label", "apiName="+jobKey.APIName)
+	dargs.Add("label", "jobID="+jobKey.ID)
+
+	containers, err := docker.MustDockerClient().ContainerList(context.Background(), types.ContainerListOptions{
+		All:     true,
+		Filters: dargs,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, jobKey.UserString())
+	}
+
+	return containers, nil
+}
+
 func DeleteContainers(apiName string) error {
 	containers, err := GetContainersByAPI(apiName)
 	if err != nil {
@@ -458,3 +596,24 @@ func DeleteContainers(apiName string) error {
 	}
 	return nil
 }
+
+func DeleteJobContainers(jobKey spec.JobKey) error {
+	containers, err := GetContainersByJob(jobKey)
+	if err != nil {
+		return err
+	}
+
+	for _, container := range containers {
+		attemptErr := docker.MustDockerClient().ContainerRemove(context.Background(), container.ID, dockertypes.ContainerRemoveOptions{
+			RemoveVolumes: true,
+			Force:         true,
+		})
+		if attemptErr != nil {
+			err = attemptErr
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, jobKey.UserString())
+	}
+	return nil
+}
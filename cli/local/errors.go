@@ -21,6 +21,8 @@ import (
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
 const (
@@ -34,6 +36,13 @@ const (
 	ErrDuplicateLocalPort            = "local.duplicate_local_port"
 	ErrPortAlreadyInUse              = "local.port_already_in_use"
 	ErrUnableToFindAvailablePorts    = "local.unable_to_find_available_ports"
+	ErrJobNotFound                   = "local.job_not_found"
+	ErrJobIsNotInProgress            = "local.job_is_not_in_progress"
+	ErrJobWorkersNotFound            = "local.job_workers_not_found"
+	ErrFieldNotSupportedLocally      = "local.field_not_supported_locally"
+	ErrGatewayFailedToStart          = "local.gateway_failed_to_start"
+	ErrAPIUsedByAPISplitter          = "local.api_used_by_api_splitter"
+	ErrNotDeployedAPIsAPISplitter    = "local.not_deployed_apis_api_splitter"
 )
 
 func ErrorAPINotDeployed(apiName string) error {
@@ -116,3 +125,52 @@ func ErrorUnableToFindAvailablePorts() error {
 		Message: fmt.Sprintf("unable to find available ports"),
 	})
 }
+
+func ErrorJobNotFound(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobNotFound,
+		Message: fmt.Sprintf("unable to find batch job %s", jobKey.UserString()),
+	})
+}
+
+func ErrorJobIsNotInProgress() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobIsNotInProgress,
+		Message: "cannot stop batch job because it is not in progress",
+	})
+}
+
+func ErrorJobWorkersNotFound(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrJobWorkersNotFound,
+		Message: fmt.Sprintf("unable to find the workers of batch job %s (the workers of a job are removed when the job is stopped)", jobKey.UserString()),
+	})
+}
+
+func ErrorFieldNotSupportedLocally(field string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldNotSupportedLocally,
+		Message: fmt.Sprintf("%s is not supported for jobs submitted to batch apis in the local environment", field),
+	})
+}
+
+func ErrorGatewayFailedToStart(apiName string, logPath string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrGatewayFailedToStart,
+		Message: fmt.Sprintf("failed to start the endpoint for %s; see %s for more information", apiName, logPath),
+	})
+}
+
+func ErrorAPIUsedByAPISplitter(apiSplitters []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIUsedByAPISplitter,
+		Message: fmt.Sprintf("cannot delete api because it is used by the following %s: %s", s.PluralS("APISplitter", len(apiSplitters)), s.StrsSentence(apiSplitters, "")),
+	})
+}
+
+func ErrorNotDeployedAPIsAPISplitter(notDeployedAPIs []string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNotDeployedAPIsAPISplitter,
+		Message: fmt.Sprintf("unable to find specified %s: %s", s.PluralS("SyncAPI", len(notDeployedAPIs)), s.StrsAnd(notDeployedAPIs)),
+	})
+}
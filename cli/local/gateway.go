@@ -0,0 +1,246 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package local
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cortexlabs/cortex/cli/types/cliconfig"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/files"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// The endpoints of local BatchAPIs (job submission) and APISplitters (traffic splitting) are served by a detached cortex process
+// (the hidden `cortex _gateway` command), which is started when the api is deployed and stopped when the api is deleted
+const (
+	_gatewayCommand     = "_gateway" // prefixed so that it doesn't interfere with command prefix matching
+	_gatewayPIDFileName = "gateway.pid"
+	_gatewayLogFileName = "gateway.log"
+	_gatewayStartupTime = 10 * time.Second
+)
+
+func gatewayPIDPath(apiName string) string {
+	return filepath.Join(_localWorkspaceDir, "apis", apiName, _gatewayPIDFileName)
+}
+
+func gatewayLogPath(apiName string) string {
+	return filepath.Join(_localWorkspaceDir, "apis", apiName, _gatewayLogFileName)
+}
+
+func isServedByGateway(kind userconfig.Kind) bool {
+	return kind == userconfig.BatchAPIKind || kind == userconfig.APISplitterKind
+}
+
+func gatewayEndpoint(apiSpec *spec.API) string {
+	if apiSpec.Networking.LocalPort == nil {
+		return ""
+	}
+	return "http://" + gatewayAddress(*apiSpec.Networking.LocalPort)
+}
+
+func startGateway(apiSpec *spec.API, envName string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	logFile, err := files.Create(gatewayLogPath(apiSpec.Name))
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(executable, _gatewayCommand, apiSpec.Name, "--env", envName)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	// run in a new session so that the process outlives the cli command which started it
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, apiSpec.Identify())
+	}
+
+	if err := files.WriteFile([]byte(s.Int(cmd.Process.Pid)), gatewayPIDPath(apiSpec.Name)); err != nil {
+		cmd.Process.Kill()
+		return err
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	for start := time.Now(); time.Since(start) < _gatewayStartupTime; time.Sleep(100 * time.Millisecond) {
+		select {
+		case <-exited:
+			return ErrorGatewayFailedToStart(apiSpec.Name, gatewayLogPath(apiSpec.Name))
+		default:
+		}
+
+		if isGatewayListening(*apiSpec.Networking.LocalPort) {
+			return nil
+		}
+	}
+
+	return ErrorGatewayFailedToStart(apiSpec.Name, gatewayLogPath(apiSpec.Name))
+}
+
+func isGatewayListening(port int) bool {
+	conn, err := net.DialTimeout("tcp", gatewayAddress(port), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// the gateway is only reachable from this machine
+func gatewayAddress(port int) string {
+	return "localhost:" + s.Int(port)
+}
+
+// Returns nil if the gateway isn't running (the pid file is only trusted if the process with that pid is the api's gateway,
+// since the pid may have been reused by an unrelated process after the gateway exited)
+func getGatewayProcess(apiName string) *os.Process {
+	pidBytes, err := files.ReadFileBytes(gatewayPIDPath(apiName))
+	if err != nil {
+		return nil
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(pidBytes)))
+	if err != nil {
+		return nil
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return nil
+	}
+
+	// signal 0 only checks whether the process exists
+	if err := process.Signal(syscall.Signal(0)); err != nil {
+		return nil
+	}
+
+	if !isGatewayCommand(pid, apiName) {
+		return nil
+	}
+
+	return process
+}
+
+// checks the command line of the process, e.g. `cortex _gateway API_NAME --env ENV`
+func isGatewayCommand(pid int, apiName string) bool {
+	output, err := exec.Command("ps", "-p", s.Int(pid), "-o", "command=").Output()
+	if err != nil {
+		return false
+	}
+
+	args := strings.Fields(string(output))
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == _gatewayCommand && args[i+1] == apiName {
+			return true
+		}
+	}
+	return false
+}
+
+func isGatewayRunning(apiName string) bool {
+	return getGatewayProcess(apiName) != nil
+}
+
+func stopGateway(apiName string) error {
+	process := getGatewayProcess(apiName)
+	if process != nil {
+		if err := process.Signal(syscall.SIGTERM); err != nil {
+			return errors.Wrap(err, "api", apiName)
+		}
+
+		// wait for the port to be released, since the api may be redeployed on the same port
+		for start := time.Now(); time.Since(start) < _gatewayStartupTime; time.Sleep(100 * time.Millisecond) {
+			if process.Signal(syscall.Signal(0)) != nil {
+				break
+			}
+		}
+	}
+
+	if err := os.Remove(gatewayPIDPath(apiName)); err != nil && !os.IsNotExist(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// ServeGateway serves the endpoint of a local BatchAPI or APISplitter until the process is stopped
+func ServeGateway(apiName string, env cliconfig.Environment) error {
+	apiSpec, err := FindAPISpec(apiName)
+	if err != nil {
+		return err
+	}
+
+	if apiSpec.Networking.LocalPort == nil {
+		return errors.ErrorUnexpected("local port was not set", apiSpec.Identify()) // unexpected
+	}
+
+	var handler http.Handler
+	switch apiSpec.Kind {
+	case userconfig.BatchAPIKind:
+		awsClient, err := newAWSClient(env)
+		if err != nil {
+			return err
+		}
+		handler = batchAPIHandler(apiSpec, awsClient)
+	case userconfig.APISplitterKind:
+		handler = apiSplitterHandler(apiSpec)
+	default:
+		return errors.ErrorUnexpected("api kind is not served by a gateway", apiSpec.Identify()) // unexpected
+	}
+
+	return errors.WithStack(http.ListenAndServe(gatewayAddress(*apiSpec.Networking.LocalPort), handler))
+}
+
+func respond(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+func respondError(w http.ResponseWriter, err error, statusCode int) {
+	errors.PrintError(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := schema.ErrorResponse{
+		Kind:    errors.GetKind(err),
+		Message: errors.Message(err),
+	}
+	json.NewEncoder(w).Encode(response)
+}
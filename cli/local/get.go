@@ -19,6 +19,7 @@ package local
 import (
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/docker"
@@ -28,9 +29,11 @@ import (
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func GetAPIs() (schema.GetAPIsResponse, error) {
+func GetAPIs(metricsWindow time.Duration) (schema.GetAPIsResponse, error) {
 	_, err := docker.GetDockerClient()
 	if err != nil {
 		return schema.GetAPIsResponse{}, err
@@ -41,27 +44,58 @@ func GetAPIs() (schema.GetAPIsResponse, error) {
 		return schema.GetAPIsResponse{}, err
 	}
 
-	syncAPIs := make([]schema.SyncAPI, len(apiSpecList))
-	for i, apiSpec := range apiSpecList {
+	syncAPIs := []schema.SyncAPI{}
+	batchAPIs := []schema.BatchAPI{}
+	apiSplitters := []schema.APISplitter{}
+	for i := range apiSpecList {
+		apiSpec := apiSpecList[i]
+
+		if apiSpec.Kind == userconfig.APISplitterKind {
+			apiSplitters = append(apiSplitters, schema.APISplitter{
+				Spec:     apiSpec,
+				Endpoint: gatewayEndpoint(&apiSpec),
+			})
+			continue
+		}
+
+		if apiSpec.Kind == userconfig.BatchAPIKind {
+			jobStatuses, err := listJobStatuses(apiSpec.Name)
+			if err != nil {
+				return schema.GetAPIsResponse{}, err
+			}
+			if len(jobStatuses) > 1 {
+				jobStatuses = jobStatuses[:1]
+			}
+
+			batchAPIs = append(batchAPIs, schema.BatchAPI{
+				Spec:        apiSpec,
+				JobStatuses: jobStatuses,
+				Endpoint:    gatewayEndpoint(&apiSpec),
+			})
+			continue
+		}
+
 		apiStatus, err := GetAPIStatus(&apiSpec)
 		if err != nil {
 			return schema.GetAPIsResponse{}, err
 		}
 
-		metrics, err := GetAPIMetrics(&apiSpec)
+		metrics, err := GetAPIMetrics(&apiSpec, metricsWindow)
 		if err != nil {
 			return schema.GetAPIsResponse{}, err
 		}
 
-		syncAPIs[i] = schema.SyncAPI{
+		syncAPIs = append(syncAPIs, schema.SyncAPI{
 			Spec:    apiSpec,
 			Status:  apiStatus,
 			Metrics: metrics,
-		}
+		})
 	}
 
 	return schema.GetAPIsResponse{
-		SyncAPIs: syncAPIs,
+		SyncAPIs:     syncAPIs,
+		BatchAPIs:    batchAPIs,
+		APISplitters: apiSplitters,
 	}, nil
 }
 
@@ -127,7 +161,7 @@ func ListVersionMismatchedAPIs() ([]string, error) {
 	return apiNames, nil
 }
 
-func GetAPI(apiName string) (schema.GetAPIResponse, error) {
+func GetAPI(apiName string, metricsWindow time.Duration) (schema.GetAPIResponse, error) {
 	_, err := docker.GetDockerClient()
 	if err != nil {
 		return schema.GetAPIResponse{}, err
@@ -138,12 +172,24 @@ func GetAPI(apiName string) (schema.GetAPIResponse, error) {
 		return schema.GetAPIResponse{}, err
 	}
 
+	switch apiSpec.Kind {
+	case userconfig.BatchAPIKind:
+		return getBatchAPI(apiSpec)
+	case userconfig.APISplitterKind:
+		return schema.GetAPIResponse{
+			APISplitter: &schema.APISplitter{
+				Spec:     *apiSpec,
+				Endpoint: gatewayEndpoint(apiSpec),
+			},
+		}, nil
+	}
+
 	apiStatus, err := GetAPIStatus(apiSpec)
 	if err != nil {
 		return schema.GetAPIResponse{}, err
 	}
 
-	apiMetrics, err := GetAPIMetrics(apiSpec)
+	apiMetrics, err := GetAPIMetrics(apiSpec, metricsWindow)
 	if err != nil {
 		return schema.GetAPIResponse{}, err
 	}
@@ -177,3 +223,34 @@ func GetAPI(apiName string) (schema.GetAPIResponse, error) {
 		},
 	}, nil
 }
+
+func getBatchAPI(apiSpec *spec.API) (schema.GetAPIResponse, error) {
+	jobStatuses, err := listJobStatuses(apiSpec.Name)
+	if err != nil {
+		return schema.GetAPIResponse{}, err
+	}
+
+	// in progress jobs, and then the most recently submitted jobs up to a total of 10 (same as the cluster)
+	displayedJobStatuses := []status.JobStatus{}
+	for _, jobStatus := range jobStatuses {
+		if jobStatus.Status.IsInProgress() {
+			displayedJobStatuses = append(displayedJobStatuses, jobStatus)
+		}
+	}
+	for _, jobStatus := range jobStatuses {
+		if len(displayedJobStatuses) >= 10 {
+			break
+		}
+		if !jobStatus.Status.IsInProgress() {
+			displayedJobStatuses = append(displayedJobStatuses, jobStatus)
+		}
+	}
+
+	return schema.GetAPIResponse{
+		BatchAPI: &schema.BatchAPI{
+			Spec:        *apiSpec,
+			JobStatuses: displayedJobStatuses,
+			Endpoint:    gatewayEndpoint(apiSpec),
+		},
+	}, nil
+}
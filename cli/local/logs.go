@@ -18,6 +18,7 @@ package local
 
 import (
 	"github.com/cortexlabs/cortex/pkg/lib/docker"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
 func StreamLogs(apiName string) error {
@@ -47,3 +48,33 @@ func StreamLogs(apiName string) error {
 
 	return docker.StreamDockerLogs(containerIDs[0], containerIDs[1:]...)
 }
+
+func StreamJobLogs(jobKey spec.JobKey) error {
+	_, err := docker.GetDockerClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = readJobSpec(jobKey)
+	if err != nil {
+		return err
+	}
+
+	containers, err := GetContainersByJob(jobKey)
+	if err != nil {
+		return err
+	}
+
+	var containerIDs []string
+	for _, container := range containers {
+		if container.Labels["type"] == _workerContainerName {
+			containerIDs = append(containerIDs, container.ID)
+		}
+	}
+
+	if len(containerIDs) == 0 {
+		return ErrorJobWorkersNotFound(jobKey)
+	}
+
+	return docker.StreamDockerLogs(containerIDs[0], containerIDs[1:]...)
+}
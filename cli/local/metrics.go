@@ -17,7 +17,9 @@ limitations under the License.
 package local
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"path/filepath"
 	"strings"
 	"time"
@@ -26,88 +28,138 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
 	"github.com/cortexlabs/cortex/pkg/lib/pointer"
-	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
 	"github.com/cortexlabs/cortex/pkg/types/metrics"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func GetAPIMetrics(api *spec.API) (metrics.Metrics, error) {
-	apiWorkspace := filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key))
+// the metrics store of an api is a directory of jsonl files (one per serving process, plus one rotated file each), written by the api containers
+const _localMetricsDirName = "metrics"
 
-	networkStats := metrics.NetworkStats{}
+// localMetricsRecord is a line of the metrics store; request records have a status code and latency, and prediction records have a predicted class or value
+type localMetricsRecord struct {
+	Timestamp      float64  `json:"timestamp"` // unix seconds
+	StatusCode     *int     `json:"status_code"`
+	Latency        *float64 `json:"latency"` // milliseconds
+	PredictedClass *string  `json:"predicted_class"`
+	PredictedValue *float64 `json:"predicted_value"`
+}
 
-	filepaths, err := files.ListDir(apiWorkspace, false)
-	if err != nil {
-		return metrics.Metrics{}, errors.Wrap(err, "api", api.Name)
+// GetAPIMetrics aggregates the metrics which were recorded within the window (or all recorded metrics if window is 0)
+func GetAPIMetrics(api *spec.API, window time.Duration) (metrics.Metrics, error) {
+	metricsDir := filepath.Join(_localWorkspaceDir, filepath.Dir(api.Key), _localMetricsDirName)
+
+	var since float64
+	if window > 0 {
+		since = float64(time.Now().Add(-window).UnixNano()) / float64(time.Second)
 	}
 
-	totalRequestTime := 0.0
-	for _, filepath := range filepaths {
-		if strings.HasSuffix(filepath, ".2XX") {
-			fileContent, err := files.ReadFile(filepath)
-			if err != nil {
-				return metrics.Metrics{}, errors.Wrap(err, "api", api.Name)
-			}
-			count, ok := s.ParseInt(fileContent)
-			if !ok {
-				count = 0
-			}
-			networkStats.Code2XX += int(count)
+	networkStats := metrics.NetworkStats{}
+	var classDistribution map[string]int
+	var regressionStats *metrics.RegressionStats
+
+	var filepaths []string
+	if files.IsDir(metricsDir) {
+		var err error
+		filepaths, err = files.ListDir(metricsDir, false)
+		if err != nil {
+			return metrics.Metrics{}, errors.Wrap(err, "api", api.Name)
 		}
+	}
 
-		if strings.HasSuffix(filepath, ".4XX") {
-			fileContent, err := files.ReadFile(filepath)
-			if err != nil {
-				return metrics.Metrics{}, errors.Wrap(err, "api", api.Name)
-			}
+	totalLatency := 0.0
+	regressionSum := 0.0
+	for _, filepath := range filepaths {
+		if !strings.HasSuffix(filepath, ".jsonl") && !strings.HasSuffix(filepath, ".jsonl.1") {
+			continue
+		}
 
-			count, ok := s.ParseInt(fileContent)
-			if !ok {
-				count = 0
-			}
-			networkStats.Code4XX += int(count)
+		records, err := readLocalMetricsRecords(filepath)
+		if err != nil {
+			return metrics.Metrics{}, errors.Wrap(err, "api", api.Name)
 		}
 
-		if strings.HasSuffix(filepath, ".5XX") {
-			fileContent, err := files.ReadFile(filepath)
-			if err != nil {
-				return metrics.Metrics{}, errors.Wrap(err, "api", api.Name)
+		for _, record := range records {
+			if record.Timestamp < since {
+				continue
 			}
 
-			count, ok := s.ParseInt(fileContent)
-			if !ok {
-				count = 0
+			if record.StatusCode != nil {
+				switch *record.StatusCode / 100 {
+				case 2:
+					networkStats.Code2XX++
+				case 4:
+					networkStats.Code4XX++
+				case 5:
+					networkStats.Code5XX++
+				}
+				networkStats.Total++
+				if record.Latency != nil {
+					totalLatency += *record.Latency
+				}
 			}
-			networkStats.Code5XX += int(count)
-		}
 
-		if strings.HasSuffix(filepath, ".request_time") {
-			fileContent, err := files.ReadFile(filepath)
-			if err != nil {
-				return metrics.Metrics{}, errors.Wrap(err, "api", api.Name)
+			if record.PredictedClass != nil {
+				if classDistribution == nil {
+					classDistribution = map[string]int{}
+				}
+				classDistribution[*record.PredictedClass]++
 			}
 
-			requestTime, ok := s.ParseFloat64(fileContent)
-			if !ok {
-				requestTime = 0
+			if record.PredictedValue != nil {
+				value := *record.PredictedValue
+				if regressionStats == nil {
+					regressionStats = &metrics.RegressionStats{}
+				}
+				regressionStats.Min = slices.Float64PtrMin(regressionStats.Min, &value)
+				regressionStats.Max = slices.Float64PtrMax(regressionStats.Max, &value)
+				regressionStats.SampleCount++
+				regressionSum += value
 			}
-			totalRequestTime += requestTime
 		}
 	}
 
-	totalRequests := networkStats.Code2XX + networkStats.Code4XX + networkStats.Code5XX
-	networkStats.Total = totalRequests
-	if totalRequests != 0 && totalRequestTime != 0 {
-		networkStats.Latency = pointer.Float64(totalRequestTime / float64(totalRequests))
+	if networkStats.Total != 0 {
+		networkStats.Latency = pointer.Float64(totalLatency / float64(networkStats.Total))
 	}
+	if regressionStats != nil {
+		regressionStats.Avg = pointer.Float64(regressionSum / float64(regressionStats.SampleCount))
+	}
+
 	return metrics.Metrics{
-		APIName:      api.Name,
-		NetworkStats: &networkStats,
+		APIName:           api.Name,
+		NetworkStats:      &networkStats,
+		ClassDistribution: classDistribution,
+		RegressionStats:   regressionStats,
 	}, nil
 }
 
+func readLocalMetricsRecords(path string) ([]localMetricsRecord, error) {
+	file, err := files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []localMetricsRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record localMetricsRecord
+		// the last line may be partially written
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+
+	return records, nil
+}
+
 func GetAPIStatus(api *spec.API) (status.Status, error) {
 	apiStatus := status.Status{
 		APIID:   api.ID,
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -27,15 +28,25 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/cron"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/print"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	"github.com/cortexlabs/cortex/pkg/lib/slices"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/lib/zip"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/userconfig"
 )
 
-func CacheModels(apiSpec *spec.API, awsClient *aws.Client) ([]*spec.LocalModelCache, error) {
+// CachedModel describes a model in the local model cache
+type CachedModel struct {
+	ID       string
+	Size     int64 // bytes
+	LastUsed time.Time
+	APINames []string // the deployed apis which use the model
+}
+
+func CacheModels(apiSpec *spec.API, awsClient *aws.Client, modelCacheMaxSize *k8s.Quantity) ([]*spec.LocalModelCache, error) {
 	modelPaths := make([]string, len(apiSpec.Predictor.Models))
 	for i, modelResource := range apiSpec.Predictor.Models {
 		modelPaths[i] = modelResource.ModelPath
@@ -58,6 +69,16 @@ func CacheModels(apiSpec *spec.API, awsClient *aws.Client) ([]*spec.LocalModelCa
 		fmt.Println("") // Newline to group all of the model information
 	}
 
+	if modelCacheMaxSize != nil {
+		modelIDsToKeep := strset.New()
+		for _, localModelCache := range localModelCaches {
+			modelIDsToKeep.Add(localModelCache.ID)
+		}
+		if err := evictCachedModels(modelCacheMaxSize.Value(), modelIDsToKeep); err != nil {
+			return nil, err
+		}
+	}
+
 	return localModelCaches, nil
 }
 
@@ -91,6 +112,11 @@ func CacheModel(modelPath string, awsClient *aws.Client) (*spec.LocalModelCache,
 	modelDir := filepath.Join(_modelCacheDir, localModelCache.ID)
 
 	if files.IsFile(filepath.Join(modelDir, "_SUCCESS")) {
+		// the modification time of the _SUCCESS file is when the model was last used
+		now := time.Now()
+		if err := os.Chtimes(filepath.Join(modelDir, "_SUCCESS"), now, now); err != nil {
+			return nil, errors.WithStack(err)
+		}
 		localModelCache.HostPath = modelDir
 		return &localModelCache, nil
 	}
@@ -175,6 +201,154 @@ func DeleteCachedModelsByID(modelIDs []string) error {
 	return errors.FirstError(errList...)
 }
 
+// ListCachedModels lists the models in the local model cache, most recently used first (models which were not fully cached are excluded)
+func ListCachedModels() ([]CachedModel, error) {
+	modelIDToAPINames, err := getModelIDToAPINamesMap()
+	if err != nil {
+		return nil, err
+	}
+
+	modelDirs, err := files.ListDir(_modelCacheDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedModels := []CachedModel{}
+	for _, modelDir := range modelDirs {
+		fileInfo, err := os.Stat(filepath.Join(modelDir, "_SUCCESS"))
+		if err != nil {
+			continue
+		}
+
+		size, err := dirSize(modelDir)
+		if err != nil {
+			return nil, err
+		}
+
+		modelID := filepath.Base(modelDir)
+		cachedModels = append(cachedModels, CachedModel{
+			ID:       modelID,
+			Size:     size,
+			LastUsed: fileInfo.ModTime(),
+			APINames: modelIDToAPINames[modelID],
+		})
+	}
+
+	sort.Slice(cachedModels, func(i, j int) bool {
+		return cachedModels[i].LastUsed.After(cachedModels[j].LastUsed)
+	})
+
+	return cachedModels, nil
+}
+
+// PruneCachedModels deletes the cached models which aren't used by any deployed api (including models which were not fully cached), and returns their IDs
+func PruneCachedModels() ([]string, error) {
+	modelIDToAPINames, err := getModelIDToAPINamesMap()
+	if err != nil {
+		return nil, err
+	}
+
+	modelDirs, err := files.ListDir(_modelCacheDir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	prunedModelIDs := []string{}
+	for _, modelDir := range modelDirs {
+		modelID := filepath.Base(modelDir)
+		if _, ok := modelIDToAPINames[modelID]; ok {
+			continue
+		}
+		if err := files.DeleteDir(modelDir); err != nil {
+			return prunedModelIDs, err
+		}
+		prunedModelIDs = append(prunedModelIDs, modelID)
+	}
+
+	return prunedModelIDs, nil
+}
+
+// ClearModelCache deletes everything in the local model cache; the apis which use cached models should be deleted first
+func ClearModelCache() error {
+	modelDirs, err := files.ListDir(_modelCacheDir, false)
+	if err != nil {
+		return err
+	}
+
+	for _, modelDir := range modelDirs {
+		if err := files.DeleteDir(modelDir); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// evictCachedModels deletes the least recently used models which aren't used by any deployed api until the cache fits within maxSize bytes
+func evictCachedModels(maxSize int64, modelIDsToKeep strset.Set) error {
+	cachedModels, err := ListCachedModels()
+	if err != nil {
+		return err
+	}
+
+	var totalSize int64
+	for _, cachedModel := range cachedModels {
+		totalSize += cachedModel.Size
+	}
+
+	// cachedModels is sorted from most to least recently used
+	for i := len(cachedModels) - 1; i >= 0 && totalSize > maxSize; i-- {
+		cachedModel := cachedModels[i]
+		if len(cachedModel.APINames) > 0 || modelIDsToKeep.Has(cachedModel.ID) {
+			continue
+		}
+
+		if err := DeleteCachedModelsByID([]string{cachedModel.ID}); err != nil {
+			return err
+		}
+		totalSize -= cachedModel.Size
+		fmt.Printf("￮ evicted cached model %s (%s) from the model cache\n", cachedModel.ID, s.Int64ToBase2Byte(cachedModel.Size))
+	}
+
+	return nil
+}
+
+func getModelIDToAPINamesMap() (map[string][]string, error) {
+	apiSpecList, err := ListAPISpecs()
+	if err != nil {
+		return nil, err
+	}
+
+	modelIDToAPINames := map[string][]string{}
+	for _, apiSpec := range apiSpecList {
+		for _, modelCache := range apiSpec.LocalModelCaches {
+			if !slices.HasString(modelIDToAPINames[modelCache.ID], apiSpec.Name) {
+				modelIDToAPINames[modelCache.ID] = append(modelIDToAPINames[modelCache.ID], apiSpec.Name)
+			}
+		}
+	}
+
+	return modelIDToAPINames, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.Walk(dir, func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fileInfo.IsDir() {
+			size += fileInfo.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return size, nil
+}
+
 func downloadModel(modelPath string, modelDir string, awsClientForBucket *aws.Client) error {
 	fmt.Printf("￮ downloading model %s ", modelPath)
 	defer fmt.Print(" ✓\n")
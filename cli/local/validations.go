@@ -121,6 +121,16 @@ func ValidateLocalAPIs(apis []userconfig.API, projectFiles ProjectFiles, awsClie
 	for i := range apis {
 		api := &apis[i]
 
+		if api.Kind == userconfig.APISplitterKind {
+			if err := spec.ValidateAPISplitter(api, types.LocalProviderType, awsClient); err != nil {
+				return errors.Wrap(err, api.Identify())
+			}
+			if err := checkIfAPISplitterAPIsExist(api.APIs, apis); err != nil {
+				return errors.Wrap(err, api.Identify())
+			}
+			continue
+		}
+
 		if err := spec.ValidateAPI(api, projectFiles, types.LocalProviderType, awsClient); err != nil {
 			return errors.Wrap(err, api.Identify())
 		}
@@ -144,6 +154,9 @@ func ValidateLocalAPIs(apis []userconfig.API, projectFiles ProjectFiles, awsClie
 
 			for i := range apis {
 				api := &apis[i]
+				if api.Kind == userconfig.APISplitterKind {
+					continue
+				}
 				if apisRequiringGPU.Has(api.Name) {
 					api.Compute.GPU = 0
 				}
@@ -168,6 +181,9 @@ func ValidateLocalAPIs(apis []userconfig.API, projectFiles ProjectFiles, awsClie
 
 	imageSet := strset.New()
 	for _, api := range apis {
+		if api.Kind == userconfig.APISplitterKind {
+			continue
+		}
 		imageSet.Add(api.Predictor.Image)
 		if api.Predictor.Type == userconfig.TensorFlowPredictorType {
 			imageSet.Add(api.Predictor.TensorFlowServingImage)
@@ -334,5 +350,45 @@ func getPortToRunningAPIsMap() (map[int]string, error) {
 		}
 	}
 
+	apiSpecs, err := ListAPISpecs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiSpec := range apiSpecs {
+		if isServedByGateway(apiSpec.Kind) && apiSpec.Networking.LocalPort != nil && isGatewayRunning(apiSpec.Name) {
+			portMap[*apiSpec.Networking.LocalPort] = apiSpec.Name
+		}
+	}
+
 	return portMap, nil
 }
+
+// checkIfAPISplitterAPIsExist checks that the apis referenced by an APISplitter are SyncAPIs which are either defined in the same configuration or already deployed
+func checkIfAPISplitterAPIsExist(trafficSplits []*userconfig.TrafficSplit, apis []userconfig.API) error {
+	var missingAPIs []string
+	for _, trafficSplit := range trafficSplits {
+		if !isSyncAPIDefinedOrDeployed(trafficSplit.Name, apis) {
+			missingAPIs = append(missingAPIs, trafficSplit.Name)
+		}
+	}
+
+	if len(missingAPIs) > 0 {
+		return ErrorNotDeployedAPIsAPISplitter(missingAPIs)
+	}
+	return nil
+}
+
+func isSyncAPIDefinedOrDeployed(apiName string, apis []userconfig.API) bool {
+	for _, api := range apis {
+		if api.Name == apiName {
+			return api.Kind == userconfig.SyncAPIKind
+		}
+	}
+
+	apiSpec, err := FindAPISpec(apiName)
+	if err != nil {
+		return false
+	}
+	return apiSpec.Kind == userconfig.SyncAPIKind
+}
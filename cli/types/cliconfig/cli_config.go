@@ -18,6 +18,7 @@ package cliconfig
 
 import (
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
 	"github.com/cortexlabs/cortex/pkg/types"
 )
@@ -25,6 +26,7 @@ import (
 type CLIConfig struct {
 	Telemetry          *bool          `json:"telemetry,omitempty" yaml:"telemetry,omitempty"`
 	DefaultEnvironment string         `json:"default_environment" yaml:"default_environment"`
+	ModelCacheMaxSize  *k8s.Quantity  `json:"model_cache_max_size,omitempty" yaml:"model_cache_max_size,omitempty"` // least recently used models which aren't used by local apis are evicted when the model cache exceeds this size
 	Environments       []*Environment `json:"environments" yaml:"environments"`
 }
 
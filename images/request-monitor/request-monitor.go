@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"sync"
 	"time"
@@ -31,12 +32,14 @@ import (
 const _tickOffset = 1 * time.Second
 const _tickInterval = 10 * time.Second
 const _requestSampleInterval = 1 * time.Second
+const _metricsPort = "15000"
 
 var (
 	client      *cloudwatch.CloudWatch
 	apiName     string
 	region      string
 	clusterName string
+	inFlight    = Gauge{}
 )
 
 type Counter struct {
@@ -59,6 +62,23 @@ func (c *Counter) GetAllAndDelete() []int {
 	return output
 }
 
+type Gauge struct {
+	sync.Mutex
+	val float64
+}
+
+func (g *Gauge) Set(val float64) {
+	g.Lock()
+	defer g.Unlock()
+	g.val = val
+}
+
+func (g *Gauge) Get() float64 {
+	g.Lock()
+	defer g.Unlock()
+	return g.val
+}
+
 // ./request-monitor api_name cluster_name
 func main() {
 	apiName = os.Args[1]
@@ -76,6 +96,8 @@ func main() {
 	client = cloudwatch.New(sess)
 	requestCounter := Counter{}
 
+	go serveMetrics()
+
 	os.OpenFile("/request_monitor_ready.txt", os.O_RDONLY|os.O_CREATE, 0666)
 
 	for {
@@ -138,6 +160,7 @@ func publishStats(apiName string, counter *Counter, client *cloudwatch.CloudWatc
 		total /= float64(len(requestCounts))
 	}
 	log.Printf("recorded %.2f in-flight requests on replica", total)
+	inFlight.Set(total)
 	curTime := time.Now()
 	metricData := cloudwatch.PutMetricDataInput{
 		Namespace: aws.String(clusterName),
@@ -181,3 +204,15 @@ func updateOpenConnections(requestCounter *Counter, timer *time.Timer) {
 	requestCounter.Append(count)
 	timer.Reset(_requestSampleInterval)
 }
+
+// serves the most recently recorded in-flight value in the prometheus text format
+func serveMetrics() {
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprintln(w, "# TYPE cortex_in_flight_requests gauge")
+		fmt.Fprintf(w, "cortex_in_flight_requests{api_name=%q} %f\n", apiName, inFlight.Get())
+	})
+	if err := http.ListenAndServe(":"+_metricsPort, nil); err != nil {
+		log.Printf("error: serving metrics: %s", err.Error())
+	}
+}
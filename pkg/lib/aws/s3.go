@@ -396,6 +396,27 @@ func (c *Client) ReadBytesFromS3(bucket string, key string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Reads length bytes starting at offset
+func (c *Client) ReadBytesFromS3Range(bucket string, key string, offset int64, length int64) ([]byte, error) {
+	byteRange := fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	obj, err := c.S3().GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(byteRange),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, S3Path(bucket, key), "range "+byteRange)
+	}
+	defer obj.Body.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(obj.Body); err != nil {
+		return nil, errors.Wrap(err, S3Path(bucket, key), "range "+byteRange)
+	}
+
+	return buf.Bytes(), nil
+}
+
 func (c *Client) ReadStringFromS3(bucket string, key string) (string, error) {
 	buf, err := c.ReadBufferFromS3(bucket, key)
 	if err != nil {
@@ -713,18 +734,51 @@ func (c *Client) S3Iterator(bucket string, prefix string, includeDirObjects bool
 	return nil
 }
 
+// Same as S3Iterator, but only objects whose keys are after startAfter (in UTF-8 binary order) are listed
+func (c *Client) S3IteratorStartAfter(bucket string, prefix string, startAfter string, includeDirObjects bool, maxResults *int64, fn func(*s3.Object) (bool, error)) error {
+	var startAfterPtr *string
+	if startAfter != "" {
+		startAfterPtr = aws.String(startAfter)
+	}
+
+	err := c.s3BatchIterator(bucket, prefix, startAfterPtr, includeDirObjects, maxResults, func(objects []*s3.Object) (bool, error) {
+		var subErr error
+		for _, object := range objects {
+			shouldContinue, newSubErr := fn(object)
+			if newSubErr != nil {
+				subErr = newSubErr
+			}
+			if !shouldContinue {
+				return false, subErr
+			}
+		}
+		return true, subErr
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // The return value of fn([]*s3.Object) (bool, error) should be whether to continue iterating, and an error (if any occurred)
 // Directory objects are empty objects ending in "/". They are not guaranteed to exists, and there may or may not be files "in" the directory
 func (c *Client) S3BatchIterator(bucket string, prefix string, includeDirObjects bool, maxResults *int64, fn func([]*s3.Object) (bool, error)) error {
+	return c.s3BatchIterator(bucket, prefix, nil, includeDirObjects, maxResults, fn)
+}
+
+func (c *Client) s3BatchIterator(bucket string, prefix string, startAfter *string, includeDirObjects bool, maxResults *int64, fn func([]*s3.Object) (bool, error)) error {
 	var maxResultsRemaining *int64
 	if maxResults != nil {
 		maxResultsRemaining = pointer.Int64(*maxResults)
 	}
 
 	listObjectsInput := &s3.ListObjectsV2Input{
-		Bucket:  aws.String(bucket),
-		Prefix:  aws.String(prefix),
-		MaxKeys: maxResultsRemaining,
+		Bucket:     aws.String(bucket),
+		Prefix:     aws.String(prefix),
+		MaxKeys:    maxResultsRemaining,
+		StartAfter: startAfter,
 	}
 
 	var numSeen int64
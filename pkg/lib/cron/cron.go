@@ -61,8 +61,12 @@ func Run(f func() error, errHandler func(error), delay time.Duration) Cron {
 	}
 }
 
+// RunNow triggers a run without waiting for the delay; it does not block, and has no effect if a run has already been triggered
 func (c *Cron) RunNow() {
-	c.cronRun <- struct{}{}
+	select {
+	case c.cronRun <- struct{}{}:
+	default:
+	}
 }
 
 func (c *Cron) Cancel() {
@@ -0,0 +1,197 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a standard 5 field cron schedule (minute, hour, day of month, month, day of week), evaluated in UTC
+type Schedule struct {
+	expression  string
+	minutes     []bool
+	hours       []bool
+	daysOfMonth []bool
+	months      []bool
+	daysOfWeek  []bool
+
+	// per the cron convention, if both day fields are restricted, a time matches if either of them matches
+	restrictedDayOfMonth bool
+	restrictedDayOfWeek  bool
+}
+
+type scheduleField struct {
+	name string
+	min  int
+	max  int
+}
+
+var (
+	_minuteField     = scheduleField{name: "minute", min: 0, max: 59}
+	_hourField       = scheduleField{name: "hour", min: 0, max: 23}
+	_dayOfMonthField = scheduleField{name: "day of month", min: 1, max: 31}
+	_monthField      = scheduleField{name: "month", min: 1, max: 12}
+	_dayOfWeekField  = scheduleField{name: "day of week", min: 0, max: 6}
+)
+
+func ParseSchedule(expression string) (*Schedule, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, ErrorInvalidSchedule(expression, fmt.Sprintf("expected 5 fields (minute, hour, day of month, month, day of week), but got %d", len(fields)))
+	}
+
+	schedule := Schedule{
+		expression:           expression,
+		restrictedDayOfMonth: fields[2] != "*",
+		restrictedDayOfWeek:  fields[4] != "*",
+	}
+
+	// 7 is an alias for sunday in the day of week field
+	dayOfWeekField := _dayOfWeekField
+	dayOfWeekField.max = 7
+
+	var err error
+	for i, parsed := range []struct {
+		field scheduleField
+		dest  *[]bool
+	}{
+		{_minuteField, &schedule.minutes},
+		{_hourField, &schedule.hours},
+		{_dayOfMonthField, &schedule.daysOfMonth},
+		{_monthField, &schedule.months},
+		{dayOfWeekField, &schedule.daysOfWeek},
+	} {
+		*parsed.dest, err = parseScheduleField(fields[i], parsed.field)
+		if err != nil {
+			return nil, ErrorInvalidSchedule(expression, err.Error())
+		}
+	}
+
+	if schedule.daysOfWeek[7] {
+		schedule.daysOfWeek[0] = true
+	}
+
+	return &schedule, nil
+}
+
+// parses a comma separated list of values, ranges (e.g. 1-5) and steps (e.g. */15 or 0-30/10)
+func parseScheduleField(str string, field scheduleField) ([]bool, error) {
+	values := make([]bool, field.max+1)
+
+	for _, item := range strings.Split(str, ",") {
+		rangeStr := item
+		step := 1
+
+		if slashIndex := strings.Index(item, "/"); slashIndex != -1 {
+			rangeStr = item[:slashIndex]
+			var err error
+			step, err = strconv.Atoi(item[slashIndex+1:])
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in %s field: %s", field.name, item)
+			}
+		}
+
+		start, end := field.min, field.max
+		if rangeStr != "*" {
+			var err error
+			if dashIndex := strings.Index(rangeStr, "-"); dashIndex != -1 {
+				start, err = parseScheduleValue(rangeStr[:dashIndex], field)
+				if err != nil {
+					return nil, err
+				}
+				end, err = parseScheduleValue(rangeStr[dashIndex+1:], field)
+				if err != nil {
+					return nil, err
+				}
+				if start > end {
+					return nil, fmt.Errorf("invalid range in %s field: %s", field.name, rangeStr)
+				}
+			} else {
+				start, err = parseScheduleValue(rangeStr, field)
+				if err != nil {
+					return nil, err
+				}
+				end = start
+				if step != 1 {
+					end = field.max
+				}
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			values[i] = true
+		}
+	}
+
+	return values, nil
+}
+
+func parseScheduleValue(str string, field scheduleField) (int, error) {
+	value, err := strconv.Atoi(str)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s field: %s", field.name, str)
+	}
+	if value < field.min || value > field.max {
+		return 0, fmt.Errorf("%s field must be between %d and %d (got %d)", field.name, field.min, field.max, value)
+	}
+	return value, nil
+}
+
+func (schedule *Schedule) String() string {
+	return schedule.expression
+}
+
+// Matches returns whether the schedule fires at the minute containing t
+func (schedule *Schedule) Matches(t time.Time) bool {
+	t = t.UTC()
+
+	if !schedule.minutes[t.Minute()] || !schedule.hours[t.Hour()] || !schedule.months[int(t.Month())] {
+		return false
+	}
+
+	dayOfMonthMatches := schedule.daysOfMonth[t.Day()]
+	dayOfWeekMatches := schedule.daysOfWeek[int(t.Weekday())]
+	if schedule.restrictedDayOfMonth && schedule.restrictedDayOfWeek {
+		return dayOfMonthMatches || dayOfWeekMatches
+	}
+	return dayOfMonthMatches && dayOfWeekMatches
+}
+
+// LastActivation returns the most recent time at or before t when the schedule fired, or nil if it hasn't fired within the lookback period
+func (schedule *Schedule) LastActivation(t time.Time, lookback time.Duration) *time.Time {
+	earliest := t.Add(-lookback)
+	for minute := t.UTC().Truncate(time.Minute); !minute.Before(earliest); minute = minute.Add(-time.Minute) {
+		if schedule.Matches(minute) {
+			return &minute
+		}
+	}
+	return nil
+}
+
+// NextActivation returns the earliest time after t when the schedule fires, or nil if it doesn't fire within the lookahead period
+func (schedule *Schedule) NextActivation(t time.Time, lookahead time.Duration) *time.Time {
+	latest := t.Add(lookahead)
+	for minute := t.UTC().Truncate(time.Minute).Add(time.Minute); !minute.After(latest); minute = minute.Add(time.Minute) {
+		if schedule.Matches(minute) {
+			return &minute
+		}
+	}
+	return nil
+}
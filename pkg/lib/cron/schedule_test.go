@@ -0,0 +1,95 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, expression := range []string{
+		"* * * * *",
+		"0 9 * * 1-5",
+		"*/15 0-6,18-23 1 1,6 *",
+		"30 8 * * 7",
+	} {
+		_, err := ParseSchedule(expression)
+		require.NoError(t, err, expression)
+	}
+
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseSchedule(expression)
+		require.Error(t, err, expression)
+	}
+}
+
+func TestScheduleMatches(t *testing.T) {
+	schedule, err := ParseSchedule("0 9 * * 1-5")
+	require.NoError(t, err)
+
+	require.True(t, schedule.Matches(time.Date(2020, 10, 5, 9, 0, 30, 0, time.UTC)))  // monday
+	require.False(t, schedule.Matches(time.Date(2020, 10, 5, 9, 1, 0, 0, time.UTC)))  // monday
+	require.False(t, schedule.Matches(time.Date(2020, 10, 4, 9, 0, 0, 0, time.UTC)))  // sunday
+	require.False(t, schedule.Matches(time.Date(2020, 10, 5, 10, 0, 0, 0, time.UTC))) // monday
+
+	// sunday can be specified as 0 or 7
+	schedule, err = ParseSchedule("0 0 * * 7")
+	require.NoError(t, err)
+	require.True(t, schedule.Matches(time.Date(2020, 10, 4, 0, 0, 0, 0, time.UTC)))
+
+	// if both day fields are restricted, either can match
+	schedule, err = ParseSchedule("0 0 1 * 1")
+	require.NoError(t, err)
+	require.True(t, schedule.Matches(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)))  // thursday
+	require.True(t, schedule.Matches(time.Date(2020, 10, 5, 0, 0, 0, 0, time.UTC)))  // monday
+	require.False(t, schedule.Matches(time.Date(2020, 10, 6, 0, 0, 0, 0, time.UTC))) // tuesday
+}
+
+func TestScheduleLastActivation(t *testing.T) {
+	schedule, err := ParseSchedule("0 9 * * *")
+	require.NoError(t, err)
+
+	now := time.Date(2020, 10, 5, 11, 30, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2020, 10, 5, 9, 0, 0, 0, time.UTC), *schedule.LastActivation(now, 3*time.Hour))
+	require.Nil(t, schedule.LastActivation(now, 2*time.Hour))
+}
+
+func TestScheduleNextActivation(t *testing.T) {
+	schedule, err := ParseSchedule("0 9 * * *")
+	require.NoError(t, err)
+
+	now := time.Date(2020, 10, 5, 11, 30, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2020, 10, 6, 9, 0, 0, 0, time.UTC), *schedule.NextActivation(now, 24*time.Hour))
+	require.Nil(t, schedule.NextActivation(now, 21*time.Hour))
+
+	// the current minute is excluded
+	now = time.Date(2020, 10, 5, 9, 0, 30, 0, time.UTC)
+	require.Equal(t, time.Date(2020, 10, 6, 9, 0, 0, 0, time.UTC), *schedule.NextActivation(now, 24*time.Hour))
+}
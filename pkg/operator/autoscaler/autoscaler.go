@@ -0,0 +1,351 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	libtime "github.com/cortexlabs/cortex/pkg/lib/time"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+type recommendations map[time.Time]int32
+
+func (recs recommendations) add(now time.Time, rec int32) {
+	recs[now] = rec
+}
+
+func (recs recommendations) deleteOlderThan(now time.Time, period time.Duration) {
+	for t := range recs {
+		if now.Sub(t) > period {
+			delete(recs, t)
+		}
+	}
+}
+
+// Returns nil if no recommendations in the period
+func (recs recommendations) maxSince(now time.Time, period time.Duration) *int32 {
+	max := int32(math.MinInt32)
+	foundRecommendation := false
+
+	for t, rec := range recs {
+		if now.Sub(t) < period && rec > max {
+			max = rec
+			foundRecommendation = true
+		}
+	}
+
+	if !foundRecommendation {
+		return nil
+	}
+
+	return &max
+}
+
+// Returns nil if no recommendations in the period
+func (recs recommendations) minSince(now time.Time, period time.Duration) *int32 {
+	min := int32(math.MaxInt32)
+	foundRecommendation := false
+
+	for t, rec := range recs {
+		if now.Sub(t) < period && rec < min {
+			min = rec
+			foundRecommendation = true
+		}
+	}
+
+	if !foundRecommendation {
+		return nil
+	}
+
+	return &min
+}
+
+// Autoscaler makes scaling decisions for a SyncAPI; it is not safe for concurrent use
+type Autoscaler struct {
+	APIName         string
+	APIID           string
+	Spec            *userconfig.Autoscaling
+	MetricsSource   MetricsSource
+	Clock           Clock
+	CurrentReplicas int32
+
+	// Returns the number of requests being held by the activator (optional)
+	ActivatorRequests func(apiName string) int
+	// Called with the inputs and outputs of each decision (optional)
+	OnTick func(tick schema.AutoscalerTick)
+
+	startTime      time.Time
+	lastActiveTime time.Time
+	recs           recommendations
+}
+
+func New(apiName string, apiID string, autoscalingSpec *userconfig.Autoscaling, currentReplicas int32, metricsSource MetricsSource, clock Clock) *Autoscaler {
+	return &Autoscaler{
+		APIName:         apiName,
+		APIID:           apiID,
+		Spec:            autoscalingSpec,
+		MetricsSource:   metricsSource,
+		Clock:           clock,
+		CurrentReplicas: currentReplicas,
+		recs:            make(recommendations),
+	}
+}
+
+// Returns the number of replicas to request, or nil if metrics are not available yet
+func (a *Autoscaler) NextRequest() (*int32, error) {
+	apiName := a.APIName
+	autoscalingSpec := a.Spec
+	currentReplicas := a.CurrentReplicas
+	now := a.Clock.Now()
+
+	if a.startTime.IsZero() {
+		a.startTime = now
+		a.lastActiveTime = now
+	}
+
+	tick := schema.AutoscalerTick{
+		Timestamp:       now,
+		CurrentReplicas: currentReplicas,
+	}
+	defer func() {
+		if a.OnTick != nil {
+			a.OnTick(tick)
+		}
+	}()
+
+	scheduleFloor, scheduleCeil, err := a.scheduleOverrides(now)
+	if err != nil {
+		tick.Message = errors.Message(err)
+		return nil, err
+	}
+	tick.ScheduleMinReplicas = scheduleFloor
+	tick.ScheduleMaxReplicas = scheduleCeil
+
+	// while scaled to zero, the only signals are requests being held by the activator and scheduled overrides
+	if currentReplicas == 0 {
+		request := int32(0)
+		tick.Message = "scaled to zero"
+		if activatorRequests := a.activatorRequests(); activatorRequests > 0 {
+			request = 1
+			a.lastActiveTime = now
+			tick.Message = fmt.Sprintf("scaling up from zero (%d requests waiting)", activatorRequests)
+			log.Printf("%s autoscaler tick: %s", apiName, tick.Message)
+		}
+		if scheduleFloor != nil && request < *scheduleFloor {
+			request = *scheduleFloor
+			tick.Message = fmt.Sprintf("scaling up from zero (scheduled min_replicas=%d)", *scheduleFloor)
+			log.Printf("%s autoscaler tick: %s", apiName, tick.Message)
+		}
+		tick.Request = &request
+		return &request, nil
+	}
+
+	avgInFlight, err := a.MetricsSource.AvgInFlight(apiName, autoscalingSpec.Window)
+	if err != nil {
+		tick.Message = errors.Message(err)
+		return nil, err
+	}
+	if avgInFlight == nil {
+		tick.Message = "metrics not available yet"
+		log.Printf("%s autoscaler tick: %s", apiName, tick.Message)
+		return nil, nil
+	}
+	tick.AvgInFlight = avgInFlight
+
+	if *avgInFlight > 0 || a.activatorRequests() > 0 {
+		a.lastActiveTime = now
+	}
+
+	rawRecommendation, policyStats, err := a.rawRecommendation(*avgInFlight, &tick)
+	if err != nil {
+		tick.Message = errors.Message(err)
+		return nil, err
+	}
+	tick.RawRecommendation = &rawRecommendation
+	recommendation := int32(math.Ceil(rawRecommendation))
+
+	if rawRecommendation < float64(currentReplicas) && rawRecommendation > float64(currentReplicas)*(1-autoscalingSpec.DownscaleTolerance) {
+		recommendation = currentReplicas
+		tick.WithinTolerance = true
+	}
+
+	if rawRecommendation > float64(currentReplicas) && rawRecommendation < float64(currentReplicas)*(1+autoscalingSpec.UpscaleTolerance) {
+		recommendation = currentReplicas
+		tick.WithinTolerance = true
+	}
+
+	// always allow subtraction of 1
+	downscaleFactorFloor := libmath.MinInt32(currentReplicas-1, int32(math.Ceil(float64(currentReplicas)*autoscalingSpec.MaxDownscaleFactor)))
+	if recommendation < downscaleFactorFloor {
+		recommendation = downscaleFactorFloor
+	}
+
+	// always allow addition of 1
+	upscaleFactorCeil := libmath.MaxInt32(currentReplicas+1, int32(math.Ceil(float64(currentReplicas)*autoscalingSpec.MaxUpscaleFactor)))
+	if recommendation > upscaleFactorCeil {
+		recommendation = upscaleFactorCeil
+	}
+	tick.DownscaleFactorFloor = &downscaleFactorFloor
+	tick.UpscaleFactorCeil = &upscaleFactorCeil
+
+	if recommendation < 1 {
+		recommendation = 1
+	}
+
+	if recommendation < autoscalingSpec.MinReplicas {
+		recommendation = autoscalingSpec.MinReplicas
+	}
+
+	if recommendation > autoscalingSpec.MaxReplicas {
+		recommendation = autoscalingSpec.MaxReplicas
+	}
+
+	// Rule of thumb: any modifications that don't consider historical recommendations should be performed before
+	// recording the recommendation, any modifications that use historical recommendations should be performed after
+	a.recs.add(now, recommendation)
+	tick.Recommendation = &recommendation
+
+	// This is just for garbage collection
+	a.recs.deleteOlderThan(now, libtime.MaxDuration(autoscalingSpec.DownscaleStabilizationPeriod, autoscalingSpec.UpscaleStabilizationPeriod))
+
+	request := recommendation
+
+	downscaleStabilizationFloor := a.recs.maxSince(now, autoscalingSpec.DownscaleStabilizationPeriod)
+	if now.Sub(a.startTime) < autoscalingSpec.DownscaleStabilizationPeriod {
+		if request < currentReplicas {
+			request = currentReplicas
+		}
+	} else if downscaleStabilizationFloor != nil && request < *downscaleStabilizationFloor {
+		request = *downscaleStabilizationFloor
+	}
+
+	upscaleStabilizationCeil := a.recs.minSince(now, autoscalingSpec.UpscaleStabilizationPeriod)
+	if now.Sub(a.startTime) < autoscalingSpec.UpscaleStabilizationPeriod {
+		if request > currentReplicas {
+			request = currentReplicas
+		}
+	} else if upscaleStabilizationCeil != nil && request > *upscaleStabilizationCeil {
+		request = *upscaleStabilizationCeil
+	}
+
+	tick.DownscaleStabilizationFloor = downscaleStabilizationFloor
+	tick.UpscaleStabilizationCeil = upscaleStabilizationCeil
+
+	if autoscalingSpec.MinReplicas == 0 && now.Sub(a.lastActiveTime) >= autoscalingSpec.ScaleToZeroPeriod {
+		tick.Message = fmt.Sprintf("no requests received in the last %s, scaling to zero", autoscalingSpec.ScaleToZeroPeriod)
+		log.Printf("%s autoscaler tick: %s", apiName, tick.Message)
+		request = 0
+	}
+
+	// scheduled overrides take precedence over the recommendation (and the floor takes precedence over the ceiling)
+	if scheduleCeil != nil && request > *scheduleCeil {
+		request = *scheduleCeil
+	}
+	if scheduleFloor != nil && request < *scheduleFloor {
+		request = *scheduleFloor
+	}
+	tick.Request = &request
+
+	log.Printf("%s autoscaler tick: avg_in_flight=%s, %s, raw_recommendation=%s, current_replicas=%d, downscale_tolerance=%s, upscale_tolerance=%s, max_downscale_factor=%s, downscale_factor_floor=%d, max_upscale_factor=%s, upscale_factor_ceil=%d, min_replicas=%d, max_replicas=%d, schedule_min_replicas=%s, schedule_max_replicas=%s, recommendation=%d, downscale_stabilization_period=%s, downscale_stabilization_floor=%s, upscale_stabilization_period=%s, upscale_stabilization_ceil=%s, request=%d", apiName, s.Round(*avgInFlight, 2, 0), policyStats, s.Round(rawRecommendation, 2, 0), currentReplicas, s.Float64(autoscalingSpec.DownscaleTolerance), s.Float64(autoscalingSpec.UpscaleTolerance), s.Float64(autoscalingSpec.MaxDownscaleFactor), downscaleFactorFloor, s.Float64(autoscalingSpec.MaxUpscaleFactor), upscaleFactorCeil, autoscalingSpec.MinReplicas, autoscalingSpec.MaxReplicas, s.ObjFlatNoQuotes(scheduleFloor), s.ObjFlatNoQuotes(scheduleCeil), recommendation, autoscalingSpec.DownscaleStabilizationPeriod, s.ObjFlatNoQuotes(downscaleStabilizationFloor), autoscalingSpec.UpscaleStabilizationPeriod, s.ObjFlatNoQuotes(upscaleStabilizationCeil), request)
+
+	return &request, nil
+}
+
+// Returns the highest min_replicas and lowest max_replicas of the schedules which are active at time t (nil if there are none)
+func (a *Autoscaler) scheduleOverrides(t time.Time) (*int32, *int32, error) {
+	var floor *int32
+	var ceil *int32
+
+	for _, schedule := range a.Spec.Schedules {
+		activeSince, err := schedule.ActiveSince(t)
+		if err != nil {
+			return nil, nil, err
+		}
+		if activeSince == nil {
+			continue
+		}
+
+		if schedule.MinReplicas != nil && (floor == nil || *schedule.MinReplicas > *floor) {
+			floor = schedule.MinReplicas
+		}
+		if schedule.MaxReplicas != nil && (ceil == nil || *schedule.MaxReplicas < *ceil) {
+			ceil = schedule.MaxReplicas
+		}
+	}
+
+	return floor, ceil, nil
+}
+
+// Returns the unbounded number of replicas recommended by the autoscaling policy, and the values it was based on (for logging)
+func (a *Autoscaler) rawRecommendation(avgInFlight float64, tick *schema.AutoscalerTick) (float64, string, error) {
+	autoscalingSpec := a.Spec
+
+	switch autoscalingSpec.Policy {
+	case userconfig.TargetLatencyP90AutoscalingPolicy:
+		p90Latency, err := a.MetricsSource.P90Latency(a.APIName, a.APIID, autoscalingSpec.Window)
+		if err != nil {
+			return 0, "", err
+		}
+		if p90Latency == nil {
+			return a.rawRecommendationWithoutData(avgInFlight), fmt.Sprintf("p90_latency=unavailable, target_latency_p90=%s", autoscalingSpec.TargetLatencyP90.String()), nil
+		}
+		tick.P90Latency = p90Latency
+		// latency is assumed to be proportional to the load on each replica
+		targetLatency := float64(*autoscalingSpec.TargetLatencyP90) / float64(time.Millisecond)
+		rawRecommendation := float64(a.CurrentReplicas) * *p90Latency / targetLatency
+		return rawRecommendation, fmt.Sprintf("p90_latency=%sms, target_latency_p90=%s", s.Round(*p90Latency, 2, 0), autoscalingSpec.TargetLatencyP90.String()), nil
+
+	case userconfig.TargetRPSPerReplicaAutoscalingPolicy:
+		requestRate, err := a.MetricsSource.RequestRate(a.APIName, a.APIID, autoscalingSpec.Window)
+		if err != nil {
+			return 0, "", err
+		}
+		if requestRate == nil {
+			return a.rawRecommendationWithoutData(avgInFlight), fmt.Sprintf("request_rate=unavailable, target_rps_per_replica=%s", s.Float64(*autoscalingSpec.TargetRPSPerReplica)), nil
+		}
+		tick.RequestRate = requestRate
+		rawRecommendation := *requestRate / *autoscalingSpec.TargetRPSPerReplica
+		return rawRecommendation, fmt.Sprintf("request_rate=%s, target_rps_per_replica=%s", s.Round(*requestRate, 2, 0), s.Float64(*autoscalingSpec.TargetRPSPerReplica)), nil
+	}
+
+	rawRecommendation := avgInFlight / *autoscalingSpec.TargetReplicaConcurrency
+	return rawRecommendation, fmt.Sprintf("target_replica_concurrency=%s", s.Float64(*autoscalingSpec.TargetReplicaConcurrency)), nil
+}
+
+// Used when the policy's metric has no data for the window: the current replica count is kept unless the API is idle,
+// since missing data usually means that the metric hasn't been ingested yet (rather than that the API isn't receiving requests)
+func (a *Autoscaler) rawRecommendationWithoutData(avgInFlight float64) float64 {
+	if avgInFlight == 0 {
+		return 0
+	}
+	return float64(a.CurrentReplicas)
+}
+
+func (a *Autoscaler) activatorRequests() int {
+	if a.ActivatorRequests == nil {
+		return 0
+	}
+	return a.ActivatorRequests(a.APIName)
+}
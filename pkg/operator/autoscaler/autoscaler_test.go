@@ -0,0 +1,271 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func testAutoscalingSpec() *userconfig.Autoscaling {
+	return &userconfig.Autoscaling{
+		MinReplicas:              1,
+		MaxReplicas:              100,
+		Policy:                   userconfig.TargetReplicaConcurrencyAutoscalingPolicy,
+		TargetReplicaConcurrency: pointer.Float64(1),
+		Window:                   10 * time.Second,
+		MaxDownscaleFactor:       0.75,
+		MaxUpscaleFactor:         1.5,
+		DownscaleTolerance:       0.05,
+		UpscaleTolerance:         0.05,
+	}
+}
+
+func TestAutoscalerNextRequest(t *testing.T) {
+	metricsSource := NewInMemoryMetricsSource()
+	clock := NewSimulatedClock(time.Now())
+	a := New("test", "test-id", testAutoscalingSpec(), 4, metricsSource, clock)
+
+	request, err := a.NextRequest()
+	require.NoError(t, err)
+	require.Nil(t, request)
+
+	// within tolerance
+	metricsSource.SetAvgInFlight("test", pointer.Float64(4.1))
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(4), *request)
+
+	// limited by max_upscale_factor
+	metricsSource.SetAvgInFlight("test", pointer.Float64(20))
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(6), *request)
+
+	// limited by max_downscale_factor
+	metricsSource.SetAvgInFlight("test", pointer.Float64(0))
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), *request)
+}
+
+func TestAutoscalerMinMaxReplicas(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.MinReplicas = 2
+	autoscalingSpec.MaxReplicas = 5
+
+	metricsSource := NewInMemoryMetricsSource()
+	clock := NewSimulatedClock(time.Now())
+	a := New("test", "test-id", autoscalingSpec, 4, metricsSource, clock)
+
+	metricsSource.SetAvgInFlight("test", pointer.Float64(100))
+	request, err := a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(5), *request)
+
+	a.CurrentReplicas = 2
+	metricsSource.SetAvgInFlight("test", pointer.Float64(0))
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(2), *request)
+}
+
+func TestAutoscalerScaleToZero(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.MinReplicas = 0
+	autoscalingSpec.ScaleToZeroPeriod = time.Hour
+
+	metricsSource := NewInMemoryMetricsSource()
+	clock := NewSimulatedClock(time.Now())
+	a := New("test", "test-id", autoscalingSpec, 1, metricsSource, clock)
+	activatorRequests := 0
+	a.ActivatorRequests = func(string) int { return activatorRequests }
+
+	// not idle for long enough
+	metricsSource.SetAvgInFlight("test", pointer.Float64(0))
+	request, err := a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), *request)
+
+	clock.Advance(2 * time.Hour)
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(0), *request)
+
+	// stays at zero until a request is held by the activator
+	a.CurrentReplicas = 0
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(0), *request)
+
+	activatorRequests = 3
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(1), *request)
+}
+
+func TestAutoscalerSchedules(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.MinReplicas = 0
+	autoscalingSpec.ScaleToZeroPeriod = time.Hour
+	autoscalingSpec.Schedules = []*userconfig.AutoscalingSchedule{
+		{
+			Cron:        "* * * * *", // always active
+			Duration:    time.Hour,
+			MinReplicas: pointer.Int32(8),
+		},
+		{
+			Cron:        "0 0 1 1 *",
+			Duration:    time.Minute,
+			MaxReplicas: pointer.Int32(2),
+		},
+	}
+
+	metricsSource := NewInMemoryMetricsSource()
+	clock := NewSimulatedClock(time.Now())
+	a := New("test", "test-id", autoscalingSpec, 4, metricsSource, clock)
+
+	// the floor is applied immediately, regardless of max_upscale_factor
+	metricsSource.SetAvgInFlight("test", pointer.Float64(0))
+	request, err := a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(8), *request)
+
+	// the floor also applies while scaled to zero
+	a.CurrentReplicas = 0
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(8), *request)
+
+	autoscalingSpec.Schedules[0].MinReplicas = nil
+	autoscalingSpec.Schedules[0].MaxReplicas = pointer.Int32(5)
+	a.CurrentReplicas = 4
+	metricsSource.SetAvgInFlight("test", pointer.Float64(100))
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(5), *request)
+}
+
+func TestAutoscalerPolicies(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.Policy = userconfig.TargetRPSPerReplicaAutoscalingPolicy
+	autoscalingSpec.TargetReplicaConcurrency = nil
+	autoscalingSpec.TargetRPSPerReplica = pointer.Float64(10)
+
+	metricsSource := NewInMemoryMetricsSource()
+	clock := NewSimulatedClock(time.Now())
+	metricsSource.SetAvgInFlight("test", pointer.Float64(1))
+	a := New("test", "test-id", autoscalingSpec, 4, metricsSource, clock)
+
+	metricsSource.SetRequestRate("test", pointer.Float64(50))
+	request, err := a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(5), *request)
+
+	autoscalingSpec.Policy = userconfig.TargetLatencyP90AutoscalingPolicy
+	autoscalingSpec.TargetRPSPerReplica = nil
+	autoscalingSpec.TargetLatencyP90 = pointer.Duration(100 * time.Millisecond)
+
+	metricsSource.SetP90Latency("test", pointer.Float64(125))
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(5), *request)
+
+	// the latency metric has no data yet, but requests are in flight
+	metricsSource.SetP90Latency("test", nil)
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(4), *request)
+
+	// no requests were received (limited by max_downscale_factor)
+	metricsSource.SetAvgInFlight("test", pointer.Float64(0))
+	request, err = a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), *request)
+}
+
+func TestSimulate(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.DownscaleStabilizationPeriod = time.Minute
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var samples []MetricsSample
+	for i := 0; i < 30; i++ {
+		inFlight := 10.0
+		if i >= 20 {
+			inFlight = 1
+		}
+		samples = append(samples, MetricsSample{Time: start.Add(time.Duration(i) * 10 * time.Second), InFlight: inFlight})
+	}
+
+	ticks, err := Simulate("test", autoscalingSpec, 1, samples, 10*time.Second)
+	require.NoError(t, err)
+	require.Len(t, ticks, 30)
+
+	// limited by max_upscale_factor
+	require.Equal(t, int32(1), ticks[0].CurrentReplicas)
+	require.Equal(t, int32(2), *ticks[0].Request)
+	require.Equal(t, int32(3), *ticks[1].Request)
+	require.Equal(t, int32(10), *ticks[4].Request)
+
+	// the downscale is delayed by downscale_stabilization_period
+	require.Equal(t, int32(8), *ticks[20].Recommendation)
+	require.Equal(t, int32(10), *ticks[24].Request)
+	require.Equal(t, int32(8), *ticks[25].Request)
+}
+
+func TestAutoscalerRestore(t *testing.T) {
+	autoscalingSpec := testAutoscalingSpec()
+	autoscalingSpec.UpscaleStabilizationPeriod = time.Minute
+
+	metricsSource := NewInMemoryMetricsSource()
+	clock := NewSimulatedClock(time.Now())
+	a := New("test", "test-id", autoscalingSpec, 4, metricsSource, clock)
+	require.Nil(t, a.State())
+
+	metricsSource.SetAvgInFlight("test", pointer.Float64(4))
+	request, err := a.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(4), *request)
+
+	state := a.State()
+	require.Len(t, state.Recommendations, 1)
+
+	clock.Advance(2 * time.Minute)
+	metricsSource.SetAvgInFlight("test", pointer.Float64(6))
+
+	// a new autoscaler blocks upscales until upscale_stabilization_period has elapsed
+	fresh := New("test", "test-id", autoscalingSpec, 4, metricsSource, clock)
+	request, err = fresh.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(4), *request)
+
+	restored := New("test", "test-id", autoscalingSpec, 4, metricsSource, clock)
+	require.True(t, restored.Restore(*state))
+	request, err = restored.NextRequest()
+	require.NoError(t, err)
+	require.Equal(t, int32(6), *request)
+
+	// state from a previous version of the API is ignored
+	updated := New("test", "test-id-2", autoscalingSpec, 4, metricsSource, clock)
+	require.False(t, updated.Restore(*state))
+	require.Nil(t, updated.State())
+}
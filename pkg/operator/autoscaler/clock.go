@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the current time, so that the autoscaler can also be run in simulated time
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// RealClock returns the actual current time
+var RealClock Clock = realClock{}
+
+// SimulatedClock returns a time which is only changed explicitly
+type SimulatedClock struct {
+	mux sync.Mutex
+	now time.Time
+}
+
+func NewSimulatedClock(now time.Time) *SimulatedClock {
+	return &SimulatedClock{now: now}
+}
+
+func (clock *SimulatedClock) Now() time.Time {
+	clock.mux.Lock()
+	defer clock.mux.Unlock()
+	return clock.now
+}
+
+func (clock *SimulatedClock) Set(now time.Time) {
+	clock.mux.Lock()
+	defer clock.mux.Unlock()
+	clock.now = now
+}
+
+func (clock *SimulatedClock) Advance(duration time.Duration) {
+	clock.mux.Lock()
+	defer clock.mux.Unlock()
+	clock.now = clock.now.Add(duration)
+}
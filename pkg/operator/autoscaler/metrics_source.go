@@ -0,0 +1,87 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"sync"
+	"time"
+)
+
+// MetricsSource provides the metrics which the autoscaler bases its decisions on
+type MetricsSource interface {
+	// Returns the average number of in-flight requests (summed across all replicas) over the window, or nil if no metrics are available yet
+	AvgInFlight(apiName string, window time.Duration) (*float64, error)
+	// Returns the p90 request latency in milliseconds over the window, or nil if no data is available (e.g. no requests were received, or the metrics have not been ingested yet)
+	P90Latency(apiName string, apiID string, window time.Duration) (*float64, error)
+	// Returns the number of requests per second (summed across all replicas) over the window, or nil if no data is available
+	RequestRate(apiName string, apiID string, window time.Duration) (*float64, error)
+}
+
+// InMemoryMetricsSource returns metrics which have been set explicitly; it is intended for testing and simulation
+type InMemoryMetricsSource struct {
+	mux         sync.Mutex
+	avgInFlight map[string]*float64
+	p90Latency  map[string]*float64
+	requestRate map[string]*float64
+}
+
+func NewInMemoryMetricsSource() *InMemoryMetricsSource {
+	return &InMemoryMetricsSource{
+		avgInFlight: make(map[string]*float64),
+		p90Latency:  make(map[string]*float64),
+		requestRate: make(map[string]*float64),
+	}
+}
+
+// Set the value that will be returned by AvgInFlight for the API (nil indicates that metrics are not available)
+func (source *InMemoryMetricsSource) SetAvgInFlight(apiName string, avgInFlight *float64) {
+	source.mux.Lock()
+	defer source.mux.Unlock()
+	source.avgInFlight[apiName] = avgInFlight
+}
+
+// Set the value that will be returned by P90Latency for the API (nil indicates that no requests were received)
+func (source *InMemoryMetricsSource) SetP90Latency(apiName string, p90Latency *float64) {
+	source.mux.Lock()
+	defer source.mux.Unlock()
+	source.p90Latency[apiName] = p90Latency
+}
+
+// Set the value that will be returned by RequestRate for the API (nil indicates that no data is available)
+func (source *InMemoryMetricsSource) SetRequestRate(apiName string, requestRate *float64) {
+	source.mux.Lock()
+	defer source.mux.Unlock()
+	source.requestRate[apiName] = requestRate
+}
+
+func (source *InMemoryMetricsSource) AvgInFlight(apiName string, window time.Duration) (*float64, error) {
+	source.mux.Lock()
+	defer source.mux.Unlock()
+	return source.avgInFlight[apiName], nil
+}
+
+func (source *InMemoryMetricsSource) P90Latency(apiName string, apiID string, window time.Duration) (*float64, error) {
+	source.mux.Lock()
+	defer source.mux.Unlock()
+	return source.p90Latency[apiName], nil
+}
+
+func (source *InMemoryMetricsSource) RequestRate(apiName string, apiID string, window time.Duration) (*float64, error) {
+	source.mux.Lock()
+	defer source.mux.Unlock()
+	return source.requestRate[apiName], nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"io/ioutil"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// MetricsSample is a point in a recorded (or synthetic) metrics time series
+type MetricsSample struct {
+	Time        time.Time
+	InFlight    float64
+	P90Latency  *float64 // milliseconds
+	RequestRate *float64
+}
+
+// SeriesMetricsSource averages the samples of a time series which fall within the window ending at the clock's current time
+type SeriesMetricsSource struct {
+	Samples []MetricsSample // sorted by time
+	Clock   Clock
+}
+
+func NewSeriesMetricsSource(samples []MetricsSample, clock Clock) *SeriesMetricsSource {
+	sortedSamples := make([]MetricsSample, len(samples))
+	copy(sortedSamples, samples)
+	sort.SliceStable(sortedSamples, func(i, j int) bool {
+		return sortedSamples[i].Time.Before(sortedSamples[j].Time)
+	})
+
+	return &SeriesMetricsSource{
+		Samples: sortedSamples,
+		Clock:   clock,
+	}
+}
+
+func (source *SeriesMetricsSource) AvgInFlight(apiName string, window time.Duration) (*float64, error) {
+	return source.avg(window, func(sample MetricsSample) *float64 {
+		return &sample.InFlight
+	}), nil
+}
+
+func (source *SeriesMetricsSource) P90Latency(apiName string, apiID string, window time.Duration) (*float64, error) {
+	return source.avg(window, func(sample MetricsSample) *float64 {
+		return sample.P90Latency
+	}), nil
+}
+
+func (source *SeriesMetricsSource) RequestRate(apiName string, apiID string, window time.Duration) (*float64, error) {
+	return source.avg(window, func(sample MetricsSample) *float64 {
+		return sample.RequestRate
+	}), nil
+}
+
+// Returns the number of in-flight requests in the most recent sample, which approximates the requests that would be held by the activator
+func (source *SeriesMetricsSource) ActivatorRequests(apiName string) int {
+	now := source.Clock.Now()
+
+	inFlight := 0.0
+	for _, sample := range source.Samples {
+		if sample.Time.After(now) {
+			break
+		}
+		inFlight = sample.InFlight
+	}
+
+	return int(math.Ceil(inFlight))
+}
+
+// Returns the average of the values in (now - window, now], or nil if there are none
+func (source *SeriesMetricsSource) avg(window time.Duration, value func(MetricsSample) *float64) *float64 {
+	now := source.Clock.Now()
+
+	sum := 0.0
+	count := 0
+	for _, sample := range source.Samples {
+		if !sample.Time.After(now.Add(-window)) {
+			continue
+		}
+		if sample.Time.After(now) {
+			break
+		}
+		if val := value(sample); val != nil {
+			sum += *val
+			count++
+		}
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	avg := sum / float64(count)
+	return &avg
+}
+
+// Simulate runs the autoscaler against a metrics time series, starting at the first sample and ticking every tickInterval until the last sample.
+// Requested replicas are assumed to become available immediately, and the series is not affected by the number of replicas.
+func Simulate(apiName string, autoscalingSpec *userconfig.Autoscaling, initialReplicas int32, samples []MetricsSample, tickInterval time.Duration) ([]schema.AutoscalerTick, error) {
+	if len(samples) == 0 {
+		return nil, nil
+	}
+
+	// the autoscaler's logs are not useful in simulations, since the ticks are returned
+	logWriter := log.Writer()
+	log.SetOutput(ioutil.Discard)
+	defer log.SetOutput(logWriter)
+
+	metricsSource := NewSeriesMetricsSource(samples, nil)
+	start := metricsSource.Samples[0].Time
+	end := metricsSource.Samples[len(metricsSource.Samples)-1].Time
+
+	clock := NewSimulatedClock(start)
+	metricsSource.Clock = clock
+
+	var ticks []schema.AutoscalerTick
+
+	a := New(apiName, "", autoscalingSpec, initialReplicas, metricsSource, clock)
+	a.ActivatorRequests = metricsSource.ActivatorRequests
+	a.OnTick = func(tick schema.AutoscalerTick) {
+		ticks = append(ticks, tick)
+	}
+
+	for !clock.Now().After(end) {
+		request, err := a.NextRequest()
+		if err != nil {
+			return nil, err
+		}
+		if request != nil {
+			a.CurrentReplicas = *request
+		}
+		clock.Advance(tickInterval)
+	}
+
+	return ticks, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscaler
+
+import (
+	"sort"
+	"time"
+)
+
+// State is the part of the autoscaler which depends on its previous decisions, so that it can be checkpointed and restored (e.g. across operator restarts)
+type State struct {
+	APIID           string           `json:"api_id"`
+	StartTime       time.Time        `json:"start_time"`
+	LastActiveTime  time.Time        `json:"last_active_time"`
+	Recommendations []Recommendation `json:"recommendations"`
+}
+
+type Recommendation struct {
+	Time     time.Time `json:"time"`
+	Replicas int32     `json:"replicas"`
+}
+
+// Returns nil if the autoscaler hasn't made any decisions yet
+func (a *Autoscaler) State() *State {
+	if a.startTime.IsZero() {
+		return nil
+	}
+
+	state := State{
+		APIID:           a.APIID,
+		StartTime:       a.startTime,
+		LastActiveTime:  a.lastActiveTime,
+		Recommendations: make([]Recommendation, 0, len(a.recs)),
+	}
+
+	for t, rec := range a.recs {
+		state.Recommendations = append(state.Recommendations, Recommendation{Time: t, Replicas: rec})
+	}
+	sort.Slice(state.Recommendations, func(i, j int) bool {
+		return state.Recommendations[i].Time.Before(state.Recommendations[j].Time)
+	})
+
+	return &state
+}
+
+// Restore the state of a previous autoscaler for the same API; returns false (and does nothing) if the state is for a different API ID
+func (a *Autoscaler) Restore(state State) bool {
+	if state.APIID != a.APIID || state.StartTime.IsZero() {
+		return false
+	}
+
+	a.startTime = state.StartTime
+	a.lastActiveTime = state.LastActiveTime
+	a.recs = make(recommendations, len(state.Recommendations))
+	for _, rec := range state.Recommendations {
+		a.recs[rec.Time] = rec.Replicas
+	}
+
+	return true
+}
@@ -26,6 +26,7 @@ import (
 
 func Deploy(w http.ResponseWriter, r *http.Request) {
 	force := getOptionalBoolQParam("force", false, r)
+	dryRun := getOptionalBoolQParam("dryRun", false, r)
 
 	configFileName, err := getRequiredQueryParam("configFileName", r)
 	if err != nil {
@@ -48,7 +49,7 @@ func Deploy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response, err := resources.Deploy(projectBytes, configFileName, configBytes, force)
+	response, err := resources.Deploy(projectBytes, configFileName, configBytes, force, dryRun)
 	if err != nil {
 		respondError(w, r, err)
 		return
@@ -37,6 +37,7 @@ const (
 	ErrAnyQueryParamRequired  = "endpoints.any_query_param_required"
 	ErrAnyPathParamRequired   = "endpoints.any_path_param_required"
 	ErrLogsJobIDRequired      = "endpoints.logs_job_id_required"
+	ErrInvalidQueryParam      = "endpoints.invalid_query_param"
 )
 
 func ErrorAPIVersionMismatch(operatorVersion string, clientVersion string) error {
@@ -123,3 +124,10 @@ func ErrorLogsJobIDRequired(resource operator.DeployedResource) error {
 		Message: fmt.Sprintf("job id is required to stream logs for %s; you can get a list of latest job ids with `cortex get %s` and use `cortex logs %s JOB_ID` to stream logs for a job", resource.UserString(), resource.Name, resource.Name),
 	})
 }
+
+func ErrorInvalidQueryParam(param string, provided string, expected string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidQueryParam,
+		Message: fmt.Sprintf("invalid value for query param %s: %s (expected %s)", param, s.UserStr(provided), expected),
+	})
+}
@@ -64,10 +64,24 @@ func GetJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	resultManifest, err := batchapi.GetResultManifest(jobStatus)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	jobProgress, err := batchapi.GetJobProgress(jobStatus)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
 	response := schema.GetJobResponse{
-		JobStatus: *jobStatus,
-		APISpec:   *spec,
-		Endpoint:  urls.Join(endpoint, jobKey.ID),
+		JobStatus:      *jobStatus,
+		APISpec:        *spec,
+		Endpoint:       urls.Join(endpoint, jobKey.ID),
+		ResultManifest: resultManifest,
+		JobProgress:    jobProgress,
 	}
 
 	respond(w, response)
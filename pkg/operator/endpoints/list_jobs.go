@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/gorilla/mux"
+)
+
+func ListJobs(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := checkIsBatchAPI(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	pageSize := batchapi.DefaultJobsPageSize
+	if pageSizeStr := getOptionalQParam("pageSize", r); pageSizeStr != "" {
+		var ok bool
+		pageSize, ok = s.ParseInt(pageSizeStr)
+		if !ok || pageSize <= 0 || pageSize > batchapi.MaxJobsPageSize {
+			respondError(w, r, ErrorInvalidQueryParam("pageSize", pageSizeStr, fmt.Sprintf("an integer between 1 and %d", batchapi.MaxJobsPageSize)))
+			return
+		}
+	}
+
+	filter := batchapi.JobFilter{}
+
+	if statusFilter := getOptionalQParam("status", r); statusFilter != "" {
+		var err error
+		filter.StatusFilter, err = batchapi.ParseJobStatusFilter(statusFilter)
+		if err != nil {
+			respondError(w, r, err)
+			return
+		}
+	}
+
+	for paramName, timeFilter := range map[string]**time.Time{
+		"submittedAfter":  &filter.SubmittedAfter,
+		"submittedBefore": &filter.SubmittedBefore,
+	} {
+		if timeStr := getOptionalQParam(paramName, r); timeStr != "" {
+			t, err := time.Parse(time.RFC3339, timeStr)
+			if err != nil {
+				respondError(w, r, ErrorInvalidQueryParam(paramName, timeStr, "an RFC 3339 timestamp"))
+				return
+			}
+			*timeFilter = &t
+		}
+	}
+
+	response, err := batchapi.ListJobs(apiName, getOptionalQParam("cursor", r), pageSize, filter)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, response)
+}
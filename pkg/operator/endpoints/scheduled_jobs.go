@@ -0,0 +1,150 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/resources/batchapi"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/gorilla/mux"
+)
+
+func CreateScheduledJob(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := checkIsBatchAPI(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	// max payload size, same as job submissions
+	rw := http.MaxBytesReader(w, r.Body, 10<<20)
+
+	bodyBytes, err := ioutil.ReadAll(rw)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	submission := schema.ScheduledJobSubmission{}
+	err = json.Unmarshal(bodyBytes, &submission)
+	if err != nil {
+		respondError(w, r, errors.Append(err, fmt.Sprintf("\n\nscheduled job schema can be found at https://docs.cortex.dev/v/%s/deployments/batchapi/endpoints", consts.CortexVersionMinor)))
+		return
+	}
+
+	scheduledJob, err := batchapi.CreateScheduledJob(apiName, &submission)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, scheduledJob)
+}
+
+func GetScheduledJobs(w http.ResponseWriter, r *http.Request) {
+	apiName := mux.Vars(r)["apiName"]
+
+	if err := checkIsBatchAPI(apiName); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	scheduledJobs, err := batchapi.GetScheduledJobs(apiName)
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.GetScheduledJobsResponse{ScheduledJobs: scheduledJobs})
+}
+
+func GetScheduledJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := checkIsBatchAPI(vars["apiName"]); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	scheduledJob, err := batchapi.GetScheduledJob(vars["apiName"], vars["scheduleName"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, scheduledJob)
+}
+
+func PauseScheduledJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := checkIsBatchAPI(vars["apiName"]); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	scheduledJob, err := batchapi.PauseScheduledJob(vars["apiName"], vars["scheduleName"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, scheduledJob)
+}
+
+func ResumeScheduledJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := checkIsBatchAPI(vars["apiName"]); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	scheduledJob, err := batchapi.ResumeScheduledJob(vars["apiName"], vars["scheduleName"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, scheduledJob)
+}
+
+func DeleteScheduledJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := checkIsBatchAPI(vars["apiName"]); err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	err := batchapi.DeleteScheduledJob(vars["apiName"], vars["scheduleName"])
+	if err != nil {
+		respondError(w, r, err)
+		return
+	}
+
+	respond(w, schema.DeleteResponse{
+		Message: fmt.Sprintf("deleted scheduled job %s", vars["scheduleName"]),
+	})
+}
@@ -43,6 +43,10 @@ func main() {
 
 	telemetry.Event("operator.init")
 
+	if err := batchapi.InitJobStateStore(config.Cluster.BatchJobStateStore); err != nil {
+		exit.Error(errors.Wrap(err, "init"))
+	}
+
 	_, err := operator.UpdateMemoryCapacityConfigMap()
 	if err != nil {
 		exit.Error(errors.Wrap(err, "init"))
@@ -58,12 +62,16 @@ func main() {
 			if err := syncapi.UpdateAutoscalerCron(&deployment); err != nil {
 				exit.Error(errors.Wrap(err, "init"))
 			}
+			if err := syncapi.ResumeCanaryRollout(deployment.Labels["apiName"]); err != nil {
+				exit.Error(errors.Wrap(err, "init"))
+			}
 		}
 	}
 
 	cron.Run(operator.DeleteEvictedPods, operator.ErrorHandler("delete evicted pods"), 12*time.Hour)
 	cron.Run(operator.InstanceTelemetry, operator.ErrorHandler("instance telemetry"), 1*time.Hour)
 	cron.Run(batchapi.ManageJobResources, operator.ErrorHandler("manage jobs"), batchapi.ManageJobResourcesCronPeriod)
+	cron.Run(batchapi.ManageScheduledJobs, operator.ErrorHandler("manage scheduled jobs"), batchapi.ManageScheduledJobsCronPeriod)
 
 	router := mux.NewRouter()
 
@@ -71,8 +79,11 @@ func main() {
 	routerWithoutAuth.Use(endpoints.PanicMiddleware)
 	routerWithoutAuth.HandleFunc("/verifycortex", endpoints.VerifyCortex).Methods("GET")
 	routerWithoutAuth.HandleFunc("/batch/{apiName}", endpoints.SubmitJob).Methods("POST")
+	routerWithoutAuth.HandleFunc("/batch/{apiName}/jobs", endpoints.ListJobs).Methods("GET")
 	routerWithoutAuth.HandleFunc("/batch/{apiName}/{jobID}", endpoints.GetJob).Methods("GET")
 	routerWithoutAuth.HandleFunc("/batch/{apiName}/{jobID}", endpoints.StopJob).Methods("DELETE")
+	routerWithoutAuth.HandleFunc("/batch/{apiName}/{jobID}/failed_batches", endpoints.GetFailedBatches).Methods("GET")
+	routerWithoutAuth.HandleFunc("/batch/{apiName}/{jobID}/failed_batches", endpoints.ResubmitFailedBatches).Methods("POST")
 	routerWithoutAuth.HandleFunc("/logs/{apiName}/{jobID}", endpoints.ReadJobLogs)
 
 	routerWithAuth := router.NewRoute().Subrouter()
@@ -85,11 +96,30 @@ func main() {
 	routerWithAuth.HandleFunc("/info", endpoints.Info).Methods("GET")
 	routerWithAuth.HandleFunc("/deploy", endpoints.Deploy).Methods("POST")
 	routerWithAuth.HandleFunc("/refresh/{apiName}", endpoints.Refresh).Methods("POST")
+	routerWithAuth.HandleFunc("/rollback/{apiName}", endpoints.Rollback).Methods("POST")
 	routerWithAuth.HandleFunc("/delete/{apiName}", endpoints.Delete).Methods("DELETE")
 	routerWithAuth.HandleFunc("/get", endpoints.GetAPIs).Methods("GET")
 	routerWithAuth.HandleFunc("/get/{apiName}", endpoints.GetAPI).Methods("GET")
+	routerWithAuth.HandleFunc("/autoscaling/{apiName}", endpoints.GetAutoscaling).Methods("GET")
+	routerWithAuth.HandleFunc("/history/{apiName}", endpoints.GetHistory).Methods("GET")
+	routerWithAuth.HandleFunc("/schedules/{apiName}", endpoints.GetScheduledJobs).Methods("GET")
+	routerWithAuth.HandleFunc("/schedules/{apiName}", endpoints.CreateScheduledJob).Methods("POST")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleName}", endpoints.GetScheduledJob).Methods("GET")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleName}", endpoints.DeleteScheduledJob).Methods("DELETE")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleName}/pause", endpoints.PauseScheduledJob).Methods("POST")
+	routerWithAuth.HandleFunc("/schedules/{apiName}/{scheduleName}/resume", endpoints.ResumeScheduledJob).Methods("POST")
 	routerWithAuth.HandleFunc("/logs/{apiName}", endpoints.ReadLogs)
 
+	// the activator is not served by the operator's router, since it must only be reachable from the apis gateway
+	activatorRouter := mux.NewRouter()
+	activatorRouter.Use(endpoints.PanicMiddleware)
+	activatorRouter.HandleFunc("/activator/{apiName}", endpoints.Activate).Methods("GET", "POST")
+
+	go func() {
+		log.Print("Running activator on port " + syncapi.ActivatorPortStr)
+		log.Fatal(http.ListenAndServe(":"+syncapi.ActivatorPortStr, activatorRouter))
+	}()
+
 	log.Print("Running on port " + _operatorPortStr)
 	log.Fatal(http.ListenAndServe(":"+_operatorPortStr, router))
 }
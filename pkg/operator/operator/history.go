@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operator
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+// older versions are dropped from the history (their specs remain in the bucket until the api is deleted)
+const _maxAPIHistoryLength = 50
+
+// Returns the deployed versions of an API, oldest first (empty if the API has never been deployed)
+func GetAPIHistory(apiName string) ([]schema.APIVersion, error) {
+	var history []schema.APIVersion
+	err := config.AWS.ReadJSONFromS3(&history, config.Cluster.Bucket, spec.HistoryKey(apiName))
+	if err != nil {
+		if aws.IsNoSuchKeyErr(err) {
+			return []schema.APIVersion{}, nil
+		}
+		return nil, err
+	}
+	return history, nil
+}
+
+// RecordAPIVersion appends the API to its history, unless it is already the most recently deployed version
+func RecordAPIVersion(api *spec.API) error {
+	history, err := GetAPIHistory(api.Name)
+	if err != nil {
+		return err
+	}
+
+	if len(history) > 0 && history[len(history)-1].APIID == api.ID {
+		return nil
+	}
+
+	history = append(history, schema.APIVersion{
+		APIID:         api.ID,
+		ProjectID:     api.ProjectID,
+		Kind:          api.Kind,
+		CortexVersion: consts.CortexVersion,
+		DeployedAt:    time.Now(),
+	})
+
+	if len(history) > _maxAPIHistoryLength {
+		history = history[len(history)-_maxAPIHistoryLength:]
+	}
+
+	return config.AWS.UploadJSONToS3(history, config.Cluster.Bucket, spec.HistoryKey(api.Name))
+}
@@ -47,6 +47,9 @@ const (
 	DefaultPortInt32 = int32(8888)
 	DefaultPortStr   = "8888"
 	APIContainerName = "api"
+
+	RequestMonitorMetricsPortInt32 = int32(15000)
+	RequestMonitorMetricsPortStr   = "15000"
 )
 
 const (
@@ -730,6 +733,9 @@ func RequestMonitorContainer(api *spec.API) kcore.Container {
 		EnvFrom:         BaseEnvVars,
 		VolumeMounts:    DefaultVolumeMounts,
 		ReadinessProbe:  FileExistsProbe(_requestMonitorReadinessFile),
+		Ports: []kcore.ContainerPort{
+			{Name: "metrics", ContainerPort: RequestMonitorMetricsPortInt32},
+		},
 		Resources: kcore.ResourceRequirements{
 			Requests: kcore.ResourceList{
 				kcore.ResourceCPU:    _requestMonitorCPURequest,
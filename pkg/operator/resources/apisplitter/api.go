@@ -70,6 +70,27 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 	return api, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
 }
 
+// DryRunUpdateAPI returns the spec which UpdateAPI would deploy, the currently deployed spec (nil if the API isn't deployed), and whether the API would be updated
+func DryRunUpdateAPI(apiConfig *userconfig.API, projectID string) (*spec.API, *spec.API, bool, error) {
+	prevVirtualService, err := getK8sResources(apiConfig)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	api := spec.GetAPISpec(apiConfig, projectID, "")
+
+	if prevVirtualService == nil {
+		return api, nil, true, nil
+	}
+
+	prevAPI, err := operator.DownloadAPISpec(apiConfig.Name, prevVirtualService.Labels["apiID"])
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return api, prevAPI, !areVirtualServiceEqual(prevVirtualService, virtualServiceSpec(api)), nil
+}
+
 func DeleteAPI(apiName string, keepCache bool) error {
 	// best effort deletion, so don't handle error yet
 	virtualService, vsErr := config.K8s.GetVirtualService(operator.K8sName(apiName))
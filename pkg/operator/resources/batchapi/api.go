@@ -90,6 +90,27 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string) (*spec.API, string,
 	return api, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
 }
 
+// DryRunUpdateAPI returns the spec which UpdateAPI would deploy, the currently deployed spec (nil if the API isn't deployed), and whether the API would be updated
+func DryRunUpdateAPI(apiConfig *userconfig.API, projectID string) (*spec.API, *spec.API, bool, error) {
+	prevVirtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiConfig.Name))
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	api := spec.GetAPISpec(apiConfig, projectID, "") // Deployment ID not needed for BatchAPI spec
+
+	if prevVirtualService == nil {
+		return api, nil, true, nil
+	}
+
+	prevAPI, err := operator.DownloadAPISpec(apiConfig.Name, prevVirtualService.Labels["apiID"])
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return api, prevAPI, !areAPIsEqual(prevVirtualService, virtualServiceSpec(api)), nil
+}
+
 func areAPIsEqual(v1, v2 *istioclientnetworking.VirtualService) bool {
 	return v1.Labels["apiName"] == v2.Labels["apiName"] &&
 		v1.Labels["apiID"] == v2.Labels["apiID"] &&
@@ -117,6 +138,9 @@ func DeleteAPI(apiName string, keepCache bool) error {
 		func() error {
 			return config.AWS.DeleteQueuesWithPrefix(apiQueueNamePrefix(apiName))
 		},
+		func() error {
+			return deleteAllScheduledJobs(apiName)
+		},
 		func() error {
 			err := operator.RemoveAPIFromAPIGatewayK8s(virtualService, true)
 			if err != nil {
@@ -161,7 +185,7 @@ func deleteS3Resources(apiName string) error {
 			return nil
 		},
 		func() error {
-			deleteAllInProgressFilesByAPI(apiName) // not useful xml error is thrown, swallow the error
+			_jobStateStore.DeleteJobStatesByAPI(apiName) // not useful xml error is thrown, swallow the error
 			return nil
 		},
 	)
@@ -216,7 +240,7 @@ func GetAllAPIs(virtualServices []istioclientnetworking.VirtualService, k8sJobs
 		}
 	}
 
-	inProgressJobKeys, err := listAllInProgressJobKeys()
+	inProgressJobKeys, err := _jobStateStore.ListInProgressJobKeys()
 	if err != nil {
 		return nil, err
 	}
@@ -287,7 +311,7 @@ func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIRe
 		jobIDToPodsMap[pod.Labels["jobID"]] = append(jobIDToPodsMap[pod.Labels["jobID"]], pod)
 	}
 
-	inProgressJobKeys, err := listAllInProgressJobKeysByAPI(deployedResource.Name)
+	inProgressJobKeys, err := _jobStateStore.ListInProgressJobKeysByAPI(deployedResource.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,252 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	kcore "k8s.io/api/core/v1"
+)
+
+const (
+	_jobStateConfigMapStatusKey = "status"
+	// the config maps of older completed jobs are deleted so that the number of config maps (and the size of each list request) is bounded
+	_maxCompletedJobStatesPerAPI = 100
+)
+
+// configMapJobStateStore keeps the state of each job in a config map. The config map is updated using its resource version,
+// so concurrent status updates conflict rather than overwrite each other. Only the states of each API's
+// _maxCompletedJobStatesPerAPI most recent completed jobs are kept (in progress jobs are never deleted).
+type configMapJobStateStore struct{}
+
+func jobStateConfigMapName(jobKey spec.JobKey) string {
+	return "job-state-" + jobKey.K8sName()
+}
+
+func jobStateConfigMapLabels(jobKey spec.JobKey, jobStatus status.JobCode) map[string]string {
+	inProgress := "false"
+	if jobStatus.IsInProgress() {
+		inProgress = "true"
+	}
+
+	return map[string]string{
+		"apiKind":    userconfig.BatchAPIKind.String(),
+		"apiName":    jobKey.APIName,
+		"jobID":      jobKey.ID,
+		"jobState":   "true",
+		"inProgress": inProgress,
+	}
+}
+
+func jobStateFromConfigMap(configMap *kcore.ConfigMap) JobState {
+	jobKey := spec.JobKey{APIName: configMap.Labels["apiName"], ID: configMap.Labels["jobID"]}
+
+	var statusCode status.JobCode
+	statusCode.UnmarshalText([]byte(configMap.Data[_jobStateConfigMapStatusKey]))
+
+	lastUpdatedMap := map[string]time.Time{}
+	for key, value := range configMap.Data {
+		if key == _jobStateConfigMapStatusKey {
+			continue
+		}
+		if lastUpdated, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			lastUpdatedMap[key] = lastUpdated
+		}
+	}
+
+	return newJobState(jobKey, statusCode, lastUpdatedMap)
+}
+
+func jobKeysFromConfigMaps(configMaps []kcore.ConfigMap) []spec.JobKey {
+	jobKeys := make([]spec.JobKey, 0, len(configMaps))
+	for _, configMap := range configMaps {
+		jobKeys = append(jobKeys, spec.JobKey{APIName: configMap.Labels["apiName"], ID: configMap.Labels["jobID"]})
+	}
+	return jobKeys
+}
+
+func (store *configMapJobStateStore) GetJobState(jobKey spec.JobKey) (*JobState, error) {
+	configMap, err := config.K8s.GetConfigMap(jobStateConfigMapName(jobKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get job state", jobKey.UserString())
+	}
+
+	if configMap == nil {
+		return nil, errors.Wrap(ErrorJobNotFound(jobKey), "failed to get job state")
+	}
+
+	jobState := jobStateFromConfigMap(configMap)
+	return &jobState, nil
+}
+
+func (store *configMapJobStateStore) SetStatus(jobKey spec.JobKey, jobStatus status.JobCode) error {
+	configMap, err := config.K8s.GetConfigMap(jobStateConfigMapName(jobKey))
+	if err != nil {
+		return err
+	}
+
+	currentStatus := status.JobUnknown
+	if configMap != nil {
+		currentStatus = jobStateFromConfigMap(configMap).Status
+	}
+
+	if err := validateJobStatusTransition(jobKey, currentStatus, jobStatus); err != nil {
+		return err
+	}
+
+	if configMap == nil {
+		_, err := config.K8s.CreateConfigMap(k8s.ConfigMap(&k8s.ConfigMapSpec{
+			Name: jobStateConfigMapName(jobKey),
+			Data: map[string]string{
+				_jobStateConfigMapStatusKey: jobStatus.String(),
+				jobStatus.String():          time.Now().Format(time.RFC3339Nano),
+			},
+			Labels: jobStateConfigMapLabels(jobKey, jobStatus),
+		}))
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[_jobStateConfigMapStatusKey] = jobStatus.String()
+	configMap.Data[jobStatus.String()] = time.Now().Format(time.RFC3339Nano)
+	configMap.Labels = jobStateConfigMapLabels(jobKey, jobStatus)
+
+	if _, err := config.K8s.UpdateConfigMap(configMap); err != nil {
+		return err
+	}
+
+	if jobStatus.IsCompleted() {
+		return deleteOldCompletedJobStates(jobKey.APIName)
+	}
+	return nil
+}
+
+// keeps the config maps of the _maxCompletedJobStatesPerAPI most recently submitted completed jobs
+func deleteOldCompletedJobStates(apiName string) error {
+	configMaps, err := config.K8s.ListConfigMapsByLabels(map[string]string{"jobState": "true", "inProgress": "false", "apiName": apiName})
+	if err != nil {
+		return err
+	}
+	if len(configMaps) <= _maxCompletedJobStatesPerAPI {
+		return nil
+	}
+
+	// job ids are monotonically decreasing
+	sort.Slice(configMaps, func(i, j int) bool {
+		return configMaps[i].Labels["jobID"] < configMaps[j].Labels["jobID"]
+	})
+
+	var errs []error
+	for _, configMap := range configMaps[_maxCompletedJobStatesPerAPI:] {
+		_, err := config.K8s.DeleteConfigMap(configMap.Name)
+		errs = append(errs, err)
+	}
+
+	return errors.FirstError(errs...)
+}
+
+func (store *configMapJobStateStore) UpdateLiveness(jobKey spec.JobKey) error {
+	configMap, err := config.K8s.GetConfigMap(jobStateConfigMapName(jobKey))
+	if err != nil {
+		return errors.Wrap(err, "failed to update liveness", jobKey.UserString())
+	}
+
+	if configMap == nil {
+		return errors.Wrap(ErrorJobNotFound(jobKey), "failed to update liveness")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[_enqueuingLivenessFile] = time.Now().Format(time.RFC3339Nano)
+
+	_, err = config.K8s.UpdateConfigMap(configMap)
+	if err != nil {
+		return errors.Wrap(err, "failed to update liveness", jobKey.UserString())
+	}
+	return nil
+}
+
+func (store *configMapJobStateStore) ListInProgressJobKeys() ([]spec.JobKey, error) {
+	configMaps, err := config.K8s.ListConfigMapsByLabels(map[string]string{"jobState": "true", "inProgress": "true"})
+	if err != nil {
+		return nil, err
+	}
+	return jobKeysFromConfigMaps(configMaps), nil
+}
+
+func (store *configMapJobStateStore) ListInProgressJobKeysByAPI(apiName string) ([]spec.JobKey, error) {
+	configMaps, err := config.K8s.ListConfigMapsByLabels(map[string]string{"jobState": "true", "inProgress": "true", "apiName": apiName})
+	if err != nil {
+		return nil, err
+	}
+	return jobKeysFromConfigMaps(configMaps), nil
+}
+
+// the in progress label is updated along with the status
+func (store *configMapJobStateStore) DeleteInProgressJobKey(jobKey spec.JobKey) error {
+	return nil
+}
+
+func (store *configMapJobStateStore) IterateJobStates(apiName string, startAfterJobID string, fn func(*JobState) (bool, error)) error {
+	configMaps, err := config.K8s.ListConfigMapsByLabels(map[string]string{"jobState": "true", "apiName": apiName})
+	if err != nil {
+		return err
+	}
+
+	// job ids are monotonically decreasing
+	sort.Slice(configMaps, func(i, j int) bool {
+		return configMaps[i].Labels["jobID"] < configMaps[j].Labels["jobID"]
+	})
+
+	for i := range configMaps {
+		if configMaps[i].Labels["jobID"] <= startAfterJobID {
+			continue
+		}
+
+		jobState := jobStateFromConfigMap(&configMaps[i])
+		if shouldContinue, err := fn(&jobState); !shouldContinue || err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (store *configMapJobStateStore) DeleteJobStatesByAPI(apiName string) error {
+	configMaps, err := config.K8s.ListConfigMapsByLabels(map[string]string{"jobState": "true", "apiName": apiName})
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, configMap := range configMaps {
+		_, err := config.K8s.DeleteConfigMap(configMap.Name)
+		errs = append(errs, err)
+	}
+
+	return errors.FirstError(errs...)
+}
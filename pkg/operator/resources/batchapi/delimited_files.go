@@ -0,0 +1,338 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/schema"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+const (
+	_parquetRowsPerRead    = 1000
+	_parquetReadAheadBytes = 1024 * 1024
+)
+
+func enqueueJSONLFile(jobSpec *spec.Job, uploader *sqsBatchUploader, jsonMessageList *jsonBuffer, bucket string, s3Obj *s3.Object) error {
+	bytesBuffer := bytes.NewBuffer([]byte{})
+	itemIndex := 0
+	return config.AWS.S3FileIterator(bucket, s3Obj, _s3DownloadChunkSize, func(readCloser io.ReadCloser, isLastChunk bool) (bool, error) {
+		_, err := bytesBuffer.ReadFrom(readCloser)
+		if err != nil {
+			return false, err
+		}
+		err = streamJSONToQueue(jobSpec, uploader, bytesBuffer, jsonMessageList, &itemIndex)
+		if err != nil {
+			if err != io.ErrUnexpectedEOF || (err == io.ErrUnexpectedEOF && isLastChunk) {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// each row is enqueued as a json object keyed by the header's column names, or as a list of strings if the file doesn't have a header
+func enqueueCSVFile(jobSpec *spec.Job, uploader *sqsBatchUploader, jsonMessageList *jsonBuffer, bucket string, s3Obj *s3.Object, hasHeader bool) error {
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close() // unblocks the download if reading stops early
+
+	go func() {
+		err := config.AWS.S3FileIterator(bucket, s3Obj, _s3DownloadChunkSize, func(readCloser io.ReadCloser, isLastChunk bool) (bool, error) {
+			defer readCloser.Close()
+			if _, err := io.Copy(pipeWriter, readCloser); err != nil {
+				return false, err
+			}
+			return true, nil
+		})
+		pipeWriter.CloseWithError(err) // the reader receives io.EOF if err is nil
+	}()
+
+	csvReader := csv.NewReader(pipeReader)
+	var header []string
+	itemIndex := 0
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if hasHeader && header == nil {
+			header = record
+			continue
+		}
+
+		var item []byte
+		if header != nil {
+			item, err = csvRowToJSONObject(header, record)
+		} else {
+			item, err = json.Marshal(record)
+		}
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("item %d", itemIndex))
+		}
+
+		if err := addItemToQueue(jobSpec, uploader, jsonMessageList, item, &itemIndex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keeps the order of the columns (marshalling a map would sort the keys)
+func csvRowToJSONObject(header []string, record []string) ([]byte, error) {
+	buffer := bytes.NewBufferString("{")
+	for i, column := range header {
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+		keyBytes, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(record[i])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(keyBytes)
+		buffer.WriteString(":")
+		buffer.Write(valueBytes)
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+// each row is enqueued as a json object keyed by the schema's field names
+func enqueueParquetFile(jobSpec *spec.Job, uploader *sqsBatchUploader, jsonMessageList *jsonBuffer, bucket string, s3Obj *s3.Object) (err error) {
+	// the parquet reader panics on some malformed files
+	defer func() {
+		if errInterface := recover(); errInterface != nil {
+			err = errors.CastRecoverError(errInterface, "failed to read parquet file")
+		}
+	}()
+
+	parquetReader, err := reader.NewParquetReader(newS3ParquetFile(bucket, *s3Obj.Key, *s3Obj.Size), nil, 1)
+	if err != nil {
+		return errors.Wrap(errors.WithStack(err), "failed to read parquet file")
+	}
+	defer parquetReader.ReadStop()
+
+	converter := newParquetJSONConverter(parquetReader.SchemaHandler)
+	numRows := int(parquetReader.GetNumRows())
+
+	itemIndex := 0
+	for itemIndex < numRows {
+		rows, err := parquetReader.ReadByNumber(libmath.MinInt(_parquetRowsPerRead, numRows-itemIndex))
+		if err != nil {
+			return errors.Wrap(errors.WithStack(err), "failed to read parquet file")
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		for _, row := range rows {
+			item, err := json.Marshal(converter.convert(reflect.ValueOf(row), 0))
+			if err != nil {
+				return errors.Wrap(err, fmt.Sprintf("item %d", itemIndex))
+			}
+
+			if err := addItemToQueue(jobSpec, uploader, jsonMessageList, item, &itemIndex); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// parquetJSONConverter converts the rows read by the parquet reader into values which are marshalled using the schema's field names
+// (the reader's row types have capitalized field names so that they can be set by reflection)
+type parquetJSONConverter struct {
+	schemaHandler *schema.SchemaHandler
+	children      [][]int
+}
+
+func newParquetJSONConverter(schemaHandler *schema.SchemaHandler) *parquetJSONConverter {
+	converter := &parquetJSONConverter{
+		schemaHandler: schemaHandler,
+		children:      make([][]int, len(schemaHandler.SchemaElements)),
+	}
+
+	// schema elements are listed in depth-first order
+	var addChildren func(idx int) int
+	addChildren = func(idx int) int {
+		nextIdx := idx + 1
+		for i := 0; i < int(schemaHandler.SchemaElements[idx].GetNumChildren()); i++ {
+			converter.children[idx] = append(converter.children[idx], nextIdx)
+			nextIdx = addChildren(nextIdx)
+		}
+		return nextIdx
+	}
+	if len(schemaHandler.SchemaElements) > 0 {
+		addChildren(0)
+	}
+
+	return converter
+}
+
+func (c *parquetJSONConverter) convert(value reflect.Value, idx int) interface{} {
+	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		return c.convert(value.Elem(), idx)
+	}
+
+	children := c.children[idx]
+	element := c.schemaHandler.SchemaElements[idx]
+
+	if len(children) == 0 {
+		if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8 {
+			return c.convertSlice(value, idx) // repeated primitive
+		}
+		return value.Interface()
+	}
+
+	// lists and maps (as annotated by the parquet writer) are read into slices and maps
+	if element.ConvertedType != nil && *element.ConvertedType == parquet.ConvertedType_LIST && value.Kind() == reflect.Slice {
+		return c.convertSlice(value, c.children[children[0]][0])
+	}
+
+	if element.ConvertedType != nil && *element.ConvertedType == parquet.ConvertedType_MAP && value.Kind() == reflect.Map {
+		valueIdx := c.children[children[0]][1]
+		result := make(map[string]interface{}, value.Len())
+		for _, key := range value.MapKeys() {
+			result[fmt.Sprint(key.Interface())] = c.convert(value.MapIndex(key), valueIdx)
+		}
+		return result
+	}
+
+	if value.Kind() == reflect.Slice {
+		return c.convertSlice(value, idx) // repeated group
+	}
+
+	result := make(map[string]interface{}, len(children))
+	for i, childIdx := range children {
+		result[c.schemaHandler.GetExName(childIdx)] = c.convert(value.Field(i), childIdx)
+	}
+	return result
+}
+
+func (c *parquetJSONConverter) convertSlice(value reflect.Value, elementIdx int) []interface{} {
+	result := make([]interface{}, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		elementValue := value.Index(i)
+		if len(c.children[elementIdx]) == 0 {
+			result[i] = c.convertLeaf(elementValue)
+		} else {
+			result[i] = c.convert(elementValue, elementIdx)
+		}
+	}
+	return result
+}
+
+func (c *parquetJSONConverter) convertLeaf(value reflect.Value) interface{} {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		return value.Elem().Interface()
+	}
+	return value.Interface()
+}
+
+// s3ParquetFile reads a parquet file using ranged requests, since the reader seeks to the file's footer and to each column chunk
+type s3ParquetFile struct {
+	bucket       string
+	key          string
+	size         int64
+	offset       int64
+	buffer       []byte
+	bufferOffset int64
+}
+
+func newS3ParquetFile(bucket string, key string, size int64) *s3ParquetFile {
+	return &s3ParquetFile{
+		bucket: bucket,
+		key:    key,
+		size:   size,
+	}
+}
+
+func (f *s3ParquetFile) Read(p []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+
+	if f.offset < f.bufferOffset || f.offset >= f.bufferOffset+int64(len(f.buffer)) {
+		length := libmath.MinInt64(libmath.MaxInt64(int64(len(p)), _parquetReadAheadBytes), f.size-f.offset)
+		buffer, err := config.AWS.ReadBytesFromS3Range(f.bucket, f.key, f.offset, length)
+		if err != nil {
+			return 0, err
+		}
+		f.buffer = buffer
+		f.bufferOffset = f.offset
+	}
+
+	n := copy(p, f.buffer[f.offset-f.bufferOffset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *s3ParquetFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+		f.offset = offset
+	case io.SeekCurrent:
+		f.offset += offset
+	case io.SeekEnd:
+		f.offset = f.size + offset
+	}
+	return f.offset, nil
+}
+
+// the reader opens the file once for each column
+func (f *s3ParquetFile) Open(name string) (source.ParquetFile, error) {
+	return newS3ParquetFile(f.bucket, f.key, f.size), nil
+}
+
+func (f *s3ParquetFile) Write(p []byte) (int, error) {
+	return 0, errors.ErrorUnexpected("parquet files from s3 are read only")
+}
+
+func (f *s3ParquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, errors.ErrorUnexpected("parquet files from s3 are read only")
+}
+
+func (f *s3ParquetFile) Close() error {
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/schema"
+)
+
+func TestCSVRowToJSONObject(t *testing.T) {
+	item, err := csvRowToJSONObject([]string{"b", "a", `"quoted"`}, []string{"1", "", "x,y"})
+	require.NoError(t, err)
+	require.Equal(t, `{"b":"1","a":"","\"quoted\"":"x,y"}`, string(item))
+}
+
+type testParquetRow struct {
+	Name   string           `parquet:"name=name, type=UTF8"`
+	Age    *int32           `parquet:"name=age, type=INT32, repetitiontype=OPTIONAL"`
+	Tags   []string         `parquet:"name=tags, type=LIST, valuetype=UTF8"`
+	Scores map[string]int32 `parquet:"name=scores, type=MAP, keytype=UTF8, valuetype=INT32"`
+	Nested struct {
+		IsValid bool `parquet:"name=is_valid, type=BOOLEAN"`
+	} `parquet:"name=nested"`
+}
+
+func TestParquetJSONConverter(t *testing.T) {
+	schemaHandler, err := schema.NewSchemaHandlerFromStruct(new(testParquetRow))
+	require.NoError(t, err)
+	converter := newParquetJSONConverter(schemaHandler)
+
+	row := testParquetRow{
+		Name:   "test",
+		Age:    pointer.Int32(3),
+		Tags:   []string{"a", "b"},
+		Scores: map[string]int32{"x": 1},
+	}
+	row.Nested.IsValid = true
+
+	item, err := json.Marshal(converter.convert(reflect.ValueOf(row), 0))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"test","age":3,"tags":["a","b"],"scores":{"x":1},"nested":{"is_valid":true}}`, string(item))
+
+	item, err = json.Marshal(converter.convert(reflect.ValueOf(testParquetRow{Name: "empty"}), 0))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"empty","age":null,"tags":[],"scores":{},"nested":{"is_valid":false}}`, string(item))
+}
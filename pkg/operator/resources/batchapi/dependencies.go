@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// the submission of a pending job is stored until its dependencies succeed and it is enqueued
+func pendingSubmissionKey(jobKey spec.JobKey) string {
+	return path.Join(jobKey.Prefix(), "submission.json")
+}
+
+// fills in the api name of dependencies which don't specify one, removes duplicates, and verifies that each dependency has been submitted
+func normalizeDependencies(apiName string, dependsOn []spec.JobKey) ([]spec.JobKey, error) {
+	var dependencies []spec.JobKey
+	seen := map[spec.JobKey]bool{}
+
+	for i, dependency := range dependsOn {
+		if dependency.ID == "" {
+			return nil, errors.Wrap(cr.ErrorMustBeDefined(), schema.DependsOnKey, s.Index(i), "job_id")
+		}
+		if dependency.APIName == "" {
+			dependency.APIName = apiName
+		}
+		if seen[dependency] {
+			continue
+		}
+		seen[dependency] = true
+
+		if _, err := _jobStateStore.GetJobState(dependency); err != nil {
+			return nil, errors.Wrap(err, schema.DependsOnKey, s.Index(i))
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies, nil
+}
+
+// returns the dependencies which haven't completed yet, or ErrorDependencyFailed if any of them didn't succeed
+func getPendingDependencies(dependsOn []spec.JobKey) ([]spec.JobKey, error) {
+	var pendingDependencies []spec.JobKey
+
+	for _, dependency := range dependsOn {
+		jobState, err := _jobStateStore.GetJobState(dependency)
+		if err != nil {
+			if errors.GetKind(err) == ErrJobNotFound {
+				return nil, ErrorDependencyFailed(dependency, "the job was not found")
+			}
+			return nil, err
+		}
+
+		if jobState.Status == status.JobSucceeded {
+			continue
+		}
+
+		if jobState.Status.IsCompleted() {
+			return nil, ErrorDependencyFailed(dependency, "status: "+jobState.Status.Message())
+		}
+
+		pendingDependencies = append(pendingDependencies, dependency)
+	}
+
+	return pendingDependencies, nil
+}
+
+func dependenciesStr(dependencies []spec.JobKey) string {
+	strs := make([]string, len(dependencies))
+	for i, dependency := range dependencies {
+		strs[i] = dependency.UserString()
+	}
+	return strings.Join(strs, ", ")
+}
+
+func uploadPendingSubmission(jobKey spec.JobKey, submission *schema.JobSubmission) error {
+	return config.AWS.UploadJSONToS3(submission, config.Cluster.Bucket, pendingSubmissionKey(jobKey))
+}
+
+// called by the cron; enqueues the job once all of its dependencies have succeeded, or fails it if any of them didn't
+func reconcilePendingJob(jobKey spec.JobKey) error {
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return err
+	}
+
+	pendingDependencies, err := getPendingDependencies(jobSpec.DependsOn)
+	if err != nil {
+		if errors.GetKind(err) != ErrDependencyFailed {
+			return err
+		}
+		return errors.FirstError(
+			writeToJobLogStream(jobKey, errors.Message(err)),
+			_jobStateStore.SetStatus(jobKey, status.JobDependencyFailed),
+			deleteJobRuntimeResources(jobKey),
+		)
+	}
+
+	if len(pendingDependencies) > 0 {
+		return nil
+	}
+
+	// the job runs on the version of the api which was deployed when the job was submitted
+	apiSpec, submission, err := downloadStoredSubmission(jobSpec)
+	if err != nil {
+		return err
+	}
+
+	err = writeToJobLogStream(jobKey, fmt.Sprintf("all dependencies have succeeded (%s)", dependenciesStr(jobSpec.DependsOn)))
+	if err != nil {
+		return err
+	}
+
+	started, err := startOrQueueJob(jobSpec)
+	if err != nil || !started {
+		return err
+	}
+
+	writeToJobLogStream(jobKey, "started enqueuing batches")
+	deployStoredJob(apiSpec, jobSpec, submission)
+
+	return nil
+}
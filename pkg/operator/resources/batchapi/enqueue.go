@@ -21,7 +21,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"path"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -48,18 +47,9 @@ func randomMessageID() string {
 	return random.String(40) // maximum is 80 (for sqs.SendMessageBatchRequestEntry.Id) but this ID may show up in a user error message
 }
 
-func updateLiveness(jobKey spec.JobKey) error {
-	s3Key := path.Join(jobKey.Prefix(), _enqueuingLivenessFile)
-	err := config.AWS.UploadJSONToS3(time.Now(), config.Cluster.Bucket, s3Key)
-	if err != nil {
-		return errors.Wrap(err, "failed to update liveness", jobKey.UserString())
-	}
-	return nil
-}
-
 func enqueue(jobSpec *spec.Job, submission *schema.JobSubmission) (int, error) {
 	livenessUpdater := func() error {
-		return updateLiveness(jobSpec.JobKey)
+		return _jobStateStore.UpdateLiveness(jobSpec.JobKey)
 	}
 
 	livenessCron := cron.Run(livenessUpdater, operator.ErrorHandler(fmt.Sprintf("liveness check for %s", jobSpec.UserString())), _enqueuingLivenessPeriod)
@@ -231,25 +221,19 @@ func enqueueS3FileContents(jobSpec *spec.Job, delimitedFiles *schema.DelimitedFi
 	jsonMessageList := newJSONBuffer(delimitedFiles.BatchSize)
 	uploader := newSQSBatchUploader(jobSpec.SQSUrl)
 
-	bytesBuffer := bytes.NewBuffer([]byte{})
 	err := s3IteratorFromLister(delimitedFiles.S3Lister, func(bucket string, s3Obj *s3.Object) (bool, error) {
 		s3Path := awslib.S3Path(bucket, *s3Obj.Key)
 		writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("enqueuing contents from file %s", s3Path))
 
-		itemIndex := 0
-		err := config.AWS.S3FileIterator(bucket, s3Obj, _s3DownloadChunkSize, func(readCloser io.ReadCloser, isLastChunk bool) (bool, error) {
-			_, err := bytesBuffer.ReadFrom(readCloser)
-			if err != nil {
-				return false, err
-			}
-			err = streamJSONToQueue(jobSpec, uploader, bytesBuffer, jsonMessageList, &itemIndex)
-			if err != nil {
-				if err != io.ErrUnexpectedEOF || (err == io.ErrUnexpectedEOF && isLastChunk) {
-					return false, err
-				}
-			}
-			return true, nil
-		})
+		var err error
+		switch delimitedFiles.GetFormat() {
+		case schema.CSVDelimitedFilesFormat:
+			err = enqueueCSVFile(jobSpec, uploader, jsonMessageList, bucket, s3Obj, delimitedFiles.HasHeader())
+		case schema.ParquetDelimitedFilesFormat:
+			err = enqueueParquetFile(jobSpec, uploader, jsonMessageList, bucket, s3Obj)
+		default:
+			err = enqueueJSONLFile(jobSpec, uploader, jsonMessageList, bucket, s3Obj)
+		}
 		if err != nil {
 			return false, errors.Wrap(err, s3Path)
 		}
@@ -290,21 +274,30 @@ func streamJSONToQueue(jobSpec *spec.Job, uploader *sqsBatchUploader, bytesBuffe
 			return err
 		}
 
-		if len(doc) > _messageSizeLimit {
-			return errors.Wrap(ErrorMessageExceedsMaxSize(len(doc), _messageSizeLimit), fmt.Sprintf("item %d", *itemIndex))
+		err = addItemToQueue(jobSpec, uploader, jsonMessageList, doc, itemIndex)
+		if err != nil {
+			return err
 		}
-		*itemIndex++
-		jsonMessageList.Add(doc)
-		if jsonMessageList.Length() == jsonMessageList.BatchSize {
-			err := addJSONObjectsToQueue(uploader, jsonMessageList)
-			if err != nil {
-				return err
-			}
-			jsonMessageList.Clear()
+	}
 
-			if uploader.TotalBatches%100 == 0 {
-				writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("enqueued %d batches", uploader.TotalBatches))
-			}
+	return nil
+}
+
+func addItemToQueue(jobSpec *spec.Job, uploader *sqsBatchUploader, jsonMessageList *jsonBuffer, item json.RawMessage, itemIndex *int) error {
+	if len(item) > _messageSizeLimit {
+		return errors.Wrap(ErrorMessageExceedsMaxSize(len(item), _messageSizeLimit), fmt.Sprintf("item %d", *itemIndex))
+	}
+	*itemIndex++
+	jsonMessageList.Add(item)
+	if jsonMessageList.Length() == jsonMessageList.BatchSize {
+		err := addJSONObjectsToQueue(uploader, jsonMessageList)
+		if err != nil {
+			return err
+		}
+		jsonMessageList.Clear()
+
+		if uploader.TotalBatches%100 == 0 {
+			writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("enqueued %d batches", uploader.TotalBatches))
 		}
 	}
 
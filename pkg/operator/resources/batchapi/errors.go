@@ -21,20 +21,35 @@ import (
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 )
 
 const (
-	ErrJobNotFound                = "batchapi.job_not_found"
-	ErrJobIsNotInProgress         = "batchapi.job_is_not_in_progress"
-	ErrJobHasAlreadyBeenStopped   = "batchapi.job_has_already_been_stopped"
-	ErrNoS3FilesFound             = "batchapi.no_s3_files_found"
-	ErrNoDataFoundInJobSubmission = "batchapi.no_data_found_in_job_submission"
-	ErrFailedToEnqueueMessages    = "batchapi.failed_to_enqueue_messages"
-	ErrMessageExceedsMaxSize      = "batchapi.message_exceeds_max_size"
-	ErrConflictingFields          = "batchapi.conflicting_fields"
-	ErrBatchItemSizeExceedsLimit  = "batchapi.item_size_exceeds_limit"
-	ErrSpecifyExactlyOneKey       = "batchapi.specify_exactly_one_key"
+	ErrJobNotFound                 = "batchapi.job_not_found"
+	ErrJobIsNotInProgress          = "batchapi.job_is_not_in_progress"
+	ErrJobHasAlreadyBeenStopped    = "batchapi.job_has_already_been_stopped"
+	ErrNoS3FilesFound              = "batchapi.no_s3_files_found"
+	ErrNoDataFoundInJobSubmission  = "batchapi.no_data_found_in_job_submission"
+	ErrFailedToEnqueueMessages     = "batchapi.failed_to_enqueue_messages"
+	ErrMessageExceedsMaxSize       = "batchapi.message_exceeds_max_size"
+	ErrConflictingFields           = "batchapi.conflicting_fields"
+	ErrBatchItemSizeExceedsLimit   = "batchapi.item_size_exceeds_limit"
+	ErrSpecifyExactlyOneKey        = "batchapi.specify_exactly_one_key"
+	ErrNoFailedBatches             = "batchapi.no_failed_batches"
+	ErrDeadLetterQueueNotFound     = "batchapi.dead_letter_queue_not_found"
+	ErrInvalidResultFormat         = "batchapi.invalid_result_format"
+	ErrInvalidConcurrencyPolicy    = "batchapi.invalid_concurrency_policy"
+	ErrScheduledJobNotFound        = "batchapi.scheduled_job_not_found"
+	ErrDependencyFailed            = "batchapi.dependency_failed"
+	ErrInvalidJobStatusTransition  = "batchapi.invalid_job_status_transition"
+	ErrInvalidJobStateStore        = "batchapi.invalid_job_state_store"
+	ErrInvalidJobStatusFilter      = "batchapi.invalid_job_status_filter"
+	ErrInvalidDelimitedFilesFormat = "batchapi.invalid_delimited_files_format"
+	ErrFieldNotSupportedForFormat  = "batchapi.field_not_supported_for_format"
+	ErrWorkersExceedClusterQuota   = "batchapi.workers_exceed_cluster_quota"
 )
 
 func ErrorJobNotFound(jobKey spec.JobKey) error {
@@ -109,3 +124,87 @@ func ErrorSpecifyExactlyOneKey(key string, keys ...string) error {
 		Message: fmt.Sprintf("specify exactly one of the following keys: %s", s.StrsOr(allKeys)), // TODO add job specification documentation
 	})
 }
+
+func ErrorNoFailedBatches(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoFailedBatches,
+		Message: fmt.Sprintf("batch job %s does not have any failed batches to resubmit", jobKey.UserString()),
+	})
+}
+
+func ErrorDeadLetterQueueNotFound(jobKey spec.JobKey) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDeadLetterQueueNotFound,
+		Message: fmt.Sprintf("the failed batches of batch job %s are not available (failed batches are retained for %d days after they fail)", jobKey.UserString(), int(_deadLetterRetentionPeriod.Hours()/24)),
+	})
+}
+
+func ErrorInvalidResultFormat() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidResultFormat,
+		Message: fmt.Sprintf("must be one of: %s", s.StrsOr(spec.ResultFormatStrings())),
+	})
+}
+
+func ErrorInvalidConcurrencyPolicy(provided string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidConcurrencyPolicy,
+		Message: fmt.Sprintf("invalid concurrency policy %s; must be one of: %s", s.UserStr(provided), s.StrsOr(schema.ConcurrencyPolicyStrings())),
+	})
+}
+
+func ErrorScheduledJobNotFound(apiName string, name string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrScheduledJobNotFound,
+		Message: fmt.Sprintf("scheduled job %s for api %s was not found", name, apiName),
+	})
+}
+
+func ErrorDependencyFailed(dependency spec.JobKey, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrDependencyFailed,
+		Message: fmt.Sprintf("dependency %s did not succeed (%s)", dependency.UserString(), reason),
+	})
+}
+
+func ErrorInvalidJobStatusTransition(jobKey spec.JobKey, from status.JobCode, to status.JobCode) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobStatusTransition,
+		Message: fmt.Sprintf("unable to change the status of job %s from %s to %s", jobKey.UserString(), s.UserStr(from.Message()), s.UserStr(to.Message())),
+	})
+}
+
+func ErrorInvalidJobStateStore(provided string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobStateStore,
+		Message: fmt.Sprintf("invalid job state store %s; must be one of: %s", s.UserStr(provided), s.StrsOr(clusterconfig.JobStateStoreStrings())),
+	})
+}
+
+func ErrorInvalidJobStatusFilter(provided string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidJobStatusFilter,
+		Message: fmt.Sprintf("invalid job status %s; must be one of: %s", s.UserStr(provided), s.StrsOr(jobStatusFilterStrings())),
+	})
+}
+
+func ErrorInvalidDelimitedFilesFormat(provided string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidDelimitedFilesFormat,
+		Message: fmt.Sprintf("invalid format %s; must be one of: %s", s.UserStr(provided), s.StrsOr(schema.DelimitedFilesFormatStrings())),
+	})
+}
+
+func ErrorFieldNotSupportedForFormat(field string, format schema.DelimitedFilesFormat) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldNotSupportedForFormat,
+		Message: fmt.Sprintf("the %s field is not supported for files with format %s", field, format.String()),
+	})
+}
+
+func ErrorWorkersExceedClusterQuota(workers int, maxConcurrentWorkers int) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrWorkersExceedClusterQuota,
+		Message: fmt.Sprintf("the job requests %d %s, but at most %d %s can run at the same time in this cluster (%s)", workers, s.PluralS("worker", workers), maxConcurrentWorkers, s.PluralS("worker", maxConcurrentWorkers), clusterconfig.BatchMaxConcurrentWorkersKey),
+	})
+}
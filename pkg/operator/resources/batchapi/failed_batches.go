@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+// messages are hidden while the dead-letter queue is being read so that each message is only received once; they are released afterwards
+const _deadLetterReadVisibilityTimeout = 60
+
+// these message attributes are set by the workers when a batch is moved to the dead-letter queue
+const (
+	_batchIDAttribute  = "batch_id"
+	_attemptsAttribute = "attempts"
+	_errorAttribute    = "error"
+)
+
+func GetFailedBatches(jobKey spec.JobKey) (*schema.GetFailedBatchesResponse, error) {
+	queueURL, err := getJobDeadLetterQueueURL(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := readAllMessages(queueURL)
+	if err != nil {
+		if awslib.IsErrCode(err, sqs.ErrCodeQueueDoesNotExist) {
+			return nil, ErrorDeadLetterQueueNotFound(jobKey)
+		}
+		return nil, err
+	}
+
+	failedBatches := make([]schema.FailedBatch, len(messages))
+	for i, message := range messages {
+		failedBatches[i] = failedBatchFromMessage(message)
+	}
+
+	return &schema.GetFailedBatchesResponse{
+		JobKey:  jobKey,
+		Batches: failedBatches,
+	}, nil
+}
+
+// ResubmitFailedBatches submits a new job (with the original job's configuration) whose items are the items of the original job's failed batches
+func ResubmitFailedBatches(jobKey spec.JobKey) (*spec.Job, error) {
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	failedBatches, err := GetFailedBatches(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(failedBatches.Batches) == 0 {
+		return nil, ErrorNoFailedBatches(jobKey)
+	}
+
+	// the batches are regrouped into batches of the size of the largest failed batch, which preserves them if they were all the same size
+	var items []json.RawMessage
+	batchSize := 1
+	for _, failedBatch := range failedBatches.Batches {
+		var batchItems []json.RawMessage
+		if err := json.Unmarshal(failedBatch.Payload, &batchItems); err != nil {
+			return nil, errors.Wrap(err, "failed batch "+failedBatch.BatchID)
+		}
+		items = append(items, batchItems...)
+		if len(batchItems) > batchSize {
+			batchSize = len(batchItems)
+		}
+	}
+
+	runtimeConfig := jobSpec.RuntimeJobConfig
+	if jobSpec.InitialWorkers > 0 {
+		runtimeConfig.Workers = jobSpec.InitialWorkers // the original job's workers may have been increased while it was running
+	}
+
+	submission := &schema.JobSubmission{
+		RuntimeJobConfig: runtimeConfig,
+		ItemList: &schema.ItemList{
+			Items:     items,
+			BatchSize: batchSize,
+		},
+	}
+
+	return SubmitJob(jobKey.APIName, submission)
+}
+
+func failedBatchFromMessage(message *sqs.Message) schema.FailedBatch {
+	failedBatch := schema.FailedBatch{
+		BatchID: *message.MessageId,
+	}
+
+	body := aws.StringValue(message.Body)
+	if json.Valid([]byte(body)) {
+		failedBatch.Payload = json.RawMessage(body)
+	} else {
+		// batches are always json, but the payload must be valid json for the response to be
+		failedBatch.Payload, _ = json.Marshal(body)
+	}
+
+	if attribute, ok := message.MessageAttributes[_batchIDAttribute]; ok {
+		failedBatch.BatchID = aws.StringValue(attribute.StringValue)
+	}
+	if attribute, ok := message.MessageAttributes[_attemptsAttribute]; ok {
+		failedBatch.Attempts, _ = s.ParseInt(aws.StringValue(attribute.StringValue))
+	}
+	if attribute, ok := message.MessageAttributes[_errorAttribute]; ok {
+		failedBatch.Error = aws.StringValue(attribute.StringValue)
+	}
+	if sentTimestamp, ok := s.ParseInt64(aws.StringValue(message.Attributes[sqs.MessageSystemAttributeNameSentTimestamp])); ok {
+		failedBatch.FailedAt = pointer.Time(time.Unix(0, sentTimestamp*int64(time.Millisecond)))
+	}
+
+	return failedBatch
+}
+
+// receives every message in the queue without deleting them
+func readAllMessages(queueURL string) ([]*sqs.Message, error) {
+	var messages []*sqs.Message
+	receivedMessageIDs := map[string]bool{}
+
+	defer func() {
+		releaseMessages(queueURL, messages)
+	}()
+
+	for {
+		output, err := config.AWS.SQS().ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String(queueURL),
+			MaxNumberOfMessages:   aws.Int64(10),
+			WaitTimeSeconds:       aws.Int64(1),
+			VisibilityTimeout:     aws.Int64(_deadLetterReadVisibilityTimeout),
+			AttributeNames:        aws.StringSlice([]string{sqs.MessageSystemAttributeNameSentTimestamp}),
+			MessageAttributeNames: aws.StringSlice([]string{"All"}),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read sqs queue", queueURL)
+		}
+
+		// messages are received again if reading the queue takes longer than the visibility timeout
+		numNewMessages := 0
+		for _, message := range output.Messages {
+			if receivedMessageIDs[*message.MessageId] {
+				continue
+			}
+			receivedMessageIDs[*message.MessageId] = true
+			messages = append(messages, message)
+			numNewMessages++
+		}
+
+		if numNewMessages == 0 {
+			return messages, nil
+		}
+	}
+}
+
+// best effort
+func releaseMessages(queueURL string, messages []*sqs.Message) {
+	for i := 0; i < len(messages); i += 10 {
+		var entries []*sqs.ChangeMessageVisibilityBatchRequestEntry
+		for j := i; j < i+10 && j < len(messages); j++ {
+			entries = append(entries, &sqs.ChangeMessageVisibilityBatchRequestEntry{
+				Id:                aws.String(s.Int(j)),
+				ReceiptHandle:     messages[j].ReceiptHandle,
+				VisibilityTimeout: aws.Int64(0),
+			})
+		}
+		config.AWS.SQS().ChangeMessageVisibilityBatch(&sqs.ChangeMessageVisibilityBatchInput{
+			QueueUrl: aws.String(queueURL),
+			Entries:  entries,
+		})
+	}
+}
@@ -18,7 +18,6 @@ package batchapi
 
 import (
 	"fmt"
-	"math"
 	"sync"
 	"time"
 
@@ -28,6 +27,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/operator/operator"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
 	kmeta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klabels "k8s.io/apimachinery/pkg/labels"
 )
@@ -39,8 +39,7 @@ func monotonicallyDecreasingJobID() string {
 	jobIDMutex.Lock()
 	defer jobIDMutex.Unlock()
 
-	i := math.MaxInt64 - time.Now().UnixNano()
-	return fmt.Sprintf("%x", i)
+	return jobIDForTime(time.Now())
 }
 
 func DryRun(submission *schema.JobSubmission) ([]string, error) {
@@ -76,6 +75,17 @@ func SubmitJob(apiName string, submission *schema.JobSubmission) (*spec.Job, err
 		return nil, err
 	}
 
+	dependsOn, err := normalizeDependencies(apiName, submission.DependsOn)
+	if err != nil {
+		return nil, err
+	}
+
+	// submissions whose dependencies have already failed are rejected
+	pendingDependencies, err := getPendingDependencies(dependsOn)
+	if err != nil {
+		return nil, err
+	}
+
 	virtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiName))
 	if err != nil {
 		return nil, err
@@ -106,39 +116,87 @@ func SubmitJob(apiName string, submission *schema.JobSubmission) (*spec.Job, err
 		return nil, err
 	}
 
+	deadLetterQueueURL, err := createDeadLetterQueue(jobKey, tags)
+	if err != nil {
+		deleteQueueByURL(queueURL)
+		return nil, err
+	}
+
+	deleteQueues := func() {
+		deleteQueueByURL(queueURL)
+		deleteQueueByURL(deadLetterQueueURL)
+	}
+
+	runtimeJobConfig := runtimeJobConfigWithDefaults(submission.RuntimeJobConfig)
 	jobSpec := spec.Job{
-		RuntimeJobConfig: submission.RuntimeJobConfig,
+		RuntimeJobConfig: runtimeJobConfig,
+		InitialWorkers:   runtimeJobConfig.Workers,
 		JobKey:           jobKey,
 		APIID:            apiSpec.ID,
 		SQSUrl:           queueURL,
+		DeadLetterSQSUrl: deadLetterQueueURL,
+		DependsOn:        dependsOn,
 		StartTime:        time.Now(),
 	}
 
 	err = uploadJobSpec(&jobSpec)
 	if err != nil {
-		deleteQueueByURL(queueURL)
+		deleteQueues()
 		return nil, err
 	}
 
 	err = createOperatorLogStreamForJob(jobSpec.JobKey)
 	if err != nil {
-		deleteQueueByURL(queueURL)
+		deleteQueues()
 		return nil, err
 	}
 
-	err = setEnqueuingStatus(jobKey)
+	if len(pendingDependencies) > 0 {
+		err = uploadPendingSubmission(jobKey, submission)
+		if err != nil {
+			deleteQueues()
+			return nil, err
+		}
+
+		err = _jobStateStore.SetStatus(jobKey, status.JobPending)
+		if err != nil {
+			deleteQueues()
+			return nil, err
+		}
+
+		writeToJobLogStream(jobKey, "waiting for dependencies to succeed: "+dependenciesStr(pendingDependencies))
+		return &jobSpec, nil
+	}
+
+	// the submission is stored in case the job has to wait for the cluster's job quota
+	if isJobQuotaEnabled() {
+		err = uploadPendingSubmission(jobKey, submission)
+		if err != nil {
+			deleteQueues()
+			return nil, err
+		}
+	}
+
+	started, err := startOrQueueJob(&jobSpec)
 	if err != nil {
-		deleteQueueByURL(queueURL)
+		deleteQueues()
 		return nil, err
 	}
+	if !started {
+		return &jobSpec, nil
+	}
 
 	err = writeToJobLogStream(jobSpec.JobKey, "started enqueuing batches")
 	if err != nil {
-		deleteQueueByURL(queueURL)
+		deleteQueues()
 		return nil, err
 	}
 
-	go deployJob(apiSpec, &jobSpec, submission)
+	if isJobQuotaEnabled() {
+		deployStoredJob(apiSpec, &jobSpec, submission)
+	} else {
+		go deployJob(apiSpec, &jobSpec, submission)
+	}
 
 	return &jobSpec, nil
 }
@@ -165,7 +223,7 @@ func deployJob(apiSpec *spec.API, jobSpec *spec.Job, submission *schema.JobSubmi
 	if err != nil {
 		err := errors.FirstError(
 			writeToJobLogStream(jobSpec.JobKey, errors.Wrap(err, "failed to enqueue all batches").Error()),
-			setEnqueueFailedStatus(jobSpec.JobKey),
+			_jobStateStore.SetStatus(jobSpec.JobKey, status.JobEnqueueFailed),
 			deleteJobRuntimeResources(jobSpec.JobKey),
 		)
 		if err != nil {
@@ -181,7 +239,7 @@ func deployJob(apiSpec *spec.API, jobSpec *spec.Job, submission *schema.JobSubmi
 		if submission.DelimitedFiles != nil {
 			errs = append(errs, writeToJobLogStream(jobSpec.JobKey, "please verify that the files are not empty (the files being read can be retrieved by providing `dryRun=true` query param with your job submission"))
 		}
-		errs = append(errs, setEnqueueFailedStatus(jobSpec.JobKey))
+		errs = append(errs, _jobStateStore.SetStatus(jobSpec.JobKey, status.JobEnqueueFailed))
 		errs = append(errs, deleteJobRuntimeResources(jobSpec.JobKey))
 
 		err := errors.FirstError(errs...)
@@ -202,7 +260,7 @@ func deployJob(apiSpec *spec.API, jobSpec *spec.Job, submission *schema.JobSubmi
 		return
 	}
 
-	err = setRunningStatus(jobSpec.JobKey)
+	err = _jobStateStore.SetStatus(jobSpec.JobKey, status.JobRunning)
 	if err != nil {
 		handleJobSubmissionError(jobSpec.JobKey, err)
 		return
@@ -217,7 +275,7 @@ func deployJob(apiSpec *spec.API, jobSpec *spec.Job, submission *schema.JobSubmi
 func handleJobSubmissionError(jobKey spec.JobKey, jobErr error) {
 	err := errors.FirstError(
 		writeToJobLogStream(jobKey, jobErr.Error()),
-		setUnexpectedErrorStatus(jobKey),
+		_jobStateStore.SetStatus(jobKey, status.JobUnexpectedError),
 		deleteJobRuntimeResources(jobKey),
 	)
 	if err != nil {
@@ -265,7 +323,7 @@ func deleteJobRuntimeResources(jobKey spec.JobKey) error {
 }
 
 func StopJob(jobKey spec.JobKey) error {
-	jobState, err := getJobState(jobKey)
+	jobState, err := _jobStateStore.GetJobState(jobKey)
 	if err != nil {
 		go deleteJobRuntimeResources(jobKey)
 		return err
@@ -279,6 +337,6 @@ func StopJob(jobKey spec.JobKey) error {
 	writeToJobLogStream(jobKey, "request received to stop job; performing cleanup...")
 	return errors.FirstError(
 		deleteJobRuntimeResources(jobKey),
-		setStoppedStatus(jobKey),
+		_jobStateStore.SetStatus(jobKey, status.JobStopped),
 	)
 }
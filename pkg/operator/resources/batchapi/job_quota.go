@@ -0,0 +1,246 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// held while deciding whether a job can start, until its status is updated, so that concurrent submissions can't exceed the quota
+var _jobQuotaMutex = sync.Mutex{}
+
+type jobQuotaUsage struct {
+	MaxJobs    *int
+	MaxWorkers *int
+	Jobs       int         // enqueuing and running jobs
+	Workers    int         // workers of enqueuing and running jobs
+	QueuedJobs []*spec.Job // in the order in which they will be started
+}
+
+func isJobQuotaEnabled() bool {
+	return config.Cluster.BatchMaxConcurrentJobs != nil || config.Cluster.BatchMaxConcurrentWorkers != nil
+}
+
+func getJobQuotaUsage() (*jobQuotaUsage, error) {
+	usage := jobQuotaUsage{
+		MaxJobs:    config.Cluster.BatchMaxConcurrentJobs,
+		MaxWorkers: config.Cluster.BatchMaxConcurrentWorkers,
+	}
+
+	jobKeys, err := _jobStateStore.ListInProgressJobKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, jobKey := range jobKeys {
+		jobState, err := _jobStateStore.GetJobState(jobKey)
+		if err != nil {
+			if errors.GetKind(err) == ErrJobNotFound {
+				continue
+			}
+			return nil, err
+		}
+
+		if jobState.Status != status.JobEnqueuing && jobState.Status != status.JobRunning && jobState.Status != status.JobQueued {
+			continue
+		}
+
+		jobSpec, err := downloadJobSpec(jobKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if jobState.Status == status.JobQueued {
+			usage.QueuedJobs = append(usage.QueuedJobs, jobSpec)
+		} else {
+			usage.Add(jobSpec)
+		}
+	}
+
+	sort.Slice(usage.QueuedJobs, func(i, j int) bool {
+		return jobPrecedes(usage.QueuedJobs[i], usage.QueuedJobs[j])
+	})
+
+	return &usage, nil
+}
+
+// jobs with a higher priority start first, and jobs with the same priority start in the order in which they were submitted
+func jobPrecedes(left *spec.Job, right *spec.Job) bool {
+	if left.Priority != right.Priority {
+		return left.Priority > right.Priority
+	}
+	return left.ID > right.ID // job ids are monotonically decreasing
+}
+
+func (usage *jobQuotaUsage) Add(jobSpec *spec.Job) {
+	usage.Jobs++
+	usage.Workers += jobSpec.Workers
+}
+
+func (usage *jobQuotaUsage) Fits(jobSpec *spec.Job) bool {
+	if usage.MaxJobs != nil && usage.Jobs+1 > *usage.MaxJobs {
+		return false
+	}
+	if usage.MaxWorkers != nil && usage.Workers+jobSpec.Workers > *usage.MaxWorkers {
+		return false
+	}
+	return true
+}
+
+// the number of workers which can be added to running jobs without exceeding the quota (nil if unlimited); workers aren't added while jobs are queued
+func (usage *jobQuotaUsage) AvailableWorkers() *int {
+	if len(usage.QueuedJobs) > 0 {
+		available := 0
+		return &available
+	}
+	if usage.MaxWorkers == nil {
+		return nil
+	}
+	available := *usage.MaxWorkers - usage.Workers
+	if available < 0 {
+		available = 0
+	}
+	return &available
+}
+
+// the number of queued jobs which will start before the job, plus one
+func (usage *jobQuotaUsage) QueuePosition(jobSpec *spec.Job) int {
+	position := 1
+	for _, queuedJob := range usage.QueuedJobs {
+		if queuedJob.ID != jobSpec.ID && jobPrecedes(queuedJob, jobSpec) {
+			position++
+		}
+	}
+	return position
+}
+
+// the job can start if it fits within the quota and no queued job precedes it
+func (usage *jobQuotaUsage) CanStart(jobSpec *spec.Job) bool {
+	return usage.QueuePosition(jobSpec) == 1 && usage.Fits(jobSpec)
+}
+
+// sets the job's status to enqueuing if the cluster's job quota allows it to start, otherwise sets its status to queued (in which case the job's submission must have been stored)
+func startOrQueueJob(jobSpec *spec.Job) (bool, error) {
+	_jobQuotaMutex.Lock()
+	defer _jobQuotaMutex.Unlock()
+
+	if isJobQuotaEnabled() {
+		usage, err := getJobQuotaUsage()
+		if err != nil {
+			return false, err
+		}
+
+		if !usage.CanStart(jobSpec) {
+			err := errors.FirstError(
+				_jobStateStore.SetStatus(jobSpec.JobKey, status.JobQueued),
+				writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("queued until the cluster has capacity for this job (position %d in the queue)", usage.QueuePosition(jobSpec))),
+			)
+			if err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+	}
+
+	err := _jobStateStore.SetStatus(jobSpec.JobKey, status.JobEnqueuing)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// called by the cron; starts queued jobs in order while they fit within the cluster's job quota
+func startQueuedJobs() error {
+	_jobQuotaMutex.Lock()
+	defer _jobQuotaMutex.Unlock()
+
+	usage, err := getJobQuotaUsage()
+	if err != nil {
+		return err
+	}
+
+	for _, jobSpec := range usage.QueuedJobs {
+		if !usage.Fits(jobSpec) {
+			break // jobs are started in order so that large jobs aren't starved by smaller ones
+		}
+
+		apiSpec, submission, err := downloadStoredSubmission(jobSpec)
+		if err != nil {
+			telemetry.Error(err)
+			errors.PrintError(err)
+			continue
+		}
+
+		err = errors.FirstError(
+			_jobStateStore.SetStatus(jobSpec.JobKey, status.JobEnqueuing),
+			writeToJobLogStream(jobSpec.JobKey, "the cluster has capacity for this job", "started enqueuing batches"),
+		)
+		if err != nil {
+			telemetry.Error(err)
+			errors.PrintError(err)
+			continue
+		}
+
+		usage.Add(jobSpec)
+		deployStoredJob(apiSpec, jobSpec, submission)
+	}
+
+	return nil
+}
+
+func getQueuePosition(jobSpec *spec.Job) (*int, error) {
+	usage, err := getJobQuotaUsage()
+	if err != nil {
+		return nil, err
+	}
+	position := usage.QueuePosition(jobSpec)
+	return &position, nil
+}
+
+// downloads the submission of a pending or queued job, and the api spec which was deployed when the job was submitted
+func downloadStoredSubmission(jobSpec *spec.Job) (*spec.API, *schema.JobSubmission, error) {
+	submission := schema.JobSubmission{}
+	err := config.AWS.ReadJSONFromS3(&submission, config.Cluster.Bucket, pendingSubmissionKey(jobSpec.JobKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	apiSpec, err := operator.DownloadAPISpec(jobSpec.APIName, jobSpec.APIID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return apiSpec, &submission, nil
+}
+
+func deployStoredJob(apiSpec *spec.API, jobSpec *spec.Job, submission *schema.JobSubmission) {
+	go func() {
+		deployJob(apiSpec, jobSpec, submission)
+		config.AWS.DeleteS3File(config.Cluster.Bucket, pendingSubmissionKey(jobSpec.JobKey))
+	}()
+}
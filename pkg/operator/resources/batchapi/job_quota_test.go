@@ -0,0 +1,71 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func testQuotaJob(id string, workers int, priority int) *spec.Job {
+	return &spec.Job{
+		JobKey:           spec.JobKey{ID: id, APIName: "test"},
+		RuntimeJobConfig: spec.RuntimeJobConfig{Workers: workers, Priority: priority},
+	}
+}
+
+func TestJobQuotaUsage(t *testing.T) {
+	usage := jobQuotaUsage{MaxJobs: pointer.Int(2), MaxWorkers: pointer.Int(10)}
+	usage.Add(testQuotaJob("f", 6, 0))
+
+	require.True(t, usage.Fits(testQuotaJob("e", 4, 0)))
+	require.False(t, usage.Fits(testQuotaJob("e", 5, 0)))
+	require.Equal(t, 4, *usage.AvailableWorkers())
+
+	usage.Add(testQuotaJob("e", 1, 0))
+	require.False(t, usage.Fits(testQuotaJob("d", 1, 0)))
+
+	unlimited := jobQuotaUsage{}
+	unlimited.Add(testQuotaJob("f", 100, 0))
+	require.True(t, unlimited.Fits(testQuotaJob("e", 100, 0)))
+	require.Nil(t, unlimited.AvailableWorkers())
+}
+
+func TestQueuedJobOrder(t *testing.T) {
+	// job ids are monotonically decreasing, so "c" was submitted before "b" and "a"
+	usage := jobQuotaUsage{MaxJobs: pointer.Int(1)}
+	usage.QueuedJobs = []*spec.Job{testQuotaJob("a", 1, 5), testQuotaJob("c", 1, 0), testQuotaJob("b", 1, 0)}
+	sort.Slice(usage.QueuedJobs, func(i, j int) bool {
+		return jobPrecedes(usage.QueuedJobs[i], usage.QueuedJobs[j])
+	})
+
+	require.Equal(t, "a", usage.QueuedJobs[0].ID)
+	require.Equal(t, "c", usage.QueuedJobs[1].ID)
+	require.Equal(t, "b", usage.QueuedJobs[2].ID)
+
+	require.Equal(t, 1, usage.QueuePosition(usage.QueuedJobs[0]))
+	require.Equal(t, 3, usage.QueuePosition(usage.QueuedJobs[2]))
+
+	// a new job starts only if no queued job precedes it
+	require.False(t, usage.CanStart(testQuotaJob("0", 1, 0)))
+	require.True(t, usage.CanStart(testQuotaJob("0", 1, 6)))
+	require.Equal(t, 0, *usage.AvailableWorkers())
+}
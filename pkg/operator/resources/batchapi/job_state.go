@@ -18,12 +18,9 @@ package batchapi
 
 import (
 	"fmt"
-	"path"
-	"path/filepath"
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
-	"github.com/cortexlabs/cortex/pkg/lib/pointer"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/cortexlabs/cortex/pkg/types/status"
@@ -31,10 +28,6 @@ import (
 	kcore "k8s.io/api/core/v1"
 )
 
-const (
-	_averageFilesPerJobState = 10
-)
-
 type JobState struct {
 	spec.JobKey
 	Status         status.JobCode
@@ -60,6 +53,10 @@ func getStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
 		return status.JobStopped
 	}
 
+	if _, ok := lastUpdatedMap[status.JobDependencyFailed.String()]; ok {
+		return status.JobDependencyFailed
+	}
+
 	if _, ok := lastUpdatedMap[status.JobWorkerOOM.String()]; ok {
 		return status.JobWorkerOOM
 	}
@@ -92,234 +89,53 @@ func getStatusCode(lastUpdatedMap map[string]time.Time) status.JobCode {
 		return status.JobEnqueuing
 	}
 
-	return status.JobUnknown
-}
-
-func getJobState(jobKey spec.JobKey) (*JobState, error) {
-	s3Objects, err := config.AWS.ListS3Prefix(config.Cluster.Bucket, jobKey.Prefix(), false, nil)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get job state", jobKey.UserString())
-	}
-
-	if len(s3Objects) == 0 {
-		return nil, errors.Wrap(ErrorJobNotFound(jobKey), "failed to get job state")
+	if _, ok := lastUpdatedMap[status.JobQueued.String()]; ok {
+		return status.JobQueued
 	}
 
-	lastUpdatedMap := map[string]time.Time{}
-
-	for _, s3Object := range s3Objects {
-		lastUpdatedMap[filepath.Base(*s3Object.Key)] = *s3Object.LastModified
+	if _, ok := lastUpdatedMap[status.JobPending.String()]; ok {
+		return status.JobPending
 	}
 
-	jobState := getJobStateFromFiles(jobKey, lastUpdatedMap)
-	return &jobState, nil
+	return status.JobUnknown
 }
 
 func getJobStateFromFiles(jobKey spec.JobKey, lastUpdatedFileMap map[string]time.Time) JobState {
-	statusCode := getStatusCode(lastUpdatedFileMap)
+	return newJobState(jobKey, getStatusCode(lastUpdatedFileMap), lastUpdatedFileMap)
+}
 
+// lastUpdatedMap contains the time at which each of the job's statuses was set, as well as the time of the latest liveness update (if any)
+func newJobState(jobKey spec.JobKey, statusCode status.JobCode, lastUpdatedMap map[string]time.Time) JobState {
 	var jobEndTime *time.Time
 	if statusCode.IsCompleted() {
-		if endTime, ok := lastUpdatedFileMap[statusCode.String()]; ok {
+		if endTime, ok := lastUpdatedMap[statusCode.String()]; ok {
 			jobEndTime = &endTime
 		}
 	}
 
 	return JobState{
 		JobKey:         jobKey,
-		LastUpdatedMap: lastUpdatedFileMap,
+		LastUpdatedMap: lastUpdatedMap,
 		Status:         statusCode,
 		EndTime:        jobEndTime,
 	}
 }
 
 func getMostRecentlySubmittedJobStates(apiName string, count int) ([]*JobState, error) {
-	// a single job state may include 5 files on average, overshoot the number of files needed
-	s3Objects, err := config.AWS.ListS3Prefix(config.Cluster.Bucket, spec.BatchAPIJobPrefix(apiName), false, pointer.Int64(int64(count*_averageFilesPerJobState)))
-	if err != nil {
-		return nil, err
-	}
-
-	// job id -> file name -> last update timestamp
-	lastUpdatedMaps := map[string]map[string]time.Time{}
-
-	jobIDOrder := []string{}
-	for _, s3Object := range s3Objects {
-		fileName := filepath.Base(*s3Object.Key)
-		jobID := filepath.Base(filepath.Dir(*s3Object.Key))
-
-		if _, ok := lastUpdatedMaps[jobID]; !ok {
-			jobIDOrder = append(jobIDOrder, jobID)
-			lastUpdatedMaps[jobID] = map[string]time.Time{fileName: *s3Object.LastModified}
-		} else {
-			lastUpdatedMaps[jobID][fileName] = *s3Object.LastModified
-		}
-	}
-
 	jobStates := make([]*JobState, 0, count)
-
-	jobStateCount := 0
-	for _, jobID := range jobIDOrder {
-		jobState := getJobStateFromFiles(spec.JobKey{APIName: apiName, ID: jobID}, lastUpdatedMaps[jobID])
-		jobStates = append(jobStates, &jobState)
-
-		jobStateCount++
-		if jobStateCount == count {
-			break
-		}
-	}
-
-	return jobStates, nil
-}
-
-func setStatusForJob(jobKey spec.JobKey, jobStatus status.JobCode) error {
-	switch jobStatus {
-	case status.JobEnqueuing:
-		return setEnqueuingStatus(jobKey)
-	case status.JobRunning:
-		return setRunningStatus(jobKey)
-	case status.JobEnqueueFailed:
-		return setEnqueueFailedStatus(jobKey)
-	case status.JobCompletedWithFailures:
-		return setCompletedWithFailuresStatus(jobKey)
-	case status.JobSucceeded:
-		return setSucceededStatus(jobKey)
-	case status.JobUnexpectedError:
-		return setUnexpectedErrorStatus(jobKey)
-	case status.JobWorkerError:
-		return setWorkerErrorStatus(jobKey)
-	case status.JobWorkerOOM:
-		return setWorkerOOMStatus(jobKey)
-	case status.JobStopped:
-		return setStoppedStatus(jobKey)
-	}
-	return nil
-}
-
-func setEnqueuingStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobEnqueuing.String()))
-	if err != nil {
-		return err
-	}
-
-	err = uploadInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setRunningStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobRunning.String()))
-	if err != nil {
-		return err
+	if count <= 0 {
+		return jobStates, nil
 	}
 
-	err = uploadInProgressFile(jobKey) // in progress file should already be there but just in case
+	err := _jobStateStore.IterateJobStates(apiName, "", func(jobState *JobState) (bool, error) {
+		jobStates = append(jobStates, jobState)
+		return len(jobStates) < count, nil
+	})
 	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setStoppedStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobStopped.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setSucceededStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobSucceeded.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setCompletedWithFailuresStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobCompletedWithFailures.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setWorkerErrorStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobWorkerError.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setWorkerOOMStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobWorkerOOM.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setEnqueueFailedStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobEnqueueFailed.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func setUnexpectedErrorStatus(jobKey spec.JobKey) error {
-	err := config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), status.JobUnexpectedError.String()))
-	if err != nil {
-		return err
-	}
-
-	err = deleteInProgressFile(jobKey)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return jobStates, nil
 }
 
 func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pods []kcore.Pod) (*status.JobStatus, error) {
@@ -346,14 +162,14 @@ func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pod
 		if latestJobCode != initialJobState.Status {
 			err := errors.FirstError(
 				writeToJobLogStream(jobKey, message),
-				setStatusForJob(jobKey, latestJobCode),
+				_jobStateStore.SetStatus(jobKey, latestJobCode),
 			)
 			if err != nil {
 				return nil, err
 			}
 		}
 
-		latestJobState, err = getJobState(jobKey)
+		latestJobState, err = _jobStateStore.GetJobState(jobKey)
 		if err != nil {
 			return nil, err
 		}
@@ -365,6 +181,14 @@ func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pod
 		Status:  latestJobState.Status,
 	}
 
+	if latestJobState.Status == status.JobQueued {
+		queuePosition, err := getQueuePosition(jobSpec)
+		if err != nil {
+			return nil, err
+		}
+		jobStatus.QueuePosition = queuePosition
+	}
+
 	if latestJobState.Status.IsInProgress() {
 		queueMetrics, err := getQueueMetrics(jobKey)
 		if err != nil {
@@ -385,7 +209,7 @@ func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pod
 			jobStatus.BatchMetrics = metrics
 
 			if k8sJob == nil {
-				err := setUnexpectedErrorStatus(jobKey)
+				err := _jobStateStore.SetStatus(jobKey, status.JobUnexpectedError)
 				if err != nil {
 					return nil, err
 				}
@@ -410,11 +234,21 @@ func getJobStatusFromJobState(initialJobState *JobState, k8sJob *kbatch.Job, pod
 		jobStatus.BatchMetrics = metrics
 	}
 
+	if latestJobState.Status == status.JobRunning || latestJobState.Status.IsCompleted() {
+		deadLetterQueueMetrics, err := getDeadLetterQueueMetrics(jobKey)
+		if err != nil {
+			return nil, err
+		}
+		if deadLetterQueueMetrics != nil {
+			jobStatus.BatchesInDeadLetterQueue = deadLetterQueueMetrics.TotalInQueue()
+		}
+	}
+
 	return &jobStatus, nil
 }
 
 func GetJobStatus(jobKey spec.JobKey) (*status.JobStatus, error) {
-	jobState, err := getJobState(jobKey)
+	jobState, err := _jobStateStore.GetJobState(jobKey)
 	if err != nil {
 		return nil, err
 	}
@@ -433,7 +267,7 @@ func GetJobStatus(jobKey spec.JobKey) (*status.JobStatus, error) {
 }
 
 func getJobStatusFromK8sJob(jobKey spec.JobKey, k8sJob *kbatch.Job, pods []kcore.Pod) (*status.JobStatus, error) {
-	jobState, err := getJobState(jobKey)
+	jobState, err := _jobStateStore.GetJobState(jobKey)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// JobStateStore persists the status of batch jobs and keeps track of which jobs are in progress
+type JobStateStore interface {
+	// returns ErrJobNotFound if no status has been set for the job
+	GetJobState(jobKey spec.JobKey) (*JobState, error)
+	// returns ErrInvalidJobStatusTransition if the job can't move from its current status to jobStatus
+	SetStatus(jobKey spec.JobKey, jobStatus status.JobCode) error
+	UpdateLiveness(jobKey spec.JobKey) error
+	ListInProgressJobKeys() ([]spec.JobKey, error)
+	ListInProgressJobKeysByAPI(apiName string) ([]spec.JobKey, error)
+	// removes a completed job from the in progress jobs (if it was left behind)
+	DeleteInProgressJobKey(jobKey spec.JobKey) error
+	// calls fn for the state of each of the api's jobs, most recently submitted first, starting after startAfterJobID (if provided); iteration stops when fn returns false
+	IterateJobStates(apiName string, startAfterJobID string, fn func(*JobState) (bool, error)) error
+	DeleteJobStatesByAPI(apiName string) error
+}
+
+var _jobStateStore JobStateStore = &s3JobStateStore{}
+
+// InitJobStateStore selects the job state store configured in the cluster config
+func InitJobStateStore(storeType clusterconfig.JobStateStore) error {
+	switch storeType {
+	case clusterconfig.S3JobStateStore:
+		_jobStateStore = &s3JobStateStore{}
+	case clusterconfig.ConfigMapJobStateStore:
+		_jobStateStore = &configMapJobStateStore{}
+	default:
+		return ErrorInvalidJobStateStore(storeType.String())
+	}
+	return nil
+}
+
+func validateJobStatusTransition(jobKey spec.JobKey, from status.JobCode, to status.JobCode) error {
+	if !from.CanTransitionTo(to) {
+		return ErrorInvalidJobStatusTransition(jobKey, from, to)
+	}
+	return nil
+}
+
+// returns JobUnknown if no status has been set for the job
+func currentJobStatus(store JobStateStore, jobKey spec.JobKey) (status.JobCode, error) {
+	jobState, err := store.GetJobState(jobKey)
+	if err != nil {
+		if errors.GetKind(err) == ErrJobNotFound {
+			return status.JobUnknown, nil
+		}
+		return status.JobUnknown, err
+	}
+	return jobState.Status, nil
+}
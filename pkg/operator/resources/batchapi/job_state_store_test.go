@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryJobStateStore(t *testing.T) {
+	store := newMemoryJobStateStore()
+	olderJobKey := spec.JobKey{APIName: "my-api", ID: "69b93378fa5c0219"}
+	newerJobKey := spec.JobKey{APIName: "my-api", ID: "69b93378fa5c0218"}
+
+	_, err := store.GetJobState(olderJobKey)
+	require.Equal(t, ErrJobNotFound, errors.GetKind(err))
+
+	require.NoError(t, store.SetStatus(olderJobKey, status.JobEnqueuing))
+	require.NoError(t, store.UpdateLiveness(olderJobKey))
+	require.NoError(t, store.SetStatus(olderJobKey, status.JobRunning))
+	require.NoError(t, store.SetStatus(newerJobKey, status.JobPending))
+
+	jobState, err := store.GetJobState(olderJobKey)
+	require.NoError(t, err)
+	require.Equal(t, status.JobRunning, jobState.Status)
+	require.Contains(t, jobState.LastUpdatedMap, _enqueuingLivenessFile)
+	require.Nil(t, jobState.EndTime)
+
+	jobKeys, err := store.ListInProgressJobKeysByAPI("my-api")
+	require.NoError(t, err)
+	require.Equal(t, []spec.JobKey{newerJobKey, olderJobKey}, jobKeys)
+
+	require.NoError(t, store.SetStatus(olderJobKey, status.JobSucceeded))
+
+	jobState, err = store.GetJobState(olderJobKey)
+	require.NoError(t, err)
+	require.NotNil(t, jobState.EndTime)
+
+	jobKeys, err = store.ListInProgressJobKeys()
+	require.NoError(t, err)
+	require.Equal(t, []spec.JobKey{newerJobKey}, jobKeys)
+
+	var jobIDs []string
+	err = store.IterateJobStates("my-api", "", func(jobState *JobState) (bool, error) {
+		jobIDs = append(jobIDs, jobState.ID)
+		return true, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{newerJobKey.ID, olderJobKey.ID}, jobIDs)
+
+	require.NoError(t, store.DeleteJobStatesByAPI("my-api"))
+	_, err = store.GetJobState(newerJobKey)
+	require.Equal(t, ErrJobNotFound, errors.GetKind(err))
+}
+
+func TestJobStatusTransitions(t *testing.T) {
+	store := newMemoryJobStateStore()
+	jobKey := spec.JobKey{APIName: "my-api", ID: "69b93378fa5c0219"}
+
+	err := store.SetStatus(jobKey, status.JobRunning)
+	require.Equal(t, ErrInvalidJobStatusTransition, errors.GetKind(err))
+
+	require.NoError(t, store.SetStatus(jobKey, status.JobPending))
+	require.NoError(t, store.SetStatus(jobKey, status.JobQueued))
+	require.NoError(t, store.SetStatus(jobKey, status.JobEnqueuing))
+	require.NoError(t, store.SetStatus(jobKey, status.JobEnqueuing))
+
+	for _, jobStatus := range []status.JobCode{status.JobPending, status.JobQueued} {
+		err = store.SetStatus(jobKey, jobStatus)
+		require.Equal(t, ErrInvalidJobStatusTransition, errors.GetKind(err))
+	}
+
+	require.NoError(t, store.SetStatus(jobKey, status.JobRunning))
+	require.NoError(t, store.SetStatus(jobKey, status.JobSucceeded))
+
+	for _, jobStatus := range []status.JobCode{status.JobRunning, status.JobSucceeded, status.JobStopped, status.JobWorkerError} {
+		err = store.SetStatus(jobKey, jobStatus)
+		require.Equal(t, ErrInvalidJobStatusTransition, errors.GetKind(err))
+	}
+
+	jobState, err := store.GetJobState(jobKey)
+	require.NoError(t, err)
+	require.Equal(t, status.JobSucceeded, jobState.Status)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatusCode(t *testing.T) {
+	now := time.Now()
+
+	require.Equal(t, status.JobPending, getStatusCode(map[string]time.Time{
+		"spec.json":                now,
+		status.JobPending.String(): now,
+	}))
+
+	// a pending job's status files are kept when it is enqueued
+	require.Equal(t, status.JobEnqueuing, getStatusCode(map[string]time.Time{
+		status.JobPending.String():   now,
+		status.JobEnqueuing.String(): now,
+	}))
+
+	require.Equal(t, status.JobDependencyFailed, getStatusCode(map[string]time.Time{
+		status.JobPending.String():          now,
+		status.JobDependencyFailed.String(): now,
+	}))
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+const (
+	DefaultJobsPageSize    = 20
+	MaxJobsPageSize        = 100
+	_maxJobsScannedPerPage = 1000 // bounds the work done per request when few jobs match the filter
+
+	_maxConcurrentJobStatusFetches = 10
+)
+
+// the groups of statuses which can be used to filter jobs, in addition to the statuses themselves
+var _jobStatusFilterGroups = map[string]func(status.JobCode) bool{
+	"in_progress": status.JobCode.IsInProgress,
+	"completed":   status.JobCode.IsCompleted,
+	"failed": func(code status.JobCode) bool {
+		return code.IsCompleted() && code != status.JobSucceeded && code != status.JobStopped
+	},
+}
+
+type JobFilter struct {
+	StatusFilter    func(status.JobCode) bool // all statuses match if nil
+	SubmittedAfter  *time.Time
+	SubmittedBefore *time.Time
+}
+
+// ParseJobStatusFilter parses a comma-separated list of job statuses (e.g. "worker_error") and status groups ("in_progress", "completed", or "failed")
+func ParseJobStatusFilter(statusFilter string) (func(status.JobCode) bool, error) {
+	var filters []func(status.JobCode) bool
+
+	for _, statusStr := range strings.Split(statusFilter, ",") {
+		statusStr = strings.TrimPrefix(strings.TrimSpace(statusStr), "status_")
+
+		if groupFilter, ok := _jobStatusFilterGroups[statusStr]; ok {
+			filters = append(filters, groupFilter)
+			continue
+		}
+
+		var jobCode status.JobCode
+		jobCode.UnmarshalText([]byte("status_" + statusStr))
+		if jobCode == status.JobUnknown {
+			return nil, ErrorInvalidJobStatusFilter(statusStr)
+		}
+		filters = append(filters, func(code status.JobCode) bool {
+			return code == jobCode
+		})
+	}
+
+	return func(code status.JobCode) bool {
+		for _, filter := range filters {
+			if filter(code) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func jobStatusFilterStrings() []string {
+	statusStrs := []string{}
+	for code := status.JobPending; code <= status.JobQueued; code++ {
+		statusStrs = append(statusStrs, strings.TrimPrefix(code.String(), "status_"))
+	}
+	return append(statusStrs, "in_progress", "completed", "failed")
+}
+
+// the id which a job submitted at t would have; job ids are monotonically decreasing
+func jobIDForTime(t time.Time) string {
+	return fmt.Sprintf("%x", math.MaxInt64-t.UnixNano())
+}
+
+func ListJobs(apiName string, cursor string, pageSize int, filter JobFilter) (*schema.ListJobsResponse, error) {
+	jobStates, nextCursor, err := listJobStates(apiName, cursor, pageSize, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	jobStatuses := make([]status.JobStatus, len(jobStates))
+	if len(jobStates) > 0 {
+		// each status requires several AWS (and for in progress jobs, kubernetes) requests, so they are fetched in parallel,
+		// with a limit on the number of concurrent fetches to avoid being throttled
+		semaphore := make(chan struct{}, _maxConcurrentJobStatusFetches)
+		fns := make([]func() error, len(jobStates))
+		for i := range jobStates {
+			i := i
+			fns[i] = func() error {
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				var jobStatus *status.JobStatus
+				var err error
+				if jobStates[i].Status.IsInProgress() {
+					jobStatus, err = GetJobStatus(jobStates[i].JobKey)
+				} else {
+					jobStatus, err = getJobStatusFromJobState(jobStates[i], nil, nil)
+				}
+				if err != nil {
+					return err
+				}
+				jobStatuses[i] = *jobStatus
+				return nil
+			}
+		}
+
+		if err := parallel.RunFirstErr(fns[0], fns[1:]...); err != nil {
+			return nil, err
+		}
+	}
+
+	return &schema.ListJobsResponse{
+		JobStatuses: jobStatuses,
+		NextCursor:  nextCursor,
+	}, nil
+}
+
+// returns the states of the jobs which match the filter (most recently submitted first), and the cursor to continue listing from (empty if there are no more jobs)
+func listJobStates(apiName string, cursor string, pageSize int, filter JobFilter) ([]*JobState, string, error) {
+	startAfterJobID := cursor
+	if filter.SubmittedBefore != nil {
+		if submittedBeforeJobID := jobIDForTime(*filter.SubmittedBefore); submittedBeforeJobID > startAfterJobID {
+			startAfterJobID = submittedBeforeJobID
+		}
+	}
+
+	var submittedAfterJobID string
+	if filter.SubmittedAfter != nil {
+		submittedAfterJobID = jobIDForTime(*filter.SubmittedAfter)
+	}
+
+	jobStates := []*JobState{}
+	numScanned := 0
+	lastScannedJobID := ""
+	nextCursor := ""
+
+	err := _jobStateStore.IterateJobStates(apiName, startAfterJobID, func(jobState *JobState) (bool, error) {
+		if submittedAfterJobID != "" && jobState.ID >= submittedAfterJobID {
+			return false, nil
+		}
+
+		// there is at least one more job to list
+		if len(jobStates) == pageSize || numScanned == _maxJobsScannedPerPage {
+			nextCursor = lastScannedJobID
+			return false, nil
+		}
+
+		numScanned++
+		lastScannedJobID = jobState.ID
+
+		if filter.StatusFilter == nil || filter.StatusFilter(jobState.Status) {
+			jobStates = append(jobStates, jobState)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return jobStates, nextCursor, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListJobStates(t *testing.T) {
+	store := newMemoryJobStateStore()
+	defer func(original JobStateStore) { _jobStateStore = original }(_jobStateStore)
+	_jobStateStore = store
+
+	startTime := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	// one job per hour, every third job has a worker error
+	var jobIDs []string
+	for i := 0; i < 10; i++ {
+		jobKey := spec.JobKey{APIName: "my-api", ID: jobIDForTime(startTime.Add(time.Duration(i) * time.Hour))}
+		require.NoError(t, store.SetStatus(jobKey, status.JobEnqueuing))
+		require.NoError(t, store.SetStatus(jobKey, status.JobRunning))
+		if i%3 == 0 {
+			require.NoError(t, store.SetStatus(jobKey, status.JobWorkerError))
+		} else {
+			require.NoError(t, store.SetStatus(jobKey, status.JobSucceeded))
+		}
+		jobIDs = append([]string{jobKey.ID}, jobIDs...)
+	}
+
+	listJobIDs := func(cursor string, pageSize int, filter JobFilter) ([]string, string) {
+		jobStates, nextCursor, err := listJobStates("my-api", cursor, pageSize, filter)
+		require.NoError(t, err)
+		ids := []string{}
+		for _, jobState := range jobStates {
+			ids = append(ids, jobState.ID)
+		}
+		return ids, nextCursor
+	}
+
+	ids, cursor := listJobIDs("", 4, JobFilter{})
+	require.Equal(t, jobIDs[:4], ids)
+	require.Equal(t, jobIDs[3], cursor)
+
+	ids, cursor = listJobIDs(cursor, 4, JobFilter{})
+	require.Equal(t, jobIDs[4:8], ids)
+
+	ids, cursor = listJobIDs(cursor, 4, JobFilter{})
+	require.Equal(t, jobIDs[8:], ids)
+	require.Empty(t, cursor)
+
+	failedFilter, err := ParseJobStatusFilter("failed")
+	require.NoError(t, err)
+	ids, cursor = listJobIDs("", 10, JobFilter{StatusFilter: failedFilter})
+	require.Equal(t, []string{jobIDs[0], jobIDs[3], jobIDs[6], jobIDs[9]}, ids)
+	require.Empty(t, cursor)
+
+	// submitted in (start + 2h, start + 6h)
+	filter := JobFilter{
+		SubmittedAfter:  pointer.Time(startTime.Add(2 * time.Hour)),
+		SubmittedBefore: pointer.Time(startTime.Add(6 * time.Hour)),
+	}
+	ids, cursor = listJobIDs("", 10, filter)
+	require.Equal(t, jobIDs[4:7], ids)
+	require.Empty(t, cursor)
+}
+
+func TestParseJobStatusFilter(t *testing.T) {
+	statusFilter, err := ParseJobStatusFilter("worker_error, status_worker_oom")
+	require.NoError(t, err)
+	require.True(t, statusFilter(status.JobWorkerError))
+	require.True(t, statusFilter(status.JobWorkerOOM))
+	require.False(t, statusFilter(status.JobEnqueueFailed))
+
+	statusFilter, err = ParseJobStatusFilter("in_progress")
+	require.NoError(t, err)
+	require.True(t, statusFilter(status.JobPending))
+	require.False(t, statusFilter(status.JobSucceeded))
+
+	_, err = ParseJobStatusFilter("unknown")
+	require.Equal(t, ErrInvalidJobStatusFilter, errors.GetKind(err))
+}
@@ -42,8 +42,11 @@ const (
 
 var jobsToDelete strset.Set = strset.New()
 
+// dead-letter queues are deleted once they have been empty for two consecutive iterations (to give time for queue metrics to reach consistency)
+var deadLetterQueuesToDelete strset.Set = strset.New()
+
 func ManageJobResources() error {
-	inProgressJobKeys, err := listAllInProgressJobKeys()
+	inProgressJobKeys, err := _jobStateStore.ListInProgressJobKeys()
 	if err != nil {
 		return err
 	}
@@ -75,11 +78,16 @@ func ManageJobResources() error {
 
 	k8sJobMap := map[string]*kbatch.Job{}
 	k8sJobIDSet := strset.Set{}
-	for _, job := range jobs {
-		k8sJobMap[job.Labels["jobID"]] = &job
+	for i := range jobs {
+		job := &jobs[i] // the loop variable is reused across iterations, so its address can't be stored
+		k8sJobMap[job.Labels["jobID"]] = job
 		k8sJobIDSet.Add(job.Labels["jobID"])
 	}
 
+	// used to limit the workers which are added to autoscaled jobs; computed once the first autoscaled job needs it
+	var quotaUsage *jobQuotaUsage
+	hasQueuedJobs := false
+
 	for _, jobKey := range inProgressJobKeys {
 		var queueURL *string
 		if queueJobIDSet.Has(jobKey.ID) {
@@ -88,7 +96,7 @@ func ManageJobResources() error {
 
 		k8sJob := k8sJobMap[jobKey.ID]
 
-		jobState, err := getJobState(jobKey)
+		jobState, err := _jobStateStore.GetJobState(jobKey)
 		if err != nil {
 			if err != nil {
 				telemetry.Error(err)
@@ -100,7 +108,7 @@ func ManageJobResources() error {
 		if !jobState.Status.IsInProgress() {
 			// best effort cleanup
 			err := errors.FirstError(
-				deleteInProgressFile(jobKey),
+				_jobStateStore.DeleteInProgressJobKey(jobKey),
 				deleteJobRuntimeResources(jobKey),
 			)
 			if err != nil {
@@ -110,6 +118,20 @@ func ManageJobResources() error {
 			}
 		}
 
+		if jobState.Status == status.JobPending {
+			err := reconcilePendingJob(jobKey)
+			if err != nil {
+				telemetry.Error(err)
+				errors.PrintError(err)
+			}
+			continue
+		}
+
+		if jobState.Status == status.JobQueued {
+			hasQueuedJobs = true
+			continue
+		}
+
 		newStatusCode, msg, err := reconcileInProgressJob(jobState, queueURL, k8sJob)
 		if err != nil {
 			telemetry.Error(err)
@@ -120,7 +142,7 @@ func ManageJobResources() error {
 		if newStatusCode != jobState.Status {
 			err = errors.FirstError(
 				writeToJobLogStream(jobKey, msg),
-				setStatusForJob(jobKey, newStatusCode),
+				_jobStateStore.SetStatus(jobKey, newStatusCode),
 			)
 			if err != nil {
 				telemetry.Error(err)
@@ -129,6 +151,14 @@ func ManageJobResources() error {
 			}
 		}
 
+		if newStatusCode == status.JobRunning && k8sJob != nil && queueURL != nil {
+			err = autoscaleJobWorkers(jobKey, *queueURL, k8sJob, &quotaUsage)
+			if err != nil {
+				telemetry.Error(err)
+				errors.PrintError(err)
+			}
+		}
+
 		err = checkIfJobCompleted(jobKey, *queueURL, k8sJob)
 		if err != nil {
 			telemetry.Error(err)
@@ -177,6 +207,14 @@ func ManageJobResources() error {
 		}
 	}
 
+	if hasQueuedJobs {
+		err := startQueuedJobs()
+		if err != nil {
+			telemetry.Error(err)
+			errors.PrintError(err)
+		}
+	}
+
 	// Clear old jobs to delete if they are no longer considered to in progress
 	for jobID := range jobsToDelete {
 		if !inProgressJobIDSet.Has(jobID) {
@@ -184,6 +222,61 @@ func ManageJobResources() error {
 		}
 	}
 
+	err = deleteEmptyDeadLetterQueues(inProgressJobIDSet)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// dead-letter queues are kept after their job completes so that the failed batches can be retrieved, until they are empty (sqs removes messages after the retention period)
+func deleteEmptyDeadLetterQueues(inProgressJobIDSet strset.Set) error {
+	deadLetterQueueURLs, err := listDeadLetterQueueURLsForAllAPIs()
+	if err != nil {
+		return err
+	}
+
+	emptyQueueURLs := strset.New()
+	for _, queueURL := range deadLetterQueueURLs {
+		if inProgressJobIDSet.Has(jobKeyFromQueueURL(queueURL).ID) {
+			continue
+		}
+
+		attributes, err := config.AWS.GetAllQueueAttributes(queueURL)
+		if err != nil {
+			telemetry.Error(err)
+			errors.PrintError(err)
+			continue
+		}
+
+		queueCreatedTimestamp := time.Time{}
+		parsedSeconds, ok := s.ParseInt64(attributes["CreatedTimestamp"])
+		if ok {
+			queueCreatedTimestamp = time.Unix(parsedSeconds, 0)
+		}
+
+		// queue was created recently, maybe there was a delay between the time queue was created and when the in progress file was written
+		if time.Now().Sub(queueCreatedTimestamp) <= _doesQueueExistGracePeriod {
+			continue
+		}
+
+		visible, _ := s.ParseInt(attributes["ApproximateNumberOfMessages"])
+		notVisible, _ := s.ParseInt(attributes["ApproximateNumberOfMessagesNotVisible"])
+		if visible+notVisible > 0 {
+			continue
+		}
+
+		emptyQueueURLs.Add(queueURL)
+		if deadLetterQueuesToDelete.Has(queueURL) {
+			if err := deleteQueueByURL(queueURL); err != nil {
+				telemetry.Error(err)
+				errors.PrintError(err)
+			}
+		}
+	}
+
+	deadLetterQueuesToDelete = emptyQueueURLs
 	return nil
 }
 
@@ -261,15 +354,21 @@ func checkIfJobCompleted(jobKey spec.JobKey, queueURL string, k8sJob *kbatch.Job
 
 	if jobSpec.TotalBatchCount == batchMetrics.TotalCompleted() {
 		jobsToDelete.Remove(jobKey.ID)
+
+		// the manifest is written before the status is updated so that it is available as soon as the job is completed
+		if err := writeResultManifest(jobSpec); err != nil {
+			writeToJobLogStream(jobKey, "failed to write the result manifest to "+jobSpec.ResultSink.ManifestS3Path(jobKey.ID)+": "+errors.Message(err))
+		}
+
 		if batchMetrics.Failed != 0 {
 			return errors.FirstError(
-				setCompletedWithFailuresStatus(jobKey),
+				_jobStateStore.SetStatus(jobKey, status.JobCompletedWithFailures),
 				deleteJobRuntimeResources(jobKey),
 			)
 		}
 
 		return errors.FirstError(
-			setSucceededStatus(jobKey),
+			_jobStateStore.SetStatus(jobKey, status.JobSucceeded),
 			deleteJobRuntimeResources(jobKey),
 		)
 	}
@@ -278,7 +377,7 @@ func checkIfJobCompleted(jobKey spec.JobKey, queueURL string, k8sJob *kbatch.Job
 		jobsToDelete.Remove(jobKey.ID)
 		return errors.FirstError(
 			writeToJobLogStream(jobKey, "unexpected job status because cluster state indicates job has completed but metrics indicate that job is still in progress"),
-			setUnexpectedErrorStatus(jobKey),
+			_jobStateStore.SetStatus(jobKey, status.JobUnexpectedError),
 			deleteJobRuntimeResources(jobKey),
 		)
 	}
@@ -297,7 +396,7 @@ func investigateJobFailure(jobKey spec.JobKey, k8sJob *kbatch.Job) error {
 		if k8s.WasPodOOMKilled(&pod) {
 			return errors.FirstError(
 				writeToJobLogStream(jobKey, "at least one worker was killed because it ran out of out of memory"),
-				setWorkerOOMStatus(jobKey),
+				_jobStateStore.SetStatus(jobKey, status.JobWorkerOOM),
 				deleteJobRuntimeResources(jobKey),
 			)
 		}
@@ -324,7 +423,7 @@ func investigateJobFailure(jobKey spec.JobKey, k8sJob *kbatch.Job) error {
 
 	return errors.FirstError(
 		err,
-		setWorkerErrorStatus(jobKey),
+		_jobStateStore.SetStatus(jobKey, status.JobWorkerError),
 		deleteJobRuntimeResources(jobKey),
 	)
 }
@@ -0,0 +1,161 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// memoryJobStateStore keeps job states in memory, and is intended for tests
+type memoryJobStateStore struct {
+	sync.Mutex
+	jobStates map[spec.JobKey]*JobState
+}
+
+func newMemoryJobStateStore() *memoryJobStateStore {
+	return &memoryJobStateStore{
+		jobStates: map[spec.JobKey]*JobState{},
+	}
+}
+
+// returns a copy so that callers can't modify the stored state
+func copyJobState(jobState *JobState) *JobState {
+	lastUpdatedMap := make(map[string]time.Time, len(jobState.LastUpdatedMap))
+	for key, lastUpdated := range jobState.LastUpdatedMap {
+		lastUpdatedMap[key] = lastUpdated
+	}
+	jobStateCopy := newJobState(jobState.JobKey, jobState.Status, lastUpdatedMap)
+	return &jobStateCopy
+}
+
+func (store *memoryJobStateStore) GetJobState(jobKey spec.JobKey) (*JobState, error) {
+	store.Lock()
+	defer store.Unlock()
+
+	jobState, ok := store.jobStates[jobKey]
+	if !ok {
+		return nil, errors.Wrap(ErrorJobNotFound(jobKey), "failed to get job state")
+	}
+
+	return copyJobState(jobState), nil
+}
+
+func (store *memoryJobStateStore) SetStatus(jobKey spec.JobKey, jobStatus status.JobCode) error {
+	store.Lock()
+	defer store.Unlock()
+
+	jobState, ok := store.jobStates[jobKey]
+	if !ok {
+		jobState = &JobState{JobKey: jobKey, Status: status.JobUnknown, LastUpdatedMap: map[string]time.Time{}}
+	}
+
+	if err := validateJobStatusTransition(jobKey, jobState.Status, jobStatus); err != nil {
+		return err
+	}
+
+	jobState.LastUpdatedMap[jobStatus.String()] = time.Now()
+	updatedJobState := newJobState(jobKey, jobStatus, jobState.LastUpdatedMap)
+	store.jobStates[jobKey] = &updatedJobState
+
+	return nil
+}
+
+func (store *memoryJobStateStore) UpdateLiveness(jobKey spec.JobKey) error {
+	store.Lock()
+	defer store.Unlock()
+
+	jobState, ok := store.jobStates[jobKey]
+	if !ok {
+		return errors.Wrap(ErrorJobNotFound(jobKey), "failed to update liveness")
+	}
+
+	jobState.LastUpdatedMap[_enqueuingLivenessFile] = time.Now()
+	return nil
+}
+
+func (store *memoryJobStateStore) listJobKeys(filter func(jobState *JobState) bool) []spec.JobKey {
+	store.Lock()
+	defer store.Unlock()
+
+	jobKeys := []spec.JobKey{}
+	for jobKey, jobState := range store.jobStates {
+		if filter(jobState) {
+			jobKeys = append(jobKeys, jobKey)
+		}
+	}
+
+	// job ids are monotonically decreasing
+	sort.Slice(jobKeys, func(i, j int) bool {
+		return jobKeys[i].ID < jobKeys[j].ID
+	})
+
+	return jobKeys
+}
+
+func (store *memoryJobStateStore) ListInProgressJobKeys() ([]spec.JobKey, error) {
+	return store.listJobKeys(func(jobState *JobState) bool {
+		return jobState.Status.IsInProgress()
+	}), nil
+}
+
+func (store *memoryJobStateStore) ListInProgressJobKeysByAPI(apiName string) ([]spec.JobKey, error) {
+	return store.listJobKeys(func(jobState *JobState) bool {
+		return jobState.APIName == apiName && jobState.Status.IsInProgress()
+	}), nil
+}
+
+func (store *memoryJobStateStore) DeleteInProgressJobKey(jobKey spec.JobKey) error {
+	return nil
+}
+
+func (store *memoryJobStateStore) IterateJobStates(apiName string, startAfterJobID string, fn func(*JobState) (bool, error)) error {
+	jobKeys := store.listJobKeys(func(jobState *JobState) bool {
+		return jobState.APIName == apiName && jobState.ID > startAfterJobID
+	})
+
+	for _, jobKey := range jobKeys {
+		jobState, err := store.GetJobState(jobKey)
+		if err != nil {
+			return err
+		}
+
+		if shouldContinue, err := fn(jobState); !shouldContinue || err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (store *memoryJobStateStore) DeleteJobStatesByAPI(apiName string) error {
+	store.Lock()
+	defer store.Unlock()
+
+	for jobKey := range store.jobStates {
+		if jobKey.APIName == apiName {
+			delete(store.jobStates, jobKey)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/metrics"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+const (
+	_throughputWindow = 5 * time.Minute
+)
+
+// GetJobProgress estimates the throughput and completion time of a running job, based on the batches completed within the most recent window
+// (which ends operator.CloudWatchIngestionDelay ago, since the batch metrics in the last few minutes are usually incomplete)
+func GetJobProgress(jobStatus *status.JobStatus) (*metrics.JobProgress, error) {
+	if jobStatus.Status != status.JobRunning || jobStatus.BatchMetrics == nil {
+		return nil, nil
+	}
+
+	jobState, err := _jobStateStore.GetJobState(jobStatus.JobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	windowEnd := now.Add(-operator.CloudWatchIngestionDelay).Truncate(time.Second)
+	windowStart := windowEnd.Add(-_throughputWindow)
+	if runningTime, ok := jobState.LastUpdatedMap[status.JobRunning.String()]; ok && runningTime.After(windowStart) {
+		windowStart = runningTime.Truncate(time.Second)
+	}
+
+	windowMetrics := metrics.BatchMetrics{}
+	if windowStart.Before(windowEnd) {
+		err := getMetricsFunc(&jobStatus.JobKey, 1, &windowStart, &windowEnd, &windowMetrics)()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	runningWorkers := 0
+	if jobStatus.WorkerCounts != nil {
+		runningWorkers = int(jobStatus.WorkerCounts.Running)
+	}
+
+	return calculateJobProgress(jobStatus.TotalBatchCount, jobStatus.BatchMetrics.TotalCompleted(), windowMetrics.TotalCompleted(), windowEnd.Sub(windowStart), runningWorkers, now), nil
+}
+
+func calculateJobProgress(totalBatches int, completedBatches int, completedBatchesInWindow int, window time.Duration, runningWorkers int, now time.Time) *metrics.JobProgress {
+	progress := metrics.JobProgress{}
+
+	progress.RemainingBatches = totalBatches - completedBatches
+	if progress.RemainingBatches < 0 {
+		progress.RemainingBatches = 0
+	}
+
+	if window > 0 {
+		progress.BatchesPerSecond = float64(completedBatchesInWindow) / window.Seconds()
+	}
+
+	if runningWorkers > 0 {
+		progress.BatchesPerSecondPerWorker = pointer.Float64(progress.BatchesPerSecond / float64(runningWorkers))
+	}
+
+	if progress.BatchesPerSecond > 0 {
+		remainingDuration := time.Duration(float64(progress.RemainingBatches) / progress.BatchesPerSecond * float64(time.Second))
+		progress.EstimatedCompletionTime = pointer.Time(now.Add(remainingDuration).Truncate(time.Second))
+	}
+
+	return &progress
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateJobProgress(t *testing.T) {
+	now := time.Date(2020, 7, 16, 14, 0, 0, 0, time.UTC)
+
+	progress := calculateJobProgress(1000, 400, 300, 5*time.Minute, 4, now)
+	require.Equal(t, 1.0, progress.BatchesPerSecond)
+	require.Equal(t, 0.25, *progress.BatchesPerSecondPerWorker)
+	require.Equal(t, 600, progress.RemainingBatches)
+	require.Equal(t, now.Add(10*time.Minute), *progress.EstimatedCompletionTime)
+
+	progress = calculateJobProgress(1000, 400, 0, 5*time.Minute, 0, now)
+	require.Equal(t, 0.0, progress.BatchesPerSecond)
+	require.Nil(t, progress.BatchesPerSecondPerWorker)
+	require.Nil(t, progress.EstimatedCompletionTime)
+
+	// batches which are retried can be counted more than once
+	progress = calculateJobProgress(10, 12, 2, time.Minute, 1, now)
+	require.Equal(t, 0, progress.RemainingBatches)
+	require.Equal(t, now, *progress.EstimatedCompletionTime)
+}
@@ -19,9 +19,11 @@ package batchapi
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/operator/config"
@@ -29,6 +31,11 @@ import (
 	"github.com/cortexlabs/cortex/pkg/types/spec"
 )
 
+const (
+	_deadLetterQueueSuffix     = "-dlq.fifo"
+	_deadLetterRetentionPeriod = 14 * 24 * time.Hour // the maximum supported by sqs
+)
+
 func apiQueueNamePrefix(apiName string) string {
 	return config.Cluster.SQSNamePrefix() + apiName + "-"
 }
@@ -38,22 +45,40 @@ func getJobQueueName(jobKey spec.JobKey) string {
 	return apiQueueNamePrefix(jobKey.APIName) + jobKey.ID + ".fifo"
 }
 
+// DeadLetterQueueName is <hash of cluster name>-<api_name>-<job_id>-dlq.fifo
+func getJobDeadLetterQueueName(jobKey spec.JobKey) string {
+	return apiQueueNamePrefix(jobKey.APIName) + jobKey.ID + _deadLetterQueueSuffix
+}
+
+func isDeadLetterQueueURL(queueURL string) bool {
+	return strings.HasSuffix(queueURL, _deadLetterQueueSuffix)
+}
+
 func getJobQueueURL(jobKey spec.JobKey) (string, error) {
+	return queueURLFromName(getJobQueueName(jobKey))
+}
+
+func getJobDeadLetterQueueURL(jobKey spec.JobKey) (string, error) {
+	return queueURLFromName(getJobDeadLetterQueueName(jobKey))
+}
+
+func queueURLFromName(queueName string) (string, error) {
 	operatorAccountID, _, err := config.AWS.GetCachedAccountID()
 	if err != nil {
 		return "", errors.Wrap(err, "failed to construct queue url", "unable to get account id")
 	}
 
-	return fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", config.AWS.Region, operatorAccountID, getJobQueueName(jobKey)), nil
+	return fmt.Sprintf("https://sqs.%s.amazonaws.com/%s/%s", config.AWS.Region, operatorAccountID, queueName), nil
 }
 
 func jobKeyFromQueueURL(queueURL string) spec.JobKey {
 	split := strings.Split(queueURL, "/")
 	queueName := split[len(split)-1]
+	queueName = strings.TrimSuffix(strings.TrimSuffix(queueName, _deadLetterQueueSuffix), ".fifo")
 
 	dashSplit := strings.Split(queueName, "-")
 
-	jobID := strings.TrimSuffix(dashSplit[len(dashSplit)-1], ".fifo")
+	jobID := dashSplit[len(dashSplit)-1]
 
 	apiNameSplit := dashSplit[1 : len(dashSplit)-1]
 	apiName := strings.Join(apiNameSplit, "-")
@@ -85,17 +110,67 @@ func createFIFOQueue(jobKey spec.JobKey, tags map[string]string) (string, error)
 	return *output.QueueUrl, nil
 }
 
+// failed batches are moved to the dead-letter queue by the workers once they have exhausted their retries
+func createDeadLetterQueue(jobKey spec.JobKey, tags map[string]string) (string, error) {
+	for key, value := range config.Cluster.Tags {
+		tags[key] = value
+	}
+
+	queueName := getJobDeadLetterQueueName(jobKey)
+
+	output, err := config.AWS.SQS().CreateQueue(
+		&sqs.CreateQueueInput{
+			Attributes: map[string]*string{
+				"FifoQueue":              aws.String("true"),
+				"VisibilityTimeout":      aws.String("120"),
+				"MessageRetentionPeriod": aws.String(s.Int64(int64(_deadLetterRetentionPeriod.Seconds()))),
+			},
+			QueueName: aws.String(queueName),
+			Tags:      aws.StringMap(tags),
+		},
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create sqs dead-letter queue", queueName)
+	}
+
+	return *output.QueueUrl, nil
+}
+
 func doesQueueExist(jobKey spec.JobKey) (bool, error) {
 	return config.AWS.DoesQueueExist(getJobQueueName(jobKey))
 }
 
+// excludes dead-letter queues, which outlive their jobs
 func listQueueURLsForAllAPIs() ([]string, error) {
 	queueURLs, err := config.AWS.ListQueuesByQueueNamePrefix(config.Cluster.SQSNamePrefix())
 	if err != nil {
 		return nil, err
 	}
 
-	return queueURLs, nil
+	var jobQueueURLs []string
+	for _, queueURL := range queueURLs {
+		if !isDeadLetterQueueURL(queueURL) {
+			jobQueueURLs = append(jobQueueURLs, queueURL)
+		}
+	}
+
+	return jobQueueURLs, nil
+}
+
+func listDeadLetterQueueURLsForAllAPIs() ([]string, error) {
+	queueURLs, err := config.AWS.ListQueuesByQueueNamePrefix(config.Cluster.SQSNamePrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	var deadLetterQueueURLs []string
+	for _, queueURL := range queueURLs {
+		if isDeadLetterQueueURL(queueURL) {
+			deadLetterQueueURLs = append(deadLetterQueueURLs, queueURL)
+		}
+	}
+
+	return deadLetterQueueURLs, nil
 }
 
 func deleteQueueByJobKey(jobKey spec.JobKey) error {
@@ -126,6 +201,24 @@ func getQueueMetrics(jobKey spec.JobKey) (*metrics.QueueMetrics, error) {
 	return getQueueMetricsFromURL(queueURL)
 }
 
+// returns nil if the job's dead-letter queue has been deleted (or the job was submitted before dead-letter queues were supported)
+func getDeadLetterQueueMetrics(jobKey spec.JobKey) (*metrics.QueueMetrics, error) {
+	queueURL, err := getJobDeadLetterQueueURL(jobKey)
+	if err != nil {
+		return nil, err
+	}
+
+	queueMetrics, err := getQueueMetricsFromURL(queueURL)
+	if err != nil {
+		if awslib.IsErrCode(err, sqs.ErrCodeQueueDoesNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return queueMetrics, nil
+}
+
 func getQueueMetricsFromURL(queueURL string) (*metrics.QueueMetrics, error) {
 	attributes, err := config.AWS.GetAllQueueAttributes(queueURL)
 	if err != nil {
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// lists the result objects written by the workers and writes a manifest next to them
+func writeResultManifest(jobSpec *spec.Job) error {
+	if jobSpec.ResultSink == nil {
+		return nil
+	}
+
+	bucket, prefix, err := awslib.SplitS3Path(jobSpec.ResultSink.JobS3Path(jobSpec.ID))
+	if err != nil {
+		return err
+	}
+
+	_, manifestKey, err := awslib.SplitS3Path(jobSpec.ResultSink.ManifestS3Path(jobSpec.ID))
+	if err != nil {
+		return err
+	}
+
+	results := []string{}
+	err = config.AWS.S3Iterator(bucket, prefix, false, nil, func(s3Obj *s3.Object) (bool, error) {
+		if *s3Obj.Key != manifestKey {
+			results = append(results, awslib.S3Path(bucket, *s3Obj.Key))
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(results)
+
+	manifest := spec.ResultManifest{
+		JobKey:      jobSpec.JobKey,
+		Format:      jobSpec.ResultSink.Format,
+		Results:     results,
+		CreatedTime: time.Now(),
+	}
+
+	return config.AWS.UploadJSONToS3(manifest, bucket, manifestKey)
+}
+
+// returns nil if the job doesn't have a result sink or its manifest hasn't been written yet
+func GetResultManifest(jobStatus *status.JobStatus) (*spec.ResultManifest, error) {
+	if jobStatus.ResultSink == nil || !jobStatus.Status.IsCompleted() {
+		return nil, nil
+	}
+
+	bucket, key, err := awslib.SplitS3Path(jobStatus.ResultSink.ManifestS3Path(jobStatus.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest spec.ResultManifest
+	err = config.AWS.ReadJSONFromS3(&manifest, bucket, key)
+	if err != nil {
+		if awslib.IsNoSuchKeyErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &manifest, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+)
+
+// s3JobStateStore writes an empty file per status to the job's directory in the cluster bucket, and the job's status is inferred from the files which are present.
+// S3 doesn't support conditional writes, so transitions are validated against the most recently listed status.
+type s3JobStateStore struct{}
+
+func (store *s3JobStateStore) GetJobState(jobKey spec.JobKey) (*JobState, error) {
+	s3Objects, err := config.AWS.ListS3Prefix(config.Cluster.Bucket, jobKey.Prefix(), false, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get job state", jobKey.UserString())
+	}
+
+	if len(s3Objects) == 0 {
+		return nil, errors.Wrap(ErrorJobNotFound(jobKey), "failed to get job state")
+	}
+
+	lastUpdatedMap := map[string]time.Time{}
+
+	for _, s3Object := range s3Objects {
+		lastUpdatedMap[filepath.Base(*s3Object.Key)] = *s3Object.LastModified
+	}
+
+	jobState := getJobStateFromFiles(jobKey, lastUpdatedMap)
+	return &jobState, nil
+}
+
+func (store *s3JobStateStore) SetStatus(jobKey spec.JobKey, jobStatus status.JobCode) error {
+	currentStatus, err := currentJobStatus(store, jobKey)
+	if err != nil {
+		return err
+	}
+
+	if err := validateJobStatusTransition(jobKey, currentStatus, jobStatus); err != nil {
+		return err
+	}
+
+	err = config.AWS.UploadStringToS3("", config.Cluster.Bucket, path.Join(jobKey.Prefix(), jobStatus.String()))
+	if err != nil {
+		return err
+	}
+
+	if jobStatus.IsInProgress() {
+		return uploadInProgressFile(jobKey) // the in progress file may already be there
+	}
+
+	return deleteInProgressFile(jobKey)
+}
+
+func (store *s3JobStateStore) UpdateLiveness(jobKey spec.JobKey) error {
+	s3Key := path.Join(jobKey.Prefix(), _enqueuingLivenessFile)
+	err := config.AWS.UploadJSONToS3(time.Now(), config.Cluster.Bucket, s3Key)
+	if err != nil {
+		return errors.Wrap(err, "failed to update liveness", jobKey.UserString())
+	}
+	return nil
+}
+
+func (store *s3JobStateStore) ListInProgressJobKeys() ([]spec.JobKey, error) {
+	return listAllInProgressJobKeys()
+}
+
+func (store *s3JobStateStore) ListInProgressJobKeysByAPI(apiName string) ([]spec.JobKey, error) {
+	return listAllInProgressJobKeysByAPI(apiName)
+}
+
+func (store *s3JobStateStore) DeleteInProgressJobKey(jobKey spec.JobKey) error {
+	return deleteInProgressFile(jobKey)
+}
+
+func (store *s3JobStateStore) IterateJobStates(apiName string, startAfterJobID string, fn func(*JobState) (bool, error)) error {
+	prefix := s.EnsureSuffix(spec.BatchAPIJobPrefix(apiName), "/")
+
+	startAfter := ""
+	if startAfterJobID != "" {
+		// "0" sorts after "/", so all of the files of startAfterJobID are skipped (job ids have a fixed length)
+		startAfter = prefix + startAfterJobID + "0"
+	}
+
+	var jobID string
+	var lastUpdatedMap map[string]time.Time
+	stopped := false
+
+	callFn := func() (bool, error) {
+		if jobID == "" {
+			return true, nil
+		}
+		jobState := getJobStateFromFiles(spec.JobKey{APIName: apiName, ID: jobID}, lastUpdatedMap)
+		shouldContinue, err := fn(&jobState)
+		stopped = !shouldContinue || err != nil
+		return shouldContinue, err
+	}
+
+	// job ids are monotonically decreasing, so the files of the most recently submitted jobs are listed first
+	err := config.AWS.S3IteratorStartAfter(config.Cluster.Bucket, prefix, startAfter, false, nil, func(s3Object *s3.Object) (bool, error) {
+		fileName := filepath.Base(*s3Object.Key)
+		objectJobID := filepath.Base(filepath.Dir(*s3Object.Key))
+
+		if objectJobID != jobID {
+			// all of the files of the previous job have been listed
+			if shouldContinue, err := callFn(); !shouldContinue || err != nil {
+				return false, err
+			}
+			jobID = objectJobID
+			lastUpdatedMap = map[string]time.Time{}
+		}
+
+		lastUpdatedMap[fileName] = *s3Object.LastModified
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !stopped {
+		_, err = callFn()
+		return err
+	}
+
+	return nil
+}
+
+// the status files are deleted along with the rest of the api's job files
+func (store *s3JobStateStore) DeleteJobStatesByAPI(apiName string) error {
+	return deleteAllInProgressFilesByAPI(apiName)
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/consts"
+	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/sets/strset"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/lib/telemetry"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+const (
+	ManageScheduledJobsCronPeriod = 20 * time.Second
+
+	// if a scheduled run is missed (e.g. the operator was unavailable), it is only submitted late if it was missed by less than this
+	_scheduledJobStartingDeadline = time.Hour
+	_maxScheduledJobHistoryLength = 20
+)
+
+// serializes updates to scheduled jobs between the api and the cron
+var _scheduledJobsMutex sync.Mutex
+
+// e.g. scheduled_jobs/<cortex version>/<api_name>/
+func scheduledJobsPrefix(apiName string) string {
+	return s.EnsureSuffix(path.Join("scheduled_jobs", consts.CortexVersion, apiName), "/")
+}
+
+// e.g. scheduled_jobs/<cortex version>/<api_name>/<name>.json
+func scheduledJobKey(apiName string, name string) string {
+	return path.Join(scheduledJobsPrefix(apiName), name+".json")
+}
+
+func CreateScheduledJob(apiName string, submission *schema.ScheduledJobSubmission) (*schema.ScheduledJob, error) {
+	concurrencyPolicy, err := validateScheduledJobSubmission(submission)
+	if err != nil {
+		return nil, err
+	}
+
+	_scheduledJobsMutex.Lock()
+	defer _scheduledJobsMutex.Unlock()
+
+	scheduledJob := &schema.ScheduledJob{
+		Name:        submission.Name,
+		APIName:     apiName,
+		CreatedTime: time.Now(),
+	}
+
+	// updating a scheduled job preserves its state and history
+	prevScheduledJob, err := getScheduledJob(apiName, submission.Name)
+	if err != nil {
+		return nil, err
+	}
+	if prevScheduledJob != nil {
+		scheduledJob = prevScheduledJob
+	}
+
+	scheduledJob.Schedule = submission.Schedule
+	scheduledJob.ConcurrencyPolicy = concurrencyPolicy
+	scheduledJob.JobSubmission = submission.JobSubmission
+
+	if err := uploadScheduledJob(scheduledJob); err != nil {
+		return nil, err
+	}
+
+	return scheduledJob, nil
+}
+
+func GetScheduledJob(apiName string, name string) (*schema.ScheduledJob, error) {
+	scheduledJob, err := getScheduledJob(apiName, name)
+	if err != nil {
+		return nil, err
+	}
+	if scheduledJob == nil {
+		return nil, ErrorScheduledJobNotFound(apiName, name)
+	}
+	return scheduledJob, nil
+}
+
+// sorted by name
+func GetScheduledJobs(apiName string) ([]schema.ScheduledJob, error) {
+	return listScheduledJobs(scheduledJobsPrefix(apiName))
+}
+
+func PauseScheduledJob(apiName string, name string) (*schema.ScheduledJob, error) {
+	return setScheduledJobPaused(apiName, name, true)
+}
+
+func ResumeScheduledJob(apiName string, name string) (*schema.ScheduledJob, error) {
+	return setScheduledJobPaused(apiName, name, false)
+}
+
+func setScheduledJobPaused(apiName string, name string, paused bool) (*schema.ScheduledJob, error) {
+	_scheduledJobsMutex.Lock()
+	defer _scheduledJobsMutex.Unlock()
+
+	scheduledJob, err := GetScheduledJob(apiName, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduledJob.Paused == paused {
+		return scheduledJob, nil
+	}
+
+	scheduledJob.Paused = paused
+	if err := uploadScheduledJob(scheduledJob); err != nil {
+		return nil, err
+	}
+
+	return scheduledJob, nil
+}
+
+func DeleteScheduledJob(apiName string, name string) error {
+	_scheduledJobsMutex.Lock()
+	defer _scheduledJobsMutex.Unlock()
+
+	if _, err := GetScheduledJob(apiName, name); err != nil {
+		return err
+	}
+
+	return config.AWS.DeleteS3File(config.Cluster.Bucket, scheduledJobKey(apiName, name))
+}
+
+func deleteAllScheduledJobs(apiName string) error {
+	_scheduledJobsMutex.Lock()
+	defer _scheduledJobsMutex.Unlock()
+
+	return config.AWS.DeleteS3Prefix(config.Cluster.Bucket, scheduledJobsPrefix(apiName), true)
+}
+
+// returns nil if the scheduled job doesn't exist
+func getScheduledJob(apiName string, name string) (*schema.ScheduledJob, error) {
+	scheduledJob := schema.ScheduledJob{}
+	err := config.AWS.ReadJSONFromS3(&scheduledJob, config.Cluster.Bucket, scheduledJobKey(apiName, name))
+	if err != nil {
+		if awslib.IsNoSuchKeyErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &scheduledJob, nil
+}
+
+func uploadScheduledJob(scheduledJob *schema.ScheduledJob) error {
+	return config.AWS.UploadJSONToS3(scheduledJob, config.Cluster.Bucket, scheduledJobKey(scheduledJob.APIName, scheduledJob.Name))
+}
+
+func listScheduledJobs(prefix string) ([]schema.ScheduledJob, error) {
+	s3Objects, err := config.AWS.ListS3Prefix(config.Cluster.Bucket, prefix, false, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledJobs := make([]schema.ScheduledJob, 0, len(s3Objects))
+	for _, s3Obj := range s3Objects {
+		scheduledJob := schema.ScheduledJob{}
+		err := config.AWS.ReadJSONFromS3(&scheduledJob, config.Cluster.Bucket, *s3Obj.Key)
+		if err != nil {
+			if awslib.IsNoSuchKeyErr(err) {
+				continue // deleted after it was listed
+			}
+			return nil, err
+		}
+		scheduledJobs = append(scheduledJobs, scheduledJob)
+	}
+
+	sort.Slice(scheduledJobs, func(i, j int) bool {
+		if scheduledJobs[i].APIName != scheduledJobs[j].APIName {
+			return scheduledJobs[i].APIName < scheduledJobs[j].APIName
+		}
+		return scheduledJobs[i].Name < scheduledJobs[j].Name
+	})
+
+	return scheduledJobs, nil
+}
+
+func ManageScheduledJobs() error {
+	_scheduledJobsMutex.Lock()
+	defer _scheduledJobsMutex.Unlock()
+
+	scheduledJobs, err := listScheduledJobs(scheduledJobsPrefix(""))
+	if err != nil {
+		return err
+	}
+
+	if len(scheduledJobs) == 0 {
+		return nil
+	}
+
+	inProgressJobKeys, err := _jobStateStore.ListInProgressJobKeys()
+	if err != nil {
+		return err
+	}
+
+	inProgressJobIDSet := strset.Set{}
+	for _, jobKey := range inProgressJobKeys {
+		inProgressJobIDSet.Add(jobKey.ID)
+	}
+
+	now := time.Now()
+	for i := range scheduledJobs {
+		err := triggerScheduledJob(&scheduledJobs[i], now, inProgressJobIDSet)
+		if err != nil {
+			telemetry.Error(err)
+			errors.PrintError(err)
+		}
+	}
+
+	return nil
+}
+
+// submits a job if the scheduled job has been triggered since it was last triggered (or created)
+func triggerScheduledJob(scheduledJob *schema.ScheduledJob, now time.Time, inProgressJobIDSet strset.Set) error {
+	cronSchedule, err := cron.ParseSchedule(scheduledJob.Schedule)
+	if err != nil {
+		return err
+	}
+
+	activation := dueActivation(cronSchedule, scheduledJob, now)
+	if activation == nil {
+		return nil
+	}
+
+	scheduledJob.LastScheduleTime = activation
+
+	// runs which are due while the scheduled job is paused are skipped
+	if scheduledJob.Paused {
+		return uploadScheduledJob(scheduledJob)
+	}
+
+	run := submitScheduledJob(scheduledJob, *activation, inProgressJobIDSet)
+
+	scheduledJob.History = append(scheduledJob.History, run)
+	if len(scheduledJob.History) > _maxScheduledJobHistoryLength {
+		scheduledJob.History = scheduledJob.History[len(scheduledJob.History)-_maxScheduledJobHistoryLength:]
+	}
+
+	return uploadScheduledJob(scheduledJob)
+}
+
+// returns the most recent activation of the schedule since the scheduled job was last triggered (or created), if there is one within the starting deadline
+func dueActivation(cronSchedule *cron.Schedule, scheduledJob *schema.ScheduledJob, now time.Time) *time.Time {
+	since := scheduledJob.CreatedTime
+	if scheduledJob.LastScheduleTime != nil && scheduledJob.LastScheduleTime.After(since) {
+		since = *scheduledJob.LastScheduleTime
+	}
+
+	lookback := now.Sub(since)
+	if lookback > _scheduledJobStartingDeadline {
+		lookback = _scheduledJobStartingDeadline
+	}
+
+	activation := cronSchedule.LastActivation(now, lookback)
+	if activation == nil || !activation.After(since) {
+		return nil
+	}
+
+	return activation
+}
+
+func submitScheduledJob(scheduledJob *schema.ScheduledJob, activation time.Time, inProgressJobIDSet strset.Set) schema.ScheduledJobRun {
+	run := schema.ScheduledJobRun{ScheduledTime: activation}
+
+	var inProgressJobIDs []string
+	for _, prevRun := range scheduledJob.History {
+		if prevRun.JobID != "" && inProgressJobIDSet.Has(prevRun.JobID) {
+			inProgressJobIDs = append(inProgressJobIDs, prevRun.JobID)
+		}
+	}
+
+	if len(inProgressJobIDs) > 0 {
+		switch scheduledJob.ConcurrencyPolicy {
+		case schema.ForbidConcurrencyPolicy:
+			run.Message = fmt.Sprintf("skipped because %s %s still in progress", s.StrsAnd(inProgressJobIDs), s.PluralCustom("is", "are", len(inProgressJobIDs)))
+			return run
+		case schema.ReplaceConcurrencyPolicy:
+			for _, jobID := range inProgressJobIDs {
+				jobKey := spec.JobKey{APIName: scheduledJob.APIName, ID: jobID}
+				if err := StopJob(jobKey); err != nil {
+					run.Message = fmt.Sprintf("skipped because %s could not be stopped: %s", jobID, errors.Message(err))
+					return run
+				}
+			}
+			run.Message = fmt.Sprintf("replaced %s", s.StrsAnd(inProgressJobIDs))
+		}
+	}
+
+	// copied since SubmitJob continues to read the submission after it returns
+	submission := scheduledJob.JobSubmission
+	jobSpec, err := SubmitJob(scheduledJob.APIName, &submission)
+	if err != nil {
+		submitErrMessage := "failed to submit job: " + errors.Message(err)
+		if run.Message != "" {
+			submitErrMessage = run.Message + "; " + submitErrMessage
+		}
+		run.Message = submitErrMessage
+		return run
+	}
+
+	run.JobID = jobSpec.ID
+	writeToJobLogStream(jobSpec.JobKey, fmt.Sprintf("submitted by scheduled job %s (scheduled for %s)", scheduledJob.Name, activation.Format(time.RFC3339)))
+
+	return run
+}
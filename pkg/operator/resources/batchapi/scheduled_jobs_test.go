@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueActivation(t *testing.T) {
+	cronSchedule, err := cron.ParseSchedule("0 2 * * *")
+	require.NoError(t, err)
+
+	scheduledJob := &schema.ScheduledJob{
+		CreatedTime: time.Date(2020, 10, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	// not due until the first activation after the scheduled job was created
+	require.Nil(t, dueActivation(cronSchedule, scheduledJob, time.Date(2020, 10, 2, 1, 59, 0, 0, time.UTC)))
+
+	activation := dueActivation(cronSchedule, scheduledJob, time.Date(2020, 10, 2, 2, 0, 10, 0, time.UTC))
+	require.Equal(t, time.Date(2020, 10, 2, 2, 0, 0, 0, time.UTC), *activation)
+
+	// an activation is only due once
+	scheduledJob.LastScheduleTime = activation
+	require.Nil(t, dueActivation(cronSchedule, scheduledJob, time.Date(2020, 10, 2, 2, 0, 30, 0, time.UTC)))
+
+	// missed activations are only due within the starting deadline
+	require.Equal(t, time.Date(2020, 10, 3, 2, 0, 0, 0, time.UTC), *dueActivation(cronSchedule, scheduledJob, time.Date(2020, 10, 3, 2, 45, 0, 0, time.UTC)))
+	require.Nil(t, dueActivation(cronSchedule, scheduledJob, time.Date(2020, 10, 3, 3, 30, 0, 0, time.UTC)))
+}
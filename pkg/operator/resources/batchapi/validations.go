@@ -23,11 +23,17 @@ import (
 	"github.com/cortexlabs/cortex/pkg/consts"
 	awslib "github.com/cortexlabs/cortex/pkg/lib/aws"
 	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/urls"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
 	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
 	"github.com/gobwas/glob"
 )
 
+const _maxRetriesLimit = 100
+
 func validateJobSubmissionSchema(submission *schema.JobSubmission) error {
 	providedKeys := []string{}
 	if submission.ItemList != nil {
@@ -74,21 +80,85 @@ func validateJobSubmissionSchema(submission *schema.JobSubmission) error {
 		if submission.DelimitedFiles.BatchSize < 1 {
 			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.DelimitedFiles.BatchSize, 1), schema.DelimitedFilesKey, schema.BatchSizeKey)
 		}
+
+		format := submission.DelimitedFiles.GetFormat()
+		if format == schema.UnknownDelimitedFilesFormat {
+			return errors.Wrap(ErrorInvalidDelimitedFilesFormat(submission.DelimitedFiles.Format), schema.DelimitedFilesKey, schema.FormatKey)
+		}
+
+		if submission.DelimitedFiles.Header != nil && format != schema.CSVDelimitedFilesFormat {
+			return errors.Wrap(ErrorFieldNotSupportedForFormat(schema.HeaderKey, format), schema.DelimitedFilesKey)
+		}
+	}
+
+	if err := validateWorkers(submission.RuntimeJobConfig); err != nil {
+		return err
+	}
+
+	if submission.MaxRetries < 0 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.MaxRetries, 0), schema.MaxRetriesKey)
+	}
+
+	if submission.MaxRetries > _maxRetriesLimit {
+		return errors.Wrap(cr.ErrorMustBeLessThanOrEqualTo(submission.MaxRetries, _maxRetriesLimit), schema.MaxRetriesKey)
+	}
+
+	if submission.ResultSink != nil {
+		if !awslib.IsValidS3Path(submission.ResultSink.S3Path) {
+			return errors.Wrap(awslib.ErrorInvalidS3Path(submission.ResultSink.S3Path), schema.ResultSinkKey, schema.S3PathKey)
+		}
+
+		if submission.ResultSink.Format == spec.UnknownResultFormat {
+			return errors.Wrap(ErrorInvalidResultFormat(), schema.ResultSinkKey, schema.FormatKey)
+		}
 	}
 
-	if submission.Workers <= 0 {
-		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(submission.Workers, 1), schema.WorkersKey)
+	return nil
+}
+
+func validateWorkers(runtimeConfig spec.RuntimeJobConfig) error {
+	if runtimeConfig.MaxWorkers == 0 {
+		if runtimeConfig.Workers <= 0 {
+			return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(runtimeConfig.Workers, 1), schema.WorkersKey)
+		}
+		return nil
+	}
+
+	// 0 indicates that workers is unset, in which case it's defaulted below
+	if runtimeConfig.Workers < 0 {
+		return errors.Wrap(cr.ErrorMustBeGreaterThanOrEqualTo(runtimeConfig.Workers, 0), schema.WorkersKey)
+	}
+
+	runtimeConfig = runtimeJobConfigWithDefaults(runtimeConfig)
+
+	if runtimeConfig.Workers > runtimeConfig.MaxWorkers {
+		return errors.Wrap(cr.ErrorMustBeLessThanOrEqualTo(runtimeConfig.Workers, runtimeConfig.MaxWorkers), schema.WorkersKey)
 	}
 
 	return nil
 }
 
+// when max_workers is specified, workers defaults to 1
+func runtimeJobConfigWithDefaults(runtimeConfig spec.RuntimeJobConfig) spec.RuntimeJobConfig {
+	if runtimeConfig.MaxWorkers != 0 && runtimeConfig.Workers == 0 {
+		runtimeConfig.Workers = 1
+	}
+	return runtimeConfig
+}
+
 func validateJobSubmission(submission *schema.JobSubmission) error {
 	err := validateJobSubmissionSchema(submission)
 	if err != nil {
 		return errors.Append(err, fmt.Sprintf("\n\njob submission schema can be found at https://docs.cortex.dev/v/%s/deployments/batchapi/endpoints", consts.CortexVersionMinor))
 	}
 
+	if config.Cluster.BatchMaxConcurrentWorkers != nil {
+		workers := runtimeJobConfigWithDefaults(submission.RuntimeJobConfig).Workers
+		if workers > *config.Cluster.BatchMaxConcurrentWorkers {
+			return errors.Wrap(ErrorWorkersExceedClusterQuota(workers, *config.Cluster.BatchMaxConcurrentWorkers), schema.WorkersKey)
+		}
+	}
+
 	if submission.FilePathLister != nil {
 		err := validateS3Lister(&submission.FilePathLister.S3Lister)
 		if err != nil {
@@ -103,6 +173,21 @@ func validateJobSubmission(submission *schema.JobSubmission) error {
 		}
 	}
 
+	if submission.ResultSink != nil {
+		bucket, _, err := awslib.SplitS3Path(submission.ResultSink.S3Path)
+		if err != nil {
+			return errors.Wrap(err, schema.ResultSinkKey, schema.S3PathKey)
+		}
+
+		exists, err := config.AWS.DoesBucketExist(bucket)
+		if err != nil {
+			return errors.Wrap(err, schema.ResultSinkKey, schema.S3PathKey)
+		}
+		if !exists {
+			return errors.Wrap(awslib.ErrorBucketNotFound(bucket), schema.ResultSinkKey, schema.S3PathKey)
+		}
+	}
+
 	return nil
 }
 
@@ -170,3 +255,32 @@ func listFilesDryRun(s3Lister *schema.S3Lister) ([]string, error) {
 
 	return s3Files, nil
 }
+
+// returns the parsed concurrency policy (allow if unspecified)
+func validateScheduledJobSubmission(submission *schema.ScheduledJobSubmission) (schema.ConcurrencyPolicy, error) {
+	if submission.Name == "" {
+		return schema.UnknownConcurrencyPolicy, errors.Wrap(cr.ErrorMustBeDefined(), schema.NameKey)
+	}
+
+	if err := urls.CheckDNS1123(submission.Name); err != nil {
+		return schema.UnknownConcurrencyPolicy, errors.Wrap(err, schema.NameKey)
+	}
+
+	if _, err := cron.ParseSchedule(submission.Schedule); err != nil {
+		return schema.UnknownConcurrencyPolicy, errors.Wrap(err, schema.ScheduleKey)
+	}
+
+	concurrencyPolicy := schema.AllowConcurrencyPolicy
+	if submission.ConcurrencyPolicy != "" {
+		concurrencyPolicy = schema.ConcurrencyPolicyFromString(submission.ConcurrencyPolicy)
+		if concurrencyPolicy == schema.UnknownConcurrencyPolicy {
+			return schema.UnknownConcurrencyPolicy, errors.Wrap(ErrorInvalidConcurrencyPolicy(submission.ConcurrencyPolicy), schema.ConcurrencyPolicyKey)
+		}
+	}
+
+	if err := validateJobSubmission(&submission.JobSubmission); err != nil {
+		return schema.UnknownConcurrencyPolicy, errors.Wrap(err, schema.JobSubmissionKey)
+	}
+
+	return concurrencyPolicy, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	kbatch "k8s.io/api/batch/v1"
+)
+
+const (
+	// workers are added so that the batches in the queue would be completed within this duration
+	_workerAutoscalingTargetDuration = 10 * time.Minute
+)
+
+// workers are only added while a job is running; removing workers would interrupt the batches that they are processing (workers exit on their own once the queue is empty)
+// quotaUsage is computed (if the cluster has a job quota) the first time that it's needed, and is shared between the jobs which are autoscaled in the same cron iteration
+func autoscaleJobWorkers(jobKey spec.JobKey, queueURL string, k8sJob *kbatch.Job, quotaUsage **jobQuotaUsage) error {
+	if !canAddJobWorkers(k8sJob) {
+		return nil
+	}
+
+	jobSpec, err := downloadJobSpec(jobKey)
+	if err != nil {
+		return err
+	}
+
+	if !jobSpec.IsAutoscaled() || jobSpec.Workers >= jobSpec.MaxWorkers {
+		return nil
+	}
+
+	queueMetrics, err := getQueueMetricsFromURL(queueURL)
+	if err != nil {
+		return err
+	}
+
+	if queueMetrics.Visible == 0 {
+		return nil
+	}
+
+	batchMetrics, err := getRealTimeBatchMetrics(jobKey)
+	if err != nil {
+		return err
+	}
+
+	maxWorkers := jobSpec.MaxWorkers
+	if isJobQuotaEnabled() {
+		if *quotaUsage == nil {
+			*quotaUsage, err = getJobQuotaUsage()
+			if err != nil {
+				return err
+			}
+		}
+		if availableWorkers := (*quotaUsage).AvailableWorkers(); availableWorkers != nil {
+			maxWorkers = libmath.MinInt(maxWorkers, jobSpec.Workers+*availableWorkers)
+		}
+	}
+
+	workers := desiredWorkerCount(queueMetrics.TotalInQueue(), batchMetrics.AverageTimePerBatch, jobSpec.Workers, maxWorkers)
+	if workers <= jobSpec.Workers {
+		return nil
+	}
+
+	k8sJob.Spec.Parallelism = pointer.Int32(int32(workers))
+	if _, err := config.K8s.UpdateJob(k8sJob); err != nil {
+		return err
+	}
+
+	previousWorkers := jobSpec.Workers
+	jobSpec.Workers = workers
+	if err := uploadJobSpec(jobSpec); err != nil {
+		return err
+	}
+
+	if *quotaUsage != nil {
+		(*quotaUsage).Workers += workers - previousWorkers
+	}
+
+	return writeToJobLogStream(jobKey, fmt.Sprintf("increased the number of workers from %d to %d (%d batches remaining in the queue)", previousWorkers, workers, queueMetrics.TotalInQueue()))
+}
+
+// the k8s job doesn't specify completions, so once any worker has succeeded (i.e. it found the queue empty, which can happen
+// while failed batches are waiting to be retried), the job is considered to be finishing and increasing its parallelism starts no new pods
+func canAddJobWorkers(k8sJob *kbatch.Job) bool {
+	return k8sJob.Status.Succeeded == 0
+}
+
+func desiredWorkerCount(batchesInQueue int, avgTimePerBatch *float64, currentWorkers int, maxWorkers int) int {
+	// the time per batch is unknown until batches have been completed
+	if avgTimePerBatch == nil || batchesInQueue == 0 {
+		return currentWorkers
+	}
+
+	remainingWork := float64(batchesInQueue) * *avgTimePerBatch
+	workers := int(math.Ceil(remainingWork / _workerAutoscalingTargetDuration.Seconds()))
+
+	if workers < currentWorkers {
+		return currentWorkers
+	}
+	if workers > maxWorkers {
+		return maxWorkers
+	}
+	return workers
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchapi
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/stretchr/testify/require"
+	kbatch "k8s.io/api/batch/v1"
+)
+
+func TestDesiredWorkerCount(t *testing.T) {
+	// 600 batches * 5 seconds per batch = 3000 seconds of work, which 5 workers would complete in 10 minutes
+	require.Equal(t, 5, desiredWorkerCount(600, pointer.Float64(5), 1, 10))
+	require.Equal(t, 6, desiredWorkerCount(601, pointer.Float64(5), 1, 10))
+	require.Equal(t, 10, desiredWorkerCount(6000, pointer.Float64(5), 1, 10))
+
+	// workers are never removed
+	require.Equal(t, 4, desiredWorkerCount(10, pointer.Float64(5), 4, 10))
+
+	require.Equal(t, 2, desiredWorkerCount(600, nil, 2, 10))
+	require.Equal(t, 2, desiredWorkerCount(0, pointer.Float64(5), 2, 10))
+}
+
+func TestCanAddJobWorkers(t *testing.T) {
+	require.True(t, canAddJobWorkers(&kbatch.Job{Status: kbatch.JobStatus{Active: 2}}))
+	require.True(t, canAddJobWorkers(&kbatch.Job{Status: kbatch.JobStatus{Active: 1, Failed: 1}}))
+	require.False(t, canAddJobWorkers(&kbatch.Job{Status: kbatch.JobStatus{Active: 1, Succeeded: 1}}))
+}
+
+func TestValidateWorkers(t *testing.T) {
+	require.NoError(t, validateWorkers(spec.RuntimeJobConfig{Workers: 2}))
+	require.NoError(t, validateWorkers(spec.RuntimeJobConfig{MaxWorkers: 4}))
+	require.NoError(t, validateWorkers(spec.RuntimeJobConfig{Workers: 2, MaxWorkers: 4}))
+
+	require.Error(t, validateWorkers(spec.RuntimeJobConfig{}))
+	require.Error(t, validateWorkers(spec.RuntimeJobConfig{Workers: -1, MaxWorkers: 4}))
+	require.Error(t, validateWorkers(spec.RuntimeJobConfig{Workers: 5, MaxWorkers: 4}))
+
+	require.Equal(t, spec.RuntimeJobConfig{Workers: 1, MaxWorkers: 4}, runtimeJobConfigWithDefaults(spec.RuntimeJobConfig{MaxWorkers: 4}))
+	require.Equal(t, spec.RuntimeJobConfig{Workers: 2, MaxWorkers: 4}, runtimeJobConfigWithDefaults(spec.RuntimeJobConfig{Workers: 2, MaxWorkers: 4}))
+	require.Equal(t, spec.RuntimeJobConfig{Workers: 2}, runtimeJobConfigWithDefaults(spec.RuntimeJobConfig{Workers: 2}))
+}
@@ -19,6 +19,7 @@ package resources
 import (
 	"fmt"
 
+	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/strings"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
@@ -27,14 +28,17 @@ import (
 )
 
 const (
-	ErrOperationIsOnlySupportedForKind = "resources.operation_is_only_supported_for_kind"
-	ErrAPINotDeployed                  = "resources.api_not_deployed"
-	ErrCannotChangeTypeOfDeployedAPI   = "resources.cannot_change_kind_of_deployed_api"
-	ErrNoAvailableNodeComputeLimit     = "resources.no_available_node_compute_limit"
-	ErrJobIDRequired                   = "resources.job_id_required"
-	ErrAPIUsedByAPISplitter            = "resources.syncapi_used_by_apisplitter"
-	ErrNotDeployedAPIsAPISplitter      = "resources.trafficsplit_apis_not_deployed"
-	ErrAPIGatewayDisabled              = "resources.api_gateway_disabled"
+	ErrOperationIsOnlySupportedForKind      = "resources.operation_is_only_supported_for_kind"
+	ErrAPINotDeployed                       = "resources.api_not_deployed"
+	ErrCannotChangeTypeOfDeployedAPI        = "resources.cannot_change_kind_of_deployed_api"
+	ErrNoAvailableNodeComputeLimit          = "resources.no_available_node_compute_limit"
+	ErrJobIDRequired                        = "resources.job_id_required"
+	ErrAPIUsedByAPISplitter                 = "resources.syncapi_used_by_apisplitter"
+	ErrNotDeployedAPIsAPISplitter           = "resources.trafficsplit_apis_not_deployed"
+	ErrAPIGatewayDisabled                   = "resources.api_gateway_disabled"
+	ErrNoPreviousAPIVersion                 = "resources.no_previous_api_version"
+	ErrAPIVersionNotFound                   = "resources.api_version_not_found"
+	ErrAPIVersionFromDifferentCortexVersion = "resources.api_version_from_different_cortex_version"
 )
 
 func ErrorOperationIsOnlySupportedForKind(resource operator.DeployedResource, supportedKind userconfig.Kind, supportedKinds ...userconfig.Kind) error {
@@ -101,3 +105,24 @@ func ErrorAPIGatewayDisabled(apiGatewayType userconfig.APIGatewayType) error {
 		Message: msg,
 	})
 }
+
+func ErrorNoPreviousAPIVersion(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrNoPreviousAPIVersion,
+		Message: fmt.Sprintf("%s does not have a previous version to roll back to (run `cortex history %s` to see its versions)", apiName, apiName),
+	})
+}
+
+func ErrorAPIVersionNotFound(apiName string, apiID string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIVersionNotFound,
+		Message: fmt.Sprintf("version %s of %s was not found in its history (run `cortex history %s` to see its versions)", apiID, apiName, apiName),
+	})
+}
+
+func ErrorAPIVersionFromDifferentCortexVersion(apiName string, apiID string, cortexVersion string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPIVersionFromDifferentCortexVersion,
+		Message: fmt.Sprintf("version %s of %s was deployed with cortex v%s and cannot be rolled back to with cortex v%s; please redeploy it with `cortex deploy` instead", apiID, apiName, cortexVersion, consts.CortexVersion),
+	})
+}
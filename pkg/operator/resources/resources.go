@@ -18,6 +18,8 @@ package resources
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -73,7 +75,7 @@ func GetDeployedResourceByNameOrNil(resourceName string) (*operator.DeployedReso
 	}, nil
 }
 
-func Deploy(projectBytes []byte, configFileName string, configBytes []byte, force bool) (*schema.DeployResponse, error) {
+func Deploy(projectBytes []byte, configFileName string, configBytes []byte, force bool, dryRun bool) (*schema.DeployResponse, error) {
 	projectID := hash.Bytes(projectBytes)
 	projectKey := spec.ProjectKey(projectID)
 	projectFileMap, err := zip.UnzipMemToMem(projectBytes)
@@ -97,6 +99,10 @@ func Deploy(projectBytes []byte, configFileName string, configBytes []byte, forc
 		return nil, err
 	}
 
+	if dryRun {
+		return dryRunDeploy(apiConfigs, projectID)
+	}
+
 	isProjectUploaded, err := config.AWS.IsS3File(config.Cluster.Bucket, projectKey)
 	if err != nil {
 		return nil, err
@@ -126,6 +132,69 @@ func Deploy(projectBytes []byte, configFileName string, configBytes []byte, forc
 	}, nil
 }
 
+// validates the deployment without uploading the project or applying any changes
+func dryRunDeploy(apiConfigs []userconfig.API, projectID string) (*schema.DeployResponse, error) {
+	results := make([]schema.DeployResult, len(apiConfigs))
+	for i, apiConfig := range apiConfigs {
+		api, plan, msg, err := dryRunUpdateAPI(&apiConfig, projectID)
+		results[i].Message = msg
+		if err != nil {
+			results[i].Error = errors.Message(err)
+		} else {
+			results[i].API = *api
+			results[i].Plan = plan
+		}
+	}
+
+	return &schema.DeployResponse{
+		Results: results,
+	}, nil
+}
+
+func dryRunUpdateAPI(apiConfig *userconfig.API, projectID string) (*spec.API, *schema.DeployPlan, string, error) {
+	deployedResource, err := GetDeployedResourceByNameOrNil(apiConfig.Name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if deployedResource != nil && deployedResource.Kind != apiConfig.Kind {
+		return nil, nil, "", ErrorCannotChangeKindOfDeployedAPI(apiConfig.Name, apiConfig.Kind, deployedResource.Kind)
+	}
+
+	var api *spec.API
+	var prevAPI *spec.API
+	var isChanged bool
+
+	switch apiConfig.Kind {
+	case userconfig.SyncAPIKind:
+		api, prevAPI, isChanged, err = syncapi.DryRunUpdateAPI(apiConfig, projectID)
+	case userconfig.BatchAPIKind:
+		api, prevAPI, isChanged, err = batchapi.DryRunUpdateAPI(apiConfig, projectID)
+	case userconfig.APISplitterKind:
+		api, prevAPI, isChanged, err = apisplitter.DryRunUpdateAPI(apiConfig, projectID)
+	default:
+		return nil, nil, "", ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.SyncAPIKind, userconfig.BatchAPIKind, userconfig.APISplitterKind) // unexpected
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if prevAPI == nil {
+		return api, &schema.DeployPlan{Action: schema.CreateDeployAction}, fmt.Sprintf("%s would be created", api.Resource.UserString()), nil
+	}
+
+	if !isChanged {
+		return api, &schema.DeployPlan{Action: schema.UnchangedDeployAction}, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
+	}
+
+	diff := spec.DiffAPIConfigs(prevAPI.API, api.API)
+	if prevAPI.ProjectID != api.ProjectID {
+		diff = append(diff, spec.FieldDiff{Field: "project files", Prev: prevAPI.ProjectID, New: api.ProjectID})
+	}
+
+	return api, &schema.DeployPlan{Action: schema.UpdateDeployAction, Diff: diff}, fmt.Sprintf("%s would be updated", api.Resource.UserString()), nil
+}
+
 func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.API, string, error) {
 	deployedResource, err := GetDeployedResourceByNameOrNil(apiConfig.Name)
 	if err != nil {
@@ -136,16 +205,109 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 		return nil, "", ErrorCannotChangeKindOfDeployedAPI(apiConfig.Name, apiConfig.Kind, deployedResource.Kind)
 	}
 
+	var api *spec.API
+	var msg string
+
 	switch apiConfig.Kind {
 	case userconfig.SyncAPIKind:
-		return syncapi.UpdateAPI(apiConfig, projectID, force)
+		api, msg, err = syncapi.UpdateAPI(apiConfig, projectID, force)
 	case userconfig.BatchAPIKind:
-		return batchapi.UpdateAPI(apiConfig, projectID)
+		api, msg, err = batchapi.UpdateAPI(apiConfig, projectID)
 	case userconfig.APISplitterKind:
-		return apisplitter.UpdateAPI(apiConfig, projectID, force)
+		api, msg, err = apisplitter.UpdateAPI(apiConfig, projectID, force)
 	default:
 		return nil, "", ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.SyncAPIKind, userconfig.BatchAPIKind, userconfig.APISplitterKind) // unexpected
 	}
+
+	if err != nil {
+		return nil, msg, err
+	}
+
+	// the api has been deployed, so failing to record it in the api's history shouldn't fail the deployment
+	if err := operator.RecordAPIVersion(api); err != nil {
+		errors.PrintError(err)
+	}
+
+	return api, msg, nil
+}
+
+func GetAPIHistory(apiName string) (*schema.GetHistoryResponse, error) {
+	history, err := operator.GetAPIHistory(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, ErrorAPINotDeployed(apiName)
+	}
+
+	return &schema.GetHistoryResponse{
+		APIName: apiName,
+		History: history,
+	}, nil
+}
+
+// RollbackAPI redeploys a version from the API's history (the version before the current one if apiID is empty)
+func RollbackAPI(apiName string, apiID string, force bool) (string, error) {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return "", err
+	}
+
+	history, err := operator.GetAPIHistory(apiName)
+	if err != nil {
+		return "", err
+	}
+
+	version, err := findVersionToRollBackTo(apiName, history, apiID)
+	if err != nil {
+		return "", err
+	}
+
+	if version.CortexVersion != consts.CortexVersion {
+		return "", ErrorAPIVersionFromDifferentCortexVersion(apiName, version.APIID, version.CortexVersion)
+	}
+
+	if version.Kind != deployedResource.Kind {
+		return "", ErrorCannotChangeKindOfDeployedAPI(apiName, version.Kind, deployedResource.Kind)
+	}
+
+	api, err := operator.DownloadAPISpec(apiName, version.APIID)
+	if err != nil {
+		return "", err
+	}
+
+	// the project was uploaded when the version was originally deployed
+	_, msg, err := UpdateAPI(api.API, api.ProjectID, force)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("rolling back %s to %s (deployed at %s): %s", apiName, version.APIID, version.DeployedAt.UTC().Format(time.RFC3339), msg), nil
+}
+
+func findVersionToRollBackTo(apiName string, history []schema.APIVersion, apiID string) (*schema.APIVersion, error) {
+	if len(history) == 0 {
+		return nil, ErrorNoPreviousAPIVersion(apiName)
+	}
+
+	if apiID == "" {
+		currentID := history[len(history)-1].APIID
+		for i := len(history) - 2; i >= 0; i-- {
+			if history[i].APIID != currentID {
+				return &history[i], nil
+			}
+		}
+		return nil, ErrorNoPreviousAPIVersion(apiName)
+	}
+
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].APIID == apiID {
+			return &history[i], nil
+		}
+	}
+
+	return nil, ErrorAPIVersionNotFound(apiName, apiID)
 }
 
 func RefreshAPI(apiName string, force bool) (string, error) {
@@ -311,6 +473,33 @@ func GetAPI(apiName string) (*schema.GetAPIResponse, error) {
 	}
 }
 
+func GetAutoscalerHistory(apiName string) (*schema.GetAutoscalingResponse, error) {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return nil, err
+	}
+
+	if deployedResource.Kind != userconfig.SyncAPIKind {
+		return nil, ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.SyncAPIKind)
+	}
+
+	return syncapi.GetAutoscalerHistory(apiName), nil
+}
+
+// Activate forwards a request for an API which has been scaled to zero once the API has scaled up
+func Activate(w http.ResponseWriter, r *http.Request, apiName string) error {
+	deployedResource, err := GetDeployedResourceByName(apiName)
+	if err != nil {
+		return err
+	}
+
+	if deployedResource.Kind != userconfig.SyncAPIKind {
+		return ErrorOperationIsOnlySupportedForKind(*deployedResource, userconfig.SyncAPIKind)
+	}
+
+	return syncapi.Activate(w, r, apiName)
+}
+
 //checkIfUsedByAPISplitter checks if api is used by a deployed APISplitter
 func checkIfUsedByAPISplitter(apiName string) error {
 	virtualServices, err := config.K8s.ListVirtualServicesByLabel("apiKind", userconfig.APISplitterKind.String())
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindVersionToRollBackTo(t *testing.T) {
+	history := []schema.APIVersion{{APIID: "a"}, {APIID: "b"}, {APIID: "c"}, {APIID: "b"}}
+
+	version, err := findVersionToRollBackTo("my-api", history, "")
+	require.NoError(t, err)
+	require.Equal(t, "c", version.APIID)
+
+	version, err = findVersionToRollBackTo("my-api", history, "a")
+	require.NoError(t, err)
+	require.Equal(t, "a", version.APIID)
+
+	_, err = findVersionToRollBackTo("my-api", history, "d")
+	require.Error(t, err)
+
+	_, err = findVersionToRollBackTo("my-api", history[:1], "")
+	require.Error(t, err)
+
+	_, err = findVersionToRollBackTo("my-api", []schema.APIVersion{{APIID: "a"}, {APIID: "a"}}, "")
+	require.Error(t, err)
+}
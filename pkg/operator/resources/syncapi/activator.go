@@ -0,0 +1,172 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	kapps "k8s.io/api/apps/v1"
+)
+
+const (
+	// the activator is served on a separate port of the operator service, which isn't routed to by the operator's load balancer
+	ActivatorPortStr    = "8889"
+	_activatorPortInt32 = int32(8889)
+
+	_activatorServiceName  = "operator"
+	_activatorPollInterval = 1 * time.Second
+	_activatorTimeout      = 5 * time.Minute
+)
+
+// requests which are being held by the activator while waiting for the API to scale up from zero
+var _activatorRequests = activatorRequests{counts: make(map[string]int)}
+
+type activatorRequests struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+func (requests *activatorRequests) inc(apiName string) {
+	requests.Lock()
+	defer requests.Unlock()
+	requests.counts[apiName]++
+}
+
+func (requests *activatorRequests) dec(apiName string) {
+	requests.Lock()
+	defer requests.Unlock()
+	requests.counts[apiName]--
+	if requests.counts[apiName] <= 0 {
+		delete(requests.counts, apiName)
+	}
+}
+
+func (requests *activatorRequests) get(apiName string) int {
+	requests.Lock()
+	defer requests.Unlock()
+	return requests.counts[apiName]
+}
+
+func activatorPath(apiName string) string {
+	return "/activator/" + apiName
+}
+
+func isRoutedToActivator(apiName string) (bool, error) {
+	virtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiName))
+	if err != nil {
+		return false, err
+	}
+	if virtualService == nil {
+		return false, nil
+	}
+
+	for _, httpRoute := range virtualService.Spec.Http {
+		for _, destination := range httpRoute.Route {
+			if destination.Destination != nil && destination.Destination.Host == _activatorServiceName {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func routeToActivator(apiName string) error {
+	virtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiName))
+	if err != nil {
+		return err
+	}
+	if virtualService == nil {
+		return errors.ErrorUnexpected("unable to find virtual service", apiName)
+	}
+
+	_, err = config.K8s.UpdateVirtualService(virtualService, activatorVirtualServiceSpec(virtualService, apiName))
+	return err
+}
+
+func routeToAPI(deployment *kapps.Deployment) error {
+	apiName := deployment.Labels["apiName"]
+
+	api, err := operator.DownloadAPISpec(apiName, deployment.Labels["apiID"])
+	if err != nil {
+		return err
+	}
+
+	virtualService, err := config.K8s.GetVirtualService(operator.K8sName(apiName))
+	if err != nil {
+		return err
+	}
+
+	return applyK8sVirtualService(api, virtualService)
+}
+
+// Activate holds a request for an API which has been scaled to zero until a replica is ready, and then forwards it to the API
+func Activate(w http.ResponseWriter, r *http.Request, apiName string) error {
+	// only requests for APIs which are scaled to zero are forwarded (otherwise the API's traffic would not be routed to the activator)
+	routedToActivator, err := isRoutedToActivator(apiName)
+	if err != nil {
+		return err
+	}
+	if !routedToActivator {
+		return ErrorAPINotScaledToZero(apiName)
+	}
+
+	_activatorRequests.inc(apiName)
+	defer _activatorRequests.dec(apiName)
+
+	_autoscalerCrons.runNow(apiName)
+
+	if err := waitForReadyReplica(apiName); err != nil {
+		return err
+	}
+
+	apiURL, err := url.Parse(fmt.Sprintf("http://%s:%d", operator.K8sName(apiName), operator.DefaultPortInt32))
+	if err != nil {
+		return err
+	}
+
+	r.URL.Path = "/predict"
+	httputil.NewSingleHostReverseProxy(apiURL).ServeHTTP(w, r)
+	return nil
+}
+
+func waitForReadyReplica(apiName string) error {
+	start := time.Now()
+	for {
+		deployment, err := config.K8s.GetDeployment(operator.K8sName(apiName))
+		if err != nil {
+			return err
+		}
+		if deployment == nil {
+			return errors.ErrorUnexpected("unable to find deployment", apiName)
+		}
+		if deployment.Status.ReadyReplicas > 0 {
+			return nil
+		}
+		if time.Since(start) > _activatorTimeout {
+			return ErrorActivationTimeout(apiName, _activatorTimeout)
+		}
+		time.Sleep(_activatorPollInterval)
+	}
+}
@@ -19,6 +19,7 @@ package syncapi
 import (
 	"fmt"
 	"path/filepath"
+	"sync"
 
 	"github.com/cortexlabs/cortex/pkg/lib/cron"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
@@ -35,9 +36,65 @@ import (
 	kcore "k8s.io/api/core/v1"
 )
 
-var _autoscalerCrons = make(map[string]cron.Cron) // apiName -> cron
+var _apiLocks = apiLocks{locks: make(map[string]*sync.Mutex)} // apiName -> lock
+
+// apiLocks serializes the operations which modify an api (updating, refreshing, and deleting it, and each tick of its canary rollout)
+type apiLocks struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock blocks until the api's lock is acquired, and returns the function which releases it
+func (locks *apiLocks) lock(apiName string) func() {
+	locks.Lock()
+	apiLock, ok := locks.locks[apiName]
+	if !ok {
+		apiLock = &sync.Mutex{}
+		locks.locks[apiName] = apiLock
+	}
+	locks.Unlock()
+
+	apiLock.Lock()
+	return apiLock.Unlock
+}
+
+var _autoscalerCrons = autoscalerCrons{crons: make(map[string]cron.Cron)} // apiName -> cron
+
+type autoscalerCrons struct {
+	sync.Mutex
+	crons map[string]cron.Cron
+}
+
+// set cancels the API's previous autoscaler cron (if any) and replaces it
+func (crons *autoscalerCrons) set(apiName string, autoscalerCron cron.Cron) {
+	crons.Lock()
+	defer crons.Unlock()
+	if prevAutoscalerCron, ok := crons.crons[apiName]; ok {
+		prevAutoscalerCron.Cancel()
+	}
+	crons.crons[apiName] = autoscalerCron
+}
+
+func (crons *autoscalerCrons) runNow(apiName string) {
+	crons.Lock()
+	defer crons.Unlock()
+	if autoscalerCron, ok := crons.crons[apiName]; ok {
+		autoscalerCron.RunNow()
+	}
+}
+
+func (crons *autoscalerCrons) cancel(apiName string) {
+	crons.Lock()
+	defer crons.Unlock()
+	if autoscalerCron, ok := crons.crons[apiName]; ok {
+		autoscalerCron.Cancel()
+		delete(crons.crons, apiName)
+	}
+}
 
 func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.API, string, error) {
+	defer _apiLocks.lock(apiConfig.Name)()
+
 	prevDeployment, prevService, prevVirtualService, err := getK8sResources(apiConfig)
 	if err != nil {
 		return nil, "", err
@@ -75,18 +132,43 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 		if err != nil {
 			return nil, "", err
 		}
-		if isUpdating && !force {
+		isRollingOut, err := isRolloutInProgress(api.Name)
+		if err != nil {
+			return nil, "", err
+		}
+		if (isUpdating || isRollingOut) && !force {
 			return nil, "", ErrorAPIUpdating(api.Name)
 		}
+		if isRollingOut {
+			if err := abortCanaryRollout(api.Name, "the api was re-deployed with --force"); err != nil {
+				return nil, "", err
+			}
+			// the virtual service now routes all traffic to the previous version
+			prevVirtualService, err = config.K8s.GetVirtualService(operator.K8sName(api.Name))
+			if err != nil {
+				return nil, "", err
+			}
+		}
 		if err := config.AWS.UploadMsgpackToS3(api, config.Cluster.Bucket, api.Key); err != nil {
 			return nil, "", errors.Wrap(err, "upload api spec")
 		}
+		// a canary is only useful if the previous version is serving traffic
+		if api.UpdateStrategy.Type == userconfig.CanaryUpdateStrategyType && prevDeployment.Status.ReadyReplicas > 0 {
+			if err := startCanaryRollout(api, prevDeployment); err != nil {
+				go deleteCanaryK8sResources(api.Name)
+				return nil, "", err
+			}
+			return api, fmt.Sprintf("rolling out %s as a canary", api.Resource.UserString()), nil
+		}
 		if err := applyK8sResources(api, prevDeployment, prevService, prevVirtualService); err != nil {
 			return nil, "", err
 		}
 		if err := operator.UpdateAPIGatewayK8s(prevVirtualService, api, false); err != nil {
 			return nil, "", err
 		}
+		if err := deleteRollout(api.Name); err != nil {
+			return nil, "", err
+		}
 		return api, fmt.Sprintf("updating %s", api.Resource.UserString()), nil
 	}
 
@@ -95,13 +177,40 @@ func UpdateAPI(apiConfig *userconfig.API, projectID string, force bool) (*spec.A
 	if err != nil {
 		return nil, "", err
 	}
-	if isUpdating {
+	isRollingOut, err := isRolloutInProgress(api.Name)
+	if err != nil {
+		return nil, "", err
+	}
+	if isUpdating || isRollingOut {
 		return api, fmt.Sprintf("%s is already updating", api.Resource.UserString()), nil
 	}
 	return api, fmt.Sprintf("%s is up to date", api.Resource.UserString()), nil
 }
 
+// DryRunUpdateAPI returns the spec which UpdateAPI would deploy, the currently deployed spec (nil if the API isn't deployed), and whether the API would be updated
+func DryRunUpdateAPI(apiConfig *userconfig.API, projectID string) (*spec.API, *spec.API, bool, error) {
+	prevDeployment, err := config.K8s.GetDeployment(operator.K8sName(apiConfig.Name))
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	if prevDeployment == nil {
+		return spec.GetAPISpec(apiConfig, projectID, k8s.RandomName()), nil, true, nil
+	}
+
+	api := spec.GetAPISpec(apiConfig, projectID, prevDeployment.Labels["deploymentID"])
+
+	prevAPI, err := operator.DownloadAPISpec(apiConfig.Name, prevDeployment.Labels["apiID"])
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return api, prevAPI, !areAPIsEqual(prevDeployment, deploymentSpec(api, prevDeployment)), nil
+}
+
 func RefreshAPI(apiName string, force bool) (string, error) {
+	defer _apiLocks.lock(apiName)()
+
 	prevDeployment, err := config.K8s.GetDeployment(operator.K8sName(apiName))
 	if err != nil {
 		return "", err
@@ -114,10 +223,21 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 		return "", err
 	}
 
-	if isUpdating && !force {
+	isRollingOut, err := isRolloutInProgress(apiName)
+	if err != nil {
+		return "", err
+	}
+
+	if (isUpdating || isRollingOut) && !force {
 		return "", ErrorAPIUpdating(apiName)
 	}
 
+	if isRollingOut {
+		if err := abortCanaryRollout(apiName, "the api was refreshed with --force"); err != nil {
+			return "", err
+		}
+	}
+
 	apiID, err := k8s.GetLabel(prevDeployment, "apiID")
 	if err != nil {
 		return "", err
@@ -142,6 +262,8 @@ func RefreshAPI(apiName string, force bool) (string, error) {
 }
 
 func DeleteAPI(apiName string, keepCache bool) error {
+	defer _apiLocks.lock(apiName)()
+
 	// best effort deletion, so don't handle error yet
 	virtualService, vsErr := config.K8s.GetVirtualService(operator.K8sName(apiName))
 
@@ -243,12 +365,12 @@ func namesAndIDsFromStatuses(statuses []status.Status) ([]string, []string) {
 }
 
 func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIResponse, error) {
-	status, err := GetStatus(deployedResource.Name)
+	apiStatus, err := GetStatus(deployedResource.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	api, err := operator.DownloadAPISpec(status.APIName, status.APIID)
+	api, err := operator.DownloadAPISpec(apiStatus.APIName, apiStatus.APIID)
 	if err != nil {
 		return nil, err
 	}
@@ -263,13 +385,23 @@ func GetAPIByName(deployedResource *operator.DeployedResource) (*schema.GetAPIRe
 		return nil, err
 	}
 
+	rollout, err := getRollout(apiStatus.APIName)
+	if err != nil {
+		return nil, err
+	}
+	// a successful rollout is reflected in the api's status
+	if rollout != nil && rollout.Code == status.RolloutSucceeded {
+		rollout = nil
+	}
+
 	return &schema.GetAPIResponse{
 		SyncAPI: &schema.SyncAPI{
 			Spec:         *api,
-			Status:       *status,
+			Status:       *apiStatus,
 			Metrics:      *metrics,
 			Endpoint:     apiEndpoint,
 			DashboardURL: DashboardURL(),
+			Rollout:      rollout,
 		},
 	}, nil
 }
@@ -346,16 +478,12 @@ func applyK8sDeployment(api *spec.API, prevDeployment *kapps.Deployment) error {
 func UpdateAutoscalerCron(deployment *kapps.Deployment) error {
 	apiName := deployment.Labels["apiName"]
 
-	if prevAutoscalerCron, ok := _autoscalerCrons[apiName]; ok {
-		prevAutoscalerCron.Cancel()
-	}
-
-	autoscaler, err := autoscaleFn(deployment)
+	autoscaler, err := autoscaleFn(deployment, metricsSourceFromConfig())
 	if err != nil {
 		return err
 	}
 
-	_autoscalerCrons[apiName] = cron.Run(autoscaler, operator.ErrorHandler(apiName+" autoscaler"), spec.AutoscalingTickInterval)
+	_autoscalerCrons.set(apiName, cron.Run(autoscaler, operator.ErrorHandler(apiName+" autoscaler"), spec.AutoscalingTickInterval))
 
 	return nil
 }
@@ -387,10 +515,8 @@ func applyK8sVirtualService(api *spec.API, prevVirtualService *istioclientnetwor
 func deleteK8sResources(apiName string) error {
 	return parallel.RunFirstErr(
 		func() error {
-			if autoscalerCron, ok := _autoscalerCrons[apiName]; ok {
-				autoscalerCron.Cancel()
-				delete(_autoscalerCrons, apiName)
-			}
+			_autoscalerCrons.cancel(apiName)
+			_autoscalerHistories.delete(apiName)
 
 			_, err := config.K8s.DeleteDeployment(operator.K8sName(apiName))
 			return err
@@ -403,6 +529,12 @@ func deleteK8sResources(apiName string) error {
 			_, err := config.K8s.DeleteVirtualService(operator.K8sName(apiName))
 			return err
 		},
+		func() error {
+			return deleteAutoscalerState(apiName)
+		},
+		func() error {
+			return deleteCanaryResources(apiName)
+		},
 	)
 }
 
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncapi
+
+import (
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+)
+
+const _autoscalerHistorySize = 360 // one hour of ticks
+
+// history of autoscaling decisions for each API, which is kept across autoscaler restarts (e.g. when the API is updated)
+var _autoscalerHistories = autoscalerHistories{histories: make(map[string]*autoscalerHistory)}
+
+type autoscalerHistories struct {
+	sync.Mutex
+	histories map[string]*autoscalerHistory
+}
+
+func (histories *autoscalerHistories) getOrCreate(apiName string) *autoscalerHistory {
+	histories.Lock()
+	defer histories.Unlock()
+	if history, ok := histories.histories[apiName]; ok {
+		return history
+	}
+	history := newAutoscalerHistory(_autoscalerHistorySize)
+	histories.histories[apiName] = history
+	return history
+}
+
+func (histories *autoscalerHistories) get(apiName string) *autoscalerHistory {
+	histories.Lock()
+	defer histories.Unlock()
+	return histories.histories[apiName]
+}
+
+func (histories *autoscalerHistories) delete(apiName string) {
+	histories.Lock()
+	defer histories.Unlock()
+	delete(histories.histories, apiName)
+}
+
+// ring buffer of the most recent autoscaler ticks
+type autoscalerHistory struct {
+	sync.Mutex
+	ticks []schema.AutoscalerTick
+	next  int
+	full  bool
+}
+
+func newAutoscalerHistory(size int) *autoscalerHistory {
+	return &autoscalerHistory{
+		ticks: make([]schema.AutoscalerTick, size),
+	}
+}
+
+func (history *autoscalerHistory) add(tick schema.AutoscalerTick) {
+	history.Lock()
+	defer history.Unlock()
+	history.ticks[history.next] = tick
+	history.next = (history.next + 1) % len(history.ticks)
+	if history.next == 0 {
+		history.full = true
+	}
+}
+
+// Returns the recorded ticks, oldest first
+func (history *autoscalerHistory) list() []schema.AutoscalerTick {
+	history.Lock()
+	defer history.Unlock()
+	if !history.full {
+		return append([]schema.AutoscalerTick{}, history.ticks[:history.next]...)
+	}
+	return append(append([]schema.AutoscalerTick{}, history.ticks[history.next:]...), history.ticks[:history.next]...)
+}
+
+func GetAutoscalerHistory(apiName string) *schema.GetAutoscalingResponse {
+	response := schema.GetAutoscalingResponse{
+		APIName: apiName,
+		Ticks:   []schema.AutoscalerTick{},
+	}
+	if history := _autoscalerHistories.get(apiName); history != nil {
+		response.Ticks = history.list()
+	}
+	return &response
+}
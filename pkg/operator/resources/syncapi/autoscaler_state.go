@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncapi
+
+import (
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// the autoscaler's state is checkpointed to a config map so that the stabilization periods aren't reset when the operator restarts
+const (
+	_autoscalerStateConfigMapKey = "state"
+	// the state changes on every tick, so it's only checkpointed when the number of replicas changes, or at most once per interval
+	_autoscalerStateCheckpointInterval = 1 * time.Minute
+)
+
+func autoscalerStateConfigMapName(apiName string) string {
+	return operator.K8sName(apiName) + "-autoscaler"
+}
+
+// Returns nil if no state has been checkpointed for the API
+func getAutoscalerState(apiName string) (*autoscaler.State, error) {
+	configMapData, err := config.K8s.GetConfigMapData(autoscalerStateConfigMapName(apiName))
+	if err != nil {
+		return nil, err
+	}
+
+	stateStr, ok := configMapData[_autoscalerStateConfigMapKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var state autoscaler.State
+	if err := json.Unmarshal([]byte(stateStr), &state); err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// Returns false if there was no state to save
+func saveAutoscalerState(apiName string, state *autoscaler.State) (bool, error) {
+	if state == nil {
+		return false, nil
+	}
+
+	stateStr, err := json.MarshalJSONStr(state)
+	if err != nil {
+		return false, err
+	}
+
+	configMap := k8s.ConfigMap(&k8s.ConfigMapSpec{
+		Name: autoscalerStateConfigMapName(apiName),
+		Data: map[string]string{
+			_autoscalerStateConfigMapKey: stateStr,
+		},
+		Labels: map[string]string{
+			"apiName": apiName,
+			"apiKind": userconfig.SyncAPIKind.String(),
+		},
+	})
+
+	if _, err := config.K8s.ApplyConfigMap(configMap); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func deleteAutoscalerState(apiName string) error {
+	_, err := config.K8s.DeleteConfigMap(autoscalerStateConfigMapName(apiName))
+	return err
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/schema"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoscalerHistory(t *testing.T) {
+	history := newAutoscalerHistory(2)
+	require.Empty(t, history.list())
+
+	for i := int32(1); i <= 3; i++ {
+		history.add(schema.AutoscalerTick{CurrentReplicas: i})
+	}
+	ticks := history.list()
+	require.Len(t, ticks, 2)
+	require.Equal(t, int32(2), ticks[0].CurrentReplicas)
+	require.Equal(t, int32(3), ticks[1].CurrentReplicas)
+
+	autoscalingSpec := &userconfig.Autoscaling{
+		MinReplicas:              1,
+		MaxReplicas:              100,
+		Policy:                   userconfig.TargetReplicaConcurrencyAutoscalingPolicy,
+		TargetReplicaConcurrency: pointer.Float64(1),
+		Window:                   10 * time.Second,
+		MaxDownscaleFactor:       0.75,
+		MaxUpscaleFactor:         1.5,
+		DownscaleTolerance:       0.05,
+		UpscaleTolerance:         0.05,
+	}
+
+	history = newAutoscalerHistory(2)
+	metricsSource := autoscaler.NewInMemoryMetricsSource()
+	a := autoscaler.New("test", "test-id", autoscalingSpec, 4, metricsSource, autoscaler.RealClock)
+	a.OnTick = history.add
+
+	_, err := a.NextRequest()
+	require.NoError(t, err)
+	metricsSource.SetAvgInFlight("test", pointer.Float64(4.1))
+	_, err = a.NextRequest()
+	require.NoError(t, err)
+
+	ticks = history.list()
+	require.Len(t, ticks, 2)
+	require.Nil(t, ticks[0].Request)
+	require.Equal(t, "metrics not available yet", ticks[0].Message)
+	require.Equal(t, 4.1, *ticks[1].AvgInFlight)
+	require.True(t, ticks[1].WithinTolerance)
+	require.Equal(t, int32(4), *ticks[1].Request)
+}
+
+func TestPrometheusMetricsSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/query", r.URL.Path)
+		require.Contains(t, r.URL.Query().Get("query"), `api_name="test"`)
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1600000000,"2.5"]}]}}`)
+	}))
+	defer server.Close()
+
+	metricsSource := &PrometheusMetricsSource{URL: server.URL}
+	avgInFlight, err := metricsSource.AvgInFlight("test", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 2.5, *avgInFlight)
+}
@@ -0,0 +1,581 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncapi
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/parallel"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	s "github.com/cortexlabs/cortex/pkg/lib/strings"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+	"github.com/cortexlabs/cortex/pkg/types/status"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	istioclientnetworking "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	kapps "k8s.io/api/apps/v1"
+	kcore "k8s.io/api/core/v1"
+)
+
+const (
+	_canaryTickInterval      = 10 * time.Second
+	_canaryReadyTimeout      = 15 * time.Minute
+	_rolloutConfigMapDataKey = "rollout"
+)
+
+var _canaryCrons = canaryCrons{crons: make(map[string]cron.Cron)}
+
+type canaryCrons struct {
+	sync.Mutex
+	crons map[string]cron.Cron
+}
+
+func (crons *canaryCrons) start(apiName string) {
+	crons.Lock()
+	defer crons.Unlock()
+	if prevCron, ok := crons.crons[apiName]; ok {
+		prevCron.Cancel()
+	}
+	crons.crons[apiName] = cron.Run(canaryFn(apiName), operator.ErrorHandler(apiName+" canary rollout"), _canaryTickInterval)
+}
+
+func (crons *canaryCrons) stop(apiName string) {
+	crons.Lock()
+	defer crons.Unlock()
+	if prevCron, ok := crons.crons[apiName]; ok {
+		prevCron.Cancel()
+		delete(crons.crons, apiName)
+	}
+}
+
+func (crons *canaryCrons) has(apiName string) bool {
+	crons.Lock()
+	defer crons.Unlock()
+	_, ok := crons.crons[apiName]
+	return ok
+}
+
+func canaryK8sName(apiName string) string {
+	return operator.K8sName(apiName) + "-canary"
+}
+
+func rolloutConfigMapName(apiName string) string {
+	return operator.K8sName(apiName) + "-rollout"
+}
+
+// Returns nil if the API has never been updated with a canary rollout (or was updated with a rolling update since)
+func getRollout(apiName string) (*status.Rollout, error) {
+	configMapData, err := config.K8s.GetConfigMapData(rolloutConfigMapName(apiName))
+	if err != nil {
+		return nil, err
+	}
+
+	rolloutStr, ok := configMapData[_rolloutConfigMapDataKey]
+	if !ok {
+		return nil, nil
+	}
+
+	var rollout status.Rollout
+	if err := json.Unmarshal([]byte(rolloutStr), &rollout); err != nil {
+		return nil, err
+	}
+
+	return &rollout, nil
+}
+
+func saveRollout(apiName string, rollout *status.Rollout) error {
+	rolloutStr, err := json.MarshalJSONStr(rollout)
+	if err != nil {
+		return err
+	}
+
+	configMap := k8s.ConfigMap(&k8s.ConfigMapSpec{
+		Name: rolloutConfigMapName(apiName),
+		Data: map[string]string{
+			_rolloutConfigMapDataKey: rolloutStr,
+		},
+		Labels: map[string]string{
+			"apiName": apiName,
+			"apiKind": userconfig.SyncAPIKind.String(),
+		},
+	})
+
+	_, err = config.K8s.ApplyConfigMap(configMap)
+	return err
+}
+
+func deleteRollout(apiName string) error {
+	_, err := config.K8s.DeleteConfigMap(rolloutConfigMapName(apiName))
+	return err
+}
+
+func isRolloutInProgress(apiName string) (bool, error) {
+	rollout, err := getRollout(apiName)
+	if err != nil {
+		return false, err
+	}
+	return rollout != nil && rollout.Code.IsInProgress(), nil
+}
+
+// ResumeCanaryRollout restarts the rollout of an API which was in progress when the operator was restarted
+func ResumeCanaryRollout(apiName string) error {
+	inProgress, err := isRolloutInProgress(apiName)
+	if err != nil {
+		return err
+	}
+	if inProgress {
+		_canaryCrons.start(apiName)
+	}
+	return nil
+}
+
+// deploys the new version of the API next to the previous one; traffic is shifted to it once it's ready
+func startCanaryRollout(api *spec.API, prevDeployment *kapps.Deployment) error {
+	steps := api.UpdateStrategy.Canary.Steps
+
+	if err := applyCanaryK8sResources(api, canaryReplicas(prevDeployment, steps[0])); err != nil {
+		return err
+	}
+
+	rollout := &status.Rollout{
+		APIID:         api.ID,
+		PrevAPIID:     prevDeployment.Labels["apiID"],
+		Code:          status.RolloutInProgress,
+		Steps:         steps,
+		Step:          -1,
+		StartTime:     time.Now(),
+		StepStartTime: time.Now(),
+		Message:       "waiting for the canary to become ready",
+	}
+	if err := saveRollout(api.Name, rollout); err != nil {
+		return err
+	}
+
+	_canaryCrons.start(api.Name)
+	return nil
+}
+
+// stops an in-progress rollout and routes all traffic to the previous version
+func abortCanaryRollout(apiName string, message string) error {
+	_canaryCrons.stop(apiName)
+
+	rollout, err := getRollout(apiName)
+	if err != nil {
+		return err
+	}
+	if rollout == nil || !rollout.Code.IsInProgress() {
+		return nil
+	}
+
+	prevAPI, err := operator.DownloadAPISpec(apiName, rollout.PrevAPIID)
+	if err != nil {
+		return err
+	}
+
+	return rollBackCanary(prevAPI, rollout, message)
+}
+
+func canaryFn(apiName string) func() error {
+	return func() error {
+		// the api can't be updated or deleted while the tick runs, so the rollout and the api's resources can't change under it
+		defer _apiLocks.lock(apiName)()
+
+		rollout, err := getRollout(apiName)
+		if err != nil {
+			return err
+		}
+		if rollout == nil || !rollout.Code.IsInProgress() {
+			_canaryCrons.stop(apiName)
+			return nil
+		}
+
+		api, err := operator.DownloadAPISpec(apiName, rollout.APIID)
+		if err != nil {
+			return err
+		}
+		prevAPI, err := operator.DownloadAPISpec(apiName, rollout.PrevAPIID)
+		if err != nil {
+			return err
+		}
+
+		if rollout.Code == status.RolloutPromoting {
+			return finishPromotingCanary(api, rollout)
+		}
+
+		canaryDeployment, err := config.K8s.GetDeployment(canaryK8sName(apiName))
+		if err != nil {
+			return err
+		}
+		if canaryDeployment == nil {
+			return rollBackCanary(prevAPI, rollout, "the canary deployment was deleted")
+		}
+
+		// wait for the canary to become ready before routing traffic to it
+		if rollout.Step == -1 {
+			if canaryDeployment.Status.ReadyReplicas > 0 {
+				return advanceCanary(prevAPI, rollout, 0)
+			}
+			if time.Since(rollout.StartTime) > _canaryReadyTimeout {
+				return rollBackCanary(prevAPI, rollout, fmt.Sprintf("the canary did not become ready within %s", _canaryReadyTimeout))
+			}
+			return nil
+		}
+
+		if time.Since(rollout.StepStartTime) < api.UpdateStrategy.Canary.StepDuration {
+			return nil
+		}
+
+		reason, err := canaryThresholdBreach(api, rollout.StepStartTime)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			return rollBackCanary(prevAPI, rollout, reason)
+		}
+
+		if rollout.Step+1 < len(rollout.Steps) {
+			return advanceCanary(prevAPI, rollout, rollout.Step+1)
+		}
+
+		return promoteCanary(api, rollout)
+	}
+}
+
+// Returns a description of the breached threshold, or "" if the canary is healthy (or didn't receive any requests)
+func canaryThresholdBreach(api *spec.API, since time.Time) (string, error) {
+	canaryMetrics, err := getMetricsSince(api, since)
+	if err != nil {
+		return "", err
+	}
+
+	networkStats := canaryMetrics.NetworkStats
+	if networkStats == nil || networkStats.Total == 0 {
+		return "", nil
+	}
+
+	canary := api.UpdateStrategy.Canary
+
+	errorRate := float64(networkStats.Code5XX) / float64(networkStats.Total)
+	if errorRate > canary.MaxErrorRate {
+		return fmt.Sprintf("the canary's 5XX error rate (%s%%) exceeded %s (%s%%)", s.Round(errorRate*100, 2, 0), userconfig.MaxErrorRateKey, s.Round(canary.MaxErrorRate*100, 2, 0)), nil
+	}
+
+	if canary.MaxLatency != nil && networkStats.Latency != nil {
+		latency := time.Duration(*networkStats.Latency * float64(time.Millisecond))
+		if latency > *canary.MaxLatency {
+			return fmt.Sprintf("the canary's average latency (%s) exceeded %s (%s)", latency.Truncate(time.Millisecond), userconfig.MaxLatencyKey, canary.MaxLatency.String()), nil
+		}
+	}
+
+	return "", nil
+}
+
+func advanceCanary(prevAPI *spec.API, rollout *status.Rollout, step int) error {
+	weight := rollout.Steps[step]
+
+	primaryDeployment, err := config.K8s.GetDeployment(operator.K8sName(prevAPI.Name))
+	if err != nil {
+		return err
+	}
+	if primaryDeployment == nil {
+		return errors.ErrorUnexpected("unable to find deployment", prevAPI.Name)
+	}
+
+	canaryDeployment, err := config.K8s.GetDeployment(canaryK8sName(prevAPI.Name))
+	if err != nil {
+		return err
+	}
+	if canaryDeployment == nil {
+		return errors.ErrorUnexpected("unable to find canary deployment", prevAPI.Name)
+	}
+
+	canaryDeployment.Spec.Replicas = pointer.Int32(canaryReplicas(primaryDeployment, weight))
+	if _, err := config.K8s.UpdateDeployment(canaryDeployment); err != nil {
+		return err
+	}
+
+	if err := applyCanaryVirtualService(prevAPI, weight); err != nil {
+		return err
+	}
+
+	log.Printf("%s canary rollout: routing %d%% of traffic to the canary", prevAPI.Name, weight)
+
+	rollout.Step = step
+	rollout.Weight = weight
+	rollout.StepStartTime = time.Now()
+	rollout.Message = ""
+	return saveRollout(prevAPI.Name, rollout)
+}
+
+// updates the API's deployment to the new version; traffic continues to be split until the update is complete
+func promoteCanary(api *spec.API, rollout *status.Rollout) error {
+	primaryDeployment, err := config.K8s.GetDeployment(operator.K8sName(api.Name))
+	if err != nil {
+		return err
+	}
+	if primaryDeployment == nil {
+		// the api was deleted during the rollout; applying the deployment would recreate it (and its autoscaler)
+		_canaryCrons.stop(api.Name)
+		return nil
+	}
+
+	// the api's lock is held during the tick, so the api can't be deleted before its deployment is updated
+	if err := applyK8sDeployment(api, primaryDeployment); err != nil {
+		return err
+	}
+
+	log.Printf("%s canary rollout: promoting the canary", api.Name)
+
+	rollout.Code = status.RolloutPromoting
+	rollout.Message = "updating the api to the new version"
+	return saveRollout(api.Name, rollout)
+}
+
+func finishPromotingCanary(api *spec.API, rollout *status.Rollout) error {
+	primaryDeployment, err := config.K8s.GetDeployment(operator.K8sName(api.Name))
+	if err != nil {
+		return err
+	}
+	if primaryDeployment == nil {
+		return errors.ErrorUnexpected("unable to find deployment", api.Name)
+	}
+
+	isUpdating, err := isAPIUpdating(primaryDeployment)
+	if err != nil {
+		return err
+	}
+	if isUpdating {
+		return nil
+	}
+
+	prevService, err := config.K8s.GetService(operator.K8sName(api.Name))
+	if err != nil {
+		return err
+	}
+	prevVirtualService, err := config.K8s.GetVirtualService(operator.K8sName(api.Name))
+	if err != nil {
+		return err
+	}
+
+	// networking changes (e.g. to the endpoint) are applied once the new version is receiving all traffic
+	if err := applyK8sService(api, prevService); err != nil {
+		return err
+	}
+	if err := applyK8sVirtualService(api, prevVirtualService); err != nil {
+		return err
+	}
+	if prevVirtualService != nil {
+		if err := operator.UpdateAPIGatewayK8s(prevVirtualService, api, false); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteCanaryK8sResources(api.Name); err != nil {
+		return err
+	}
+
+	log.Printf("%s canary rollout: succeeded", api.Name)
+
+	rollout.Code = status.RolloutSucceeded
+	rollout.Weight = 100
+	rollout.EndTime = pointer.Time(time.Now())
+	rollout.Message = ""
+	if err := saveRollout(api.Name, rollout); err != nil {
+		return err
+	}
+
+	_canaryCrons.stop(api.Name)
+	return nil
+}
+
+func rollBackCanary(prevAPI *spec.API, rollout *status.Rollout, message string) error {
+	prevVirtualService, err := config.K8s.GetVirtualService(operator.K8sName(prevAPI.Name))
+	if err != nil {
+		return err
+	}
+	if err := applyK8sVirtualService(prevAPI, prevVirtualService); err != nil {
+		return err
+	}
+
+	if err := deleteCanaryK8sResources(prevAPI.Name); err != nil {
+		return err
+	}
+
+	log.Printf("%s canary rollout: rolled back (%s)", prevAPI.Name, message)
+
+	rollout.Code = status.RolloutRolledBack
+	rollout.Weight = 0
+	rollout.EndTime = pointer.Time(time.Now())
+	rollout.Message = message
+	if err := saveRollout(prevAPI.Name, rollout); err != nil {
+		return err
+	}
+
+	_canaryCrons.stop(prevAPI.Name)
+	return nil
+}
+
+// the canary's share of the replicas is proportional to its share of the traffic
+func canaryReplicas(primaryDeployment *kapps.Deployment, weight int32) int32 {
+	primaryReplicas := int32(1)
+	if primaryDeployment != nil && primaryDeployment.Spec.Replicas != nil && *primaryDeployment.Spec.Replicas > 0 {
+		primaryReplicas = *primaryDeployment.Spec.Replicas
+	}
+
+	replicas := int32(math.Ceil(float64(primaryReplicas) * float64(weight) / 100))
+	if replicas < 1 {
+		replicas = 1
+	}
+	return replicas
+}
+
+func applyCanaryK8sResources(api *spec.API, replicas int32) error {
+	return parallel.RunFirstErr(
+		func() error {
+			canaryDeployment := canaryDeploymentSpec(api, replicas)
+			prevCanaryDeployment, err := config.K8s.GetDeployment(canaryDeployment.Name)
+			if err != nil {
+				return err
+			}
+			if prevCanaryDeployment != nil {
+				config.K8s.DeleteDeployment(canaryDeployment.Name)
+			}
+			_, err = config.K8s.CreateDeployment(canaryDeployment)
+			return err
+		},
+		func() error {
+			_, err := config.K8s.ApplyService(canaryServiceSpec(api))
+			return err
+		},
+	)
+}
+
+func applyCanaryVirtualService(prevAPI *spec.API, weight int32) error {
+	prevVirtualService, err := config.K8s.GetVirtualService(operator.K8sName(prevAPI.Name))
+	if err != nil {
+		return err
+	}
+	if prevVirtualService == nil {
+		return errors.ErrorUnexpected("unable to find virtual service", prevAPI.Name)
+	}
+
+	_, err = config.K8s.UpdateVirtualService(prevVirtualService, canaryVirtualServiceSpec(prevAPI, weight))
+	return err
+}
+
+func deleteCanaryK8sResources(apiName string) error {
+	return parallel.RunFirstErr(
+		func() error {
+			_, err := config.K8s.DeleteDeployment(canaryK8sName(apiName))
+			return err
+		},
+		func() error {
+			_, err := config.K8s.DeleteService(canaryK8sName(apiName))
+			return err
+		},
+	)
+}
+
+func deleteCanaryResources(apiName string) error {
+	_canaryCrons.stop(apiName)
+
+	return parallel.RunFirstErr(
+		func() error {
+			return deleteCanaryK8sResources(apiName)
+		},
+		func() error {
+			return deleteRollout(apiName)
+		},
+	)
+}
+
+// canary pods are labeled with "canaryOf" rather than "apiName", so that they aren't selected by the API's service or counted in its status
+func canaryDeploymentSpec(api *spec.API, replicas int32) *kapps.Deployment {
+	deployment := deploymentSpec(api, nil)
+
+	labels := map[string]string{
+		"canaryOf":     api.Name,
+		"apiKind":      api.Kind.String(),
+		"apiID":        api.ID,
+		"deploymentID": api.DeploymentID,
+	}
+
+	deployment.Name = canaryK8sName(api.Name)
+	deployment.Labels = labels
+	deployment.Spec.Replicas = &replicas
+	deployment.Spec.Selector.MatchLabels = map[string]string{
+		"canaryOf": api.Name,
+		"apiKind":  api.Kind.String(),
+	}
+	deployment.Spec.Template.Name = canaryK8sName(api.Name)
+	deployment.Spec.Template.Labels = labels
+
+	return deployment
+}
+
+func canaryServiceSpec(api *spec.API) *kcore.Service {
+	return k8s.Service(&k8s.ServiceSpec{
+		Name:        canaryK8sName(api.Name),
+		Port:        operator.DefaultPortInt32,
+		TargetPort:  operator.DefaultPortInt32,
+		Annotations: api.ToK8sAnnotations(),
+		Labels: map[string]string{
+			"canaryOf": api.Name,
+			"apiKind":  api.Kind.String(),
+		},
+		Selector: map[string]string{
+			"canaryOf": api.Name,
+			"apiKind":  api.Kind.String(),
+		},
+	})
+}
+
+// splits the traffic to the API's endpoint between the previous version and the canary
+func canaryVirtualServiceSpec(prevAPI *spec.API, weight int32) *istioclientnetworking.VirtualService {
+	return k8s.VirtualService(&k8s.VirtualServiceSpec{
+		Name:     operator.K8sName(prevAPI.Name),
+		Gateways: []string{"apis-gateway"},
+		Destinations: []k8s.Destination{
+			{
+				ServiceName: operator.K8sName(prevAPI.Name),
+				Weight:      100 - weight,
+				Port:        uint32(operator.DefaultPortInt32),
+			},
+			{
+				ServiceName: canaryK8sName(prevAPI.Name),
+				Weight:      weight,
+				Port:        uint32(operator.DefaultPortInt32),
+			},
+		},
+		ExactPath:   prevAPI.Networking.Endpoint,
+		Rewrite:     pointer.String("predict"),
+		Annotations: prevAPI.ToK8sAnnotations(),
+		Labels: map[string]string{
+			"apiName": prevAPI.Name,
+			"apiKind": prevAPI.Kind.String(),
+		},
+	})
+}
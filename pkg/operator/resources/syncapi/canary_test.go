@@ -0,0 +1,38 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncapi
+
+import (
+	"testing"
+
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/stretchr/testify/require"
+	kapps "k8s.io/api/apps/v1"
+)
+
+func TestCanaryReplicas(t *testing.T) {
+	deployment := func(replicas int32) *kapps.Deployment {
+		return &kapps.Deployment{Spec: kapps.DeploymentSpec{Replicas: pointer.Int32(replicas)}}
+	}
+
+	require.Equal(t, int32(1), canaryReplicas(deployment(10), 10))
+	require.Equal(t, int32(5), canaryReplicas(deployment(10), 50))
+	require.Equal(t, int32(2), canaryReplicas(deployment(3), 50))
+	require.Equal(t, int32(1), canaryReplicas(deployment(3), 1))
+	require.Equal(t, int32(1), canaryReplicas(deployment(0), 50))
+	require.Equal(t, int32(1), canaryReplicas(nil, 90))
+}
@@ -18,12 +18,16 @@ package syncapi
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 )
 
 const (
-	ErrAPIUpdating = "syncapi.api_updating"
+	ErrAPIUpdating        = "syncapi.api_updating"
+	ErrPrometheusQuery    = "syncapi.prometheus_query"
+	ErrActivationTimeout  = "syncapi.activation_timeout"
+	ErrAPINotScaledToZero = "syncapi.api_not_scaled_to_zero"
 )
 
 func ErrorAPIUpdating(apiName string) error {
@@ -32,3 +36,24 @@ func ErrorAPIUpdating(apiName string) error {
 		Message: fmt.Sprintf("%s is updating (override with --force)", apiName),
 	})
 }
+
+func ErrorPrometheusQuery(prometheusURL string, metricName string, reason string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPrometheusQuery,
+		Message: fmt.Sprintf("unable to query %s from prometheus at %s: %s", metricName, prometheusURL, reason),
+	})
+}
+
+func ErrorActivationTimeout(apiName string, timeout time.Duration) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrActivationTimeout,
+		Message: fmt.Sprintf("%s did not scale up from zero within %s", apiName, timeout),
+	})
+}
+
+func ErrorAPINotScaledToZero(apiName string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrAPINotScaledToZero,
+		Message: fmt.Sprintf("%s is not scaled to zero", apiName),
+	})
+}
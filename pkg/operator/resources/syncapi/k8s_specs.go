@@ -68,9 +68,7 @@ func tensorflowAPISpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.D
 				"apiID":        api.ID,
 				"deploymentID": api.DeploymentID,
 			},
-			Annotations: map[string]string{
-				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
-			},
+			Annotations: podAnnotations(),
 			K8sPodSpec: kcore.PodSpec{
 				RestartPolicy: "Always",
 				InitContainers: []kcore.Container{
@@ -115,9 +113,7 @@ func pythonAPISpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.Deplo
 				"apiID":        api.ID,
 				"deploymentID": api.DeploymentID,
 			},
-			Annotations: map[string]string{
-				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
-			},
+			Annotations: podAnnotations(),
 			K8sPodSpec: kcore.PodSpec{
 				RestartPolicy: "Always",
 				InitContainers: []kcore.Container{
@@ -162,9 +158,7 @@ func onnxAPISpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.Deploym
 				"apiID":        api.ID,
 				"deploymentID": api.DeploymentID,
 			},
-			Annotations: map[string]string{
-				"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
-			},
+			Annotations: podAnnotations(),
 			K8sPodSpec: kcore.PodSpec{
 				InitContainers: []kcore.Container{
 					operator.InitContainer(api),
@@ -181,6 +175,15 @@ func onnxAPISpec(api *spec.API, prevDeployment *kapps.Deployment) *kapps.Deploym
 	})
 }
 
+func podAnnotations() map[string]string {
+	return map[string]string{
+		"traffic.sidecar.istio.io/excludeOutboundIPRanges": "0.0.0.0/0",
+		// allows the autoscaler's in-flight metric to be scraped when using prometheus as the metrics source
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   operator.RequestMonitorMetricsPortStr,
+	}
+}
+
 func serviceSpec(api *spec.API) *kcore.Service {
 	return k8s.Service(&k8s.ServiceSpec{
 		Name:        operator.K8sName(api.Name),
@@ -217,6 +220,25 @@ func virtualServiceSpec(api *spec.API) *istioclientnetworking.VirtualService {
 	})
 }
 
+// routes the API's endpoint to the activator while the API is scaled to zero
+func activatorVirtualServiceSpec(virtualService *istioclientnetworking.VirtualService, apiName string) *istioclientnetworking.VirtualService {
+	endpoint := virtualService.Annotations[userconfig.EndpointAnnotationKey]
+
+	return k8s.VirtualService(&k8s.VirtualServiceSpec{
+		Name:     virtualService.Name,
+		Gateways: []string{"apis-gateway"},
+		Destinations: []k8s.Destination{{
+			ServiceName: _activatorServiceName,
+			Weight:      100,
+			Port:        uint32(_activatorPortInt32),
+		}},
+		ExactPath:   &endpoint,
+		Rewrite:     pointer.String(activatorPath(apiName)),
+		Annotations: virtualService.Annotations,
+		Labels:      virtualService.Labels,
+	})
+}
+
 func getRequestedReplicasFromDeployment(api *spec.API, deployment *kapps.Deployment) int32 {
 	requestedReplicas := api.Autoscaling.InitReplicas
 
@@ -90,6 +90,20 @@ func GetMetrics(api *spec.API) (*metrics.Metrics, error) {
 	return &mergedMetrics, nil
 }
 
+// metrics are aggregated over one-minute periods; since the metrics are filtered by API ID, a canary's metrics don't include the previous version's
+func getMetricsSince(api *spec.API, startTime time.Time) (*metrics.Metrics, error) {
+	endTime := time.Now().Truncate(time.Second)
+	startTime = startTime.Truncate(time.Minute)
+
+	apiMetrics := metrics.Metrics{}
+	if err := getMetricsFunc(api, 60, &startTime, &endTime, &apiMetrics)(); err != nil {
+		return nil, err
+	}
+
+	apiMetrics.APIName = api.Name
+	return &apiMetrics, nil
+}
+
 func getMetricsFunc(api *spec.API, period int64, startTime *time.Time, endTime *time.Time, metrics *metrics.Metrics) func() error {
 	return func() error {
 		metricDataResults, err := queryMetrics(api, period, startTime, endTime)
@@ -0,0 +1,258 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package syncapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/cortexlabs/cortex/pkg/lib/errors"
+	libmath "github.com/cortexlabs/cortex/pkg/lib/math"
+	"github.com/cortexlabs/cortex/pkg/operator/autoscaler"
+	"github.com/cortexlabs/cortex/pkg/operator/config"
+	"github.com/cortexlabs/cortex/pkg/operator/operator"
+	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
+	"github.com/cortexlabs/cortex/pkg/types/spec"
+)
+
+func metricsSourceFromConfig() autoscaler.MetricsSource {
+	if config.Cluster.AutoscalerMetricsSource == clusterconfig.PrometheusMetricsSource {
+		return &PrometheusMetricsSource{URL: *config.Cluster.PrometheusURL}
+	}
+	return &CloudWatchMetricsSource{}
+}
+
+// CloudWatchMetricsSource reads the in-flight metric published to CloudWatch by the request monitor
+type CloudWatchMetricsSource struct{}
+
+func (*CloudWatchMetricsSource) AvgInFlight(apiName string, window time.Duration) (*float64, error) {
+	endTime := time.Now().Truncate(time.Second)
+	startTime := endTime.Add(-2 * window)
+	metricsDataQuery := cloudwatch.GetMetricDataInput{
+		EndTime:   &endTime,
+		StartTime: &startTime,
+		MetricDataQueries: []*cloudwatch.MetricDataQuery{
+			{
+				Id:    aws.String("inflight"),
+				Label: aws.String("InFlight"),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(config.Cluster.ClusterName),
+						MetricName: aws.String("in-flight"),
+						Dimensions: []*cloudwatch.Dimension{
+							{
+								Name:  aws.String("apiName"),
+								Value: aws.String(apiName),
+							},
+						},
+					},
+					Stat:   aws.String("Sum"),
+					Period: aws.Int64(10),
+				},
+			},
+		},
+	}
+
+	output, err := config.AWS.CloudWatch().GetMetricData(&metricsDataQuery)
+	if err != nil {
+		return nil, err
+	}
+	if len(output.MetricDataResults) == 0 {
+		return nil, nil
+	}
+
+	timestampCounter := -1
+	for i, timeStamp := range output.MetricDataResults[0].Timestamps {
+		if endTime.Sub(*timeStamp) < 20*time.Second {
+			timestampCounter = i
+		} else {
+			break
+		}
+	}
+
+	if timestampCounter == -1 {
+		return nil, nil // no metrics were available in the last 2 tick intervals
+	}
+
+	steps := int(window.Nanoseconds() / spec.AutoscalingTickInterval.Nanoseconds())
+
+	endTimeStampCounter := libmath.MinInt(timestampCounter+steps, len(output.MetricDataResults[0].Timestamps))
+
+	values := output.MetricDataResults[0].Values[timestampCounter:endTimeStampCounter]
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	avg := 0.0
+	for _, val := range values {
+		avg += *val
+	}
+	avg = avg / float64(len(values))
+
+	return &avg, nil
+}
+
+func (*CloudWatchMetricsSource) P90Latency(apiName string, apiID string, window time.Duration) (*float64, error) {
+	p90Latency, _, err := getLatencyStat(apiName, apiID, "p90", window)
+	return p90Latency, err
+}
+
+func (*CloudWatchMetricsSource) RequestRate(apiName string, apiID string, window time.Duration) (*float64, error) {
+	requestCount, period, err := getLatencyStat(apiName, apiID, "SampleCount", window)
+	if err != nil || requestCount == nil {
+		return nil, err
+	}
+	requestRate := *requestCount / period.Seconds()
+	return &requestRate, nil
+}
+
+// the latency metric is published by the API at standard resolution, so the window is rounded up to the nearest minute,
+// and ends operator.CloudWatchIngestionDelay ago since the most recent minutes usually haven't been ingested yet
+func getLatencyStat(apiName string, apiID string, stat string, window time.Duration) (*float64, time.Duration, error) {
+	period := time.Duration(math.Ceil(window.Minutes())) * time.Minute
+	endTime := time.Now().Add(-operator.CloudWatchIngestionDelay).Truncate(time.Minute)
+	startTime := endTime.Add(-period)
+	metricsDataQuery := cloudwatch.GetMetricDataInput{
+		EndTime:   &endTime,
+		StartTime: &startTime,
+		MetricDataQueries: []*cloudwatch.MetricDataQuery{
+			{
+				Id:    aws.String("latency"),
+				Label: aws.String("Latency"),
+				MetricStat: &cloudwatch.MetricStat{
+					Metric: &cloudwatch.Metric{
+						Namespace:  aws.String(config.Cluster.ClusterName),
+						MetricName: aws.String("Latency"),
+						Dimensions: []*cloudwatch.Dimension{
+							{
+								Name:  aws.String("APIName"),
+								Value: aws.String(apiName),
+							},
+							{
+								Name:  aws.String("APIID"),
+								Value: aws.String(apiID),
+							},
+							{
+								Name:  aws.String("metric_type"),
+								Value: aws.String("histogram"),
+							},
+						},
+					},
+					Stat:   aws.String(stat),
+					Period: aws.Int64(int64(period.Seconds())),
+				},
+			},
+		},
+	}
+
+	output, err := config.AWS.CloudWatch().GetMetricData(&metricsDataQuery)
+	if err != nil {
+		return nil, period, err
+	}
+	if len(output.MetricDataResults) == 0 || len(output.MetricDataResults[0].Values) == 0 {
+		return nil, period, nil
+	}
+
+	return output.MetricDataResults[0].Values[0], period, nil
+}
+
+// PrometheusMetricsSource queries the in-flight metric from a Prometheus server's HTTP API
+type PrometheusMetricsSource struct {
+	URL    string
+	Client *http.Client
+}
+
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (source *PrometheusMetricsSource) AvgInFlight(apiName string, window time.Duration) (*float64, error) {
+	// sum across replicas at each tick, then average the sums over the window (equivalent to the CloudWatch query)
+	return source.query("in-flight requests", fmt.Sprintf(`avg_over_time(sum(cortex_in_flight_requests{api_name="%s"})[%ds:%ds])`, apiName, int64(window.Seconds()), int64(spec.AutoscalingTickInterval.Seconds())))
+}
+
+// latency and request rate are read from the metrics exported by the istio sidecars, so prometheus must be configured to scrape them
+func (source *PrometheusMetricsSource) P90Latency(apiName string, apiID string, window time.Duration) (*float64, error) {
+	return source.query("p90 latency", fmt.Sprintf(`histogram_quantile(0.9, sum(rate(istio_request_duration_milliseconds_bucket{destination_workload="%s",reporter="destination"}[%ds])) by (le))`, operator.K8sName(apiName), int64(window.Seconds())))
+}
+
+func (source *PrometheusMetricsSource) RequestRate(apiName string, apiID string, window time.Duration) (*float64, error) {
+	return source.query("request rate", fmt.Sprintf(`sum(rate(istio_requests_total{destination_workload="%s",reporter="destination"}[%ds]))`, operator.K8sName(apiName), int64(window.Seconds())))
+}
+
+// Returns the value of a query which evaluates to a single sample, or nil if it has no result (metricName is used in error messages)
+func (source *PrometheusMetricsSource) query(metricName string, query string) (*float64, error) {
+	client := source.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	queryURL := strings.TrimSuffix(source.URL, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+
+	response, err := client.Get(queryURL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer response.Body.Close()
+
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var queryResponse prometheusQueryResponse
+	if err := json.Unmarshal(bodyBytes, &queryResponse); err != nil {
+		return nil, ErrorPrometheusQuery(source.URL, metricName, strings.TrimSpace(string(bodyBytes)))
+	}
+	if response.StatusCode != http.StatusOK || queryResponse.Status != "success" {
+		return nil, ErrorPrometheusQuery(source.URL, metricName, queryResponse.Error)
+	}
+
+	if len(queryResponse.Data.Result) == 0 || len(queryResponse.Data.Result[0].Value) != 2 {
+		return nil, nil
+	}
+
+	valueStr, ok := queryResponse.Data.Result[0].Value[1].(string)
+	if !ok {
+		return nil, ErrorPrometheusQuery(source.URL, metricName, "unexpected value format")
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return nil, ErrorPrometheusQuery(source.URL, metricName, err.Error())
+	}
+	if math.IsNaN(value) {
+		return nil, nil // e.g. a quantile of a histogram with no observations
+	}
+
+	return &value, nil
+}
@@ -146,6 +146,10 @@ func addPodToReplicaCounts(pod *kcore.Pod, deployment *kapps.Deployment, counts
 }
 
 func getStatusCode(counts *status.ReplicaCounts, minReplicas int32) status.Code {
+	if counts.Requested == 0 {
+		return status.ScaledToZero
+	}
+
 	if counts.Updated.Ready >= counts.Requested {
 		return status.Live
 	}
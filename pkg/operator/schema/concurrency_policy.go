@@ -0,0 +1,81 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+// ConcurrencyPolicy determines what a scheduled job does when it is triggered while a job it previously submitted is still in progress
+type ConcurrencyPolicy int
+
+const (
+	UnknownConcurrencyPolicy ConcurrencyPolicy = iota
+	AllowConcurrencyPolicy
+	ForbidConcurrencyPolicy
+	ReplaceConcurrencyPolicy
+)
+
+var _concurrencyPolicies = []string{
+	"unknown",
+	"allow",
+	"forbid",
+	"replace",
+}
+
+func ConcurrencyPolicyFromString(s string) ConcurrencyPolicy {
+	for i := 0; i < len(_concurrencyPolicies); i++ {
+		if s == _concurrencyPolicies[i] {
+			return ConcurrencyPolicy(i)
+		}
+	}
+	return UnknownConcurrencyPolicy
+}
+
+func ConcurrencyPolicyStrings() []string {
+	return _concurrencyPolicies[1:]
+}
+
+func (t ConcurrencyPolicy) String() string {
+	return _concurrencyPolicies[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t ConcurrencyPolicy) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *ConcurrencyPolicy) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_concurrencyPolicies); i++ {
+		if enum == _concurrencyPolicies[i] {
+			*t = ConcurrencyPolicy(i)
+			return nil
+		}
+	}
+
+	*t = UnknownConcurrencyPolicy
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *ConcurrencyPolicy) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t ConcurrencyPolicy) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
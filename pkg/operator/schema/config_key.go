@@ -27,4 +27,17 @@ const (
 	IncludesKey       = "includes"
 	ExcludesKey       = "excludes"
 	WorkersKey        = "workers"
+	MaxWorkersKey     = "max_workers"
+	MaxRetriesKey     = "max_retries"
+	ResultSinkKey     = "result_sink"
+	S3PathKey         = "s3_path"
+	FormatKey         = "format"
+	DependsOnKey      = "depends_on"
+	HeaderKey         = "header"
+
+	// Scheduled Job Submission
+	NameKey              = "name"
+	ScheduleKey          = "schedule"
+	ConcurrencyPolicyKey = "concurrency_policy"
+	JobSubmissionKey     = "job_submission"
 )
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+// DelimitedFilesFormat is the format of the files whose contents are enqueued by a job submission's delimited_files
+type DelimitedFilesFormat int
+
+const (
+	UnknownDelimitedFilesFormat DelimitedFilesFormat = iota
+	JSONLDelimitedFilesFormat
+	CSVDelimitedFilesFormat
+	ParquetDelimitedFilesFormat
+)
+
+var _delimitedFilesFormats = []string{
+	"unknown",
+	"jsonl",
+	"csv",
+	"parquet",
+}
+
+func DelimitedFilesFormatFromString(s string) DelimitedFilesFormat {
+	for i := 0; i < len(_delimitedFilesFormats); i++ {
+		if s == _delimitedFilesFormats[i] {
+			return DelimitedFilesFormat(i)
+		}
+	}
+	return UnknownDelimitedFilesFormat
+}
+
+func DelimitedFilesFormatStrings() []string {
+	return _delimitedFilesFormats[1:]
+}
+
+func (t DelimitedFilesFormat) String() string {
+	return _delimitedFilesFormats[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t DelimitedFilesFormat) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *DelimitedFilesFormat) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_delimitedFilesFormats); i++ {
+		if enum == _delimitedFilesFormats[i] {
+			*t = DelimitedFilesFormat(i)
+			return nil
+		}
+	}
+
+	*t = UnknownDelimitedFilesFormat
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *DelimitedFilesFormat) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t DelimitedFilesFormat) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
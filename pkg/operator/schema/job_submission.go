@@ -40,7 +40,20 @@ type FilePathLister struct {
 
 type DelimitedFiles struct {
 	S3Lister
-	BatchSize int `json:"batch_size"`
+	BatchSize int    `json:"batch_size"`
+	Format    string `json:"format"` // jsonl (default), csv, or parquet
+	Header    *bool  `json:"header"` // csv only; whether the first row of each file contains the column names (default true)
+}
+
+func (delimitedFiles *DelimitedFiles) GetFormat() DelimitedFilesFormat {
+	if delimitedFiles.Format == "" {
+		return JSONLDelimitedFilesFormat
+	}
+	return DelimitedFilesFormatFromString(delimitedFiles.Format)
+}
+
+func (delimitedFiles *DelimitedFiles) HasHeader() bool {
+	return delimitedFiles.Header == nil || *delimitedFiles.Header
 }
 
 type JobSubmission struct {
@@ -48,4 +61,5 @@ type JobSubmission struct {
 	ItemList       *ItemList       `json:"item_list"`
 	FilePathLister *FilePathLister `json:"file_path_lister"`
 	DelimitedFiles *DelimitedFiles `json:"delimited_files"`
+	DependsOn      []spec.JobKey   `json:"depends_on"` // the api name defaults to the api the job is submitted to
 }
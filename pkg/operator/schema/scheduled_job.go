@@ -0,0 +1,52 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"time"
+)
+
+// ScheduledJobSubmission creates (or updates) a scheduled job, which submits a job from a template on a cron schedule
+type ScheduledJobSubmission struct {
+	Name              string        `json:"name"`
+	Schedule          string        `json:"schedule"`           // cron expression, evaluated in UTC
+	ConcurrencyPolicy string        `json:"concurrency_policy"` // allow (default), forbid, or replace
+	JobSubmission     JobSubmission `json:"job_submission"`
+}
+
+type ScheduledJob struct {
+	Name              string            `json:"name"`
+	APIName           string            `json:"api_name"`
+	Schedule          string            `json:"schedule"`
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrency_policy"`
+	JobSubmission     JobSubmission     `json:"job_submission"`
+	Paused            bool              `json:"paused"`
+	CreatedTime       time.Time         `json:"created_time"`
+	LastScheduleTime  *time.Time        `json:"last_schedule_time"`
+	History           []ScheduledJobRun `json:"history"` // most recent last
+}
+
+// ScheduledJobRun is a time at which a scheduled job was triggered
+type ScheduledJobRun struct {
+	ScheduledTime time.Time `json:"scheduled_time"`
+	JobID         string    `json:"job_id"`  // empty if a job wasn't submitted
+	Message       string    `json:"message"` // e.g. why a job wasn't submitted, or which jobs were replaced
+}
+
+type GetScheduledJobsResponse struct {
+	ScheduledJobs []ScheduledJob `json:"scheduled_jobs"`
+}
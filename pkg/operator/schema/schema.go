@@ -17,6 +17,9 @@ limitations under the License.
 package schema
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/cortexlabs/cortex/pkg/types/clusterconfig"
 	"github.com/cortexlabs/cortex/pkg/types/metrics"
 	"github.com/cortexlabs/cortex/pkg/types/spec"
@@ -49,8 +52,23 @@ type DeployResult struct {
 	API     spec.API
 	Message string
 	Error   string
+	Plan    *DeployPlan // only set for dry runs
+}
+
+// DeployPlan describes the changes which a deployment would make to an API
+type DeployPlan struct {
+	Action DeployAction     `json:"action"`
+	Diff   []spec.FieldDiff `json:"diff"` // only set for updates
 }
 
+type DeployAction string
+
+const (
+	CreateDeployAction    DeployAction = "create"
+	UpdateDeployAction    DeployAction = "update"
+	UnchangedDeployAction DeployAction = "unchanged"
+)
+
 type GetAPIsResponse struct {
 	SyncAPIs     []SyncAPI     `json:"sync_apis"`
 	BatchAPIs    []BatchAPI    `json:"batch_apis"`
@@ -63,6 +81,7 @@ type SyncAPI struct {
 	Metrics      metrics.Metrics `json:"metrics"`
 	Endpoint     string          `json:"endpoint"`
 	DashboardURL string          `json:"dashboard_url"`
+	Rollout      *status.Rollout `json:"rollout"`
 }
 
 type APISplitter struct {
@@ -83,9 +102,73 @@ type BatchAPI struct {
 }
 
 type GetJobResponse struct {
-	APISpec   spec.API         `json:"api_spec"`
-	JobStatus status.JobStatus `json:"job_status"`
-	Endpoint  string           `json:"endpoint"`
+	APISpec        spec.API             `json:"api_spec"`
+	JobStatus      status.JobStatus     `json:"job_status"`
+	Endpoint       string               `json:"endpoint"`
+	ResultManifest *spec.ResultManifest `json:"result_manifest"` // only present if the job has a result sink and has completed
+	JobProgress    *metrics.JobProgress `json:"job_progress"`    // only present if the job is running
+}
+
+type ListJobsResponse struct {
+	JobStatuses []status.JobStatus `json:"job_statuses"`
+	NextCursor  string             `json:"next_cursor,omitempty"` // empty if there are no more jobs
+}
+
+type GetFailedBatchesResponse struct {
+	JobKey  spec.JobKey   `json:"job_key"`
+	Batches []FailedBatch `json:"batches"`
+}
+
+// FailedBatch is a batch which was moved to its job's dead-letter queue after failing on every attempt
+type FailedBatch struct {
+	BatchID  string          `json:"batch_id"`
+	Payload  json.RawMessage `json:"payload"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	FailedAt *time.Time      `json:"failed_at"`
+}
+
+type GetAutoscalingResponse struct {
+	APIName string           `json:"api_name"`
+	Ticks   []AutoscalerTick `json:"ticks"` // oldest first
+}
+
+// AutoscalerTick records the inputs and outputs of a single autoscaling decision (fields which weren't reached are nil)
+type AutoscalerTick struct {
+	Timestamp                   time.Time `json:"timestamp"`
+	CurrentReplicas             int32     `json:"current_replicas"`
+	AvgInFlight                 *float64  `json:"avg_in_flight"`
+	P90Latency                  *float64  `json:"p90_latency"`
+	RequestRate                 *float64  `json:"request_rate"`
+	RawRecommendation           *float64  `json:"raw_recommendation"`
+	WithinTolerance             bool      `json:"within_tolerance"`
+	DownscaleFactorFloor        *int32    `json:"downscale_factor_floor"`
+	UpscaleFactorCeil           *int32    `json:"upscale_factor_ceil"`
+	Recommendation              *int32    `json:"recommendation"`
+	DownscaleStabilizationFloor *int32    `json:"downscale_stabilization_floor"`
+	UpscaleStabilizationCeil    *int32    `json:"upscale_stabilization_ceil"`
+	ScheduleMinReplicas         *int32    `json:"schedule_min_replicas"`
+	ScheduleMaxReplicas         *int32    `json:"schedule_max_replicas"`
+	Request                     *int32    `json:"request"`
+	Message                     string    `json:"message"`
+}
+
+type GetHistoryResponse struct {
+	APIName string       `json:"api_name"`
+	History []APIVersion `json:"history"` // oldest first
+}
+
+// APIVersion records a deployment of an API; the spec of each version remains in the bucket so that it can be rolled back to
+type APIVersion struct {
+	APIID         string          `json:"api_id"`
+	ProjectID     string          `json:"project_id"`
+	Kind          userconfig.Kind `json:"kind"`
+	CortexVersion string          `json:"cortex_version"`
+	DeployedAt    time.Time       `json:"deployed_at"`
+}
+
+type RollbackResponse struct {
+	Message string `json:"message"`
 }
 
 type DeleteResponse struct {
@@ -66,6 +66,11 @@ type Config struct {
 	APILoadBalancerScheme      LoadBalancerScheme `json:"api_load_balancer_scheme" yaml:"api_load_balancer_scheme"`
 	OperatorLoadBalancerScheme LoadBalancerScheme `json:"operator_load_balancer_scheme" yaml:"operator_load_balancer_scheme"`
 	APIGatewaySetting          APIGatewaySetting  `json:"api_gateway" yaml:"api_gateway"`
+	AutoscalerMetricsSource    MetricsSource      `json:"autoscaler_metrics_source" yaml:"autoscaler_metrics_source"`
+	PrometheusURL              *string            `json:"prometheus_url" yaml:"prometheus_url"`
+	BatchMaxConcurrentJobs     *int               `json:"batch_max_concurrent_jobs" yaml:"batch_max_concurrent_jobs"`
+	BatchMaxConcurrentWorkers  *int               `json:"batch_max_concurrent_workers" yaml:"batch_max_concurrent_workers"`
+	BatchJobStateStore         JobStateStore      `json:"batch_job_state_store" yaml:"batch_job_state_store"`
 	Telemetry                  bool               `json:"telemetry" yaml:"telemetry"`
 	ImageOperator              string             `json:"image_operator" yaml:"image_operator"`
 	ImageManager               string             `json:"image_manager" yaml:"image_manager"`
@@ -328,6 +333,46 @@ var UserValidation = &cr.StructValidation{
 				return APIGatewaySettingFromString(str), nil
 			},
 		},
+		{
+			StructField: "AutoscalerMetricsSource",
+			StringValidation: &cr.StringValidation{
+				AllowedValues: MetricsSourceStrings(),
+				Default:       CloudWatchMetricsSource.String(),
+			},
+			Parser: func(str string) (interface{}, error) {
+				return MetricsSourceFromString(str), nil
+			},
+		},
+		{
+			StructField: "PrometheusURL",
+			StringPtrValidation: &cr.StringPtrValidation{
+				AllowExplicitNull: true,
+			},
+		},
+		{
+			StructField: "BatchMaxConcurrentJobs",
+			IntPtrValidation: &cr.IntPtrValidation{
+				AllowExplicitNull: true,
+				GreaterThan:       pointer.Int(0),
+			},
+		},
+		{
+			StructField: "BatchMaxConcurrentWorkers",
+			IntPtrValidation: &cr.IntPtrValidation{
+				AllowExplicitNull: true,
+				GreaterThan:       pointer.Int(0),
+			},
+		},
+		{
+			StructField: "BatchJobStateStore",
+			StringValidation: &cr.StringValidation{
+				AllowedValues: JobStateStoreStrings(),
+				Default:       S3JobStateStore.String(),
+			},
+			Parser: func(str string) (interface{}, error) {
+				return JobStateStoreFromString(str), nil
+			},
+		},
 		{
 			StructField: "ImageOperator",
 			StringValidation: &cr.StringValidation{
@@ -540,6 +585,10 @@ func (cc *Config) Validate(awsClient *aws.Client) error {
 		return ErrorNATRequiredWithPrivateSubnetVisibility()
 	}
 
+	if cc.AutoscalerMetricsSource == PrometheusMetricsSource && cc.PrometheusURL == nil {
+		return ErrorPrometheusURLRequired()
+	}
+
 	if cc.Bucket == "" {
 		accountID, _, err := awsClient.GetCachedAccountID()
 		if err != nil {
@@ -1081,6 +1130,17 @@ func (cc *Config) UserTable() table.KeyValuePairs {
 	items.Add(APILoadBalancerSchemeUserKey, cc.APILoadBalancerScheme)
 	items.Add(OperatorLoadBalancerSchemeUserKey, cc.OperatorLoadBalancerScheme)
 	items.Add(APIGatewaySettingUserKey, cc.APIGatewaySetting)
+	items.Add(AutoscalerMetricsSourceUserKey, cc.AutoscalerMetricsSource)
+	if cc.PrometheusURL != nil {
+		items.Add(PrometheusURLUserKey, *cc.PrometheusURL)
+	}
+	if cc.BatchMaxConcurrentJobs != nil {
+		items.Add(BatchMaxConcurrentJobsUserKey, *cc.BatchMaxConcurrentJobs)
+	}
+	if cc.BatchMaxConcurrentWorkers != nil {
+		items.Add(BatchMaxConcurrentWorkersUserKey, *cc.BatchMaxConcurrentWorkers)
+	}
+	items.Add(BatchJobStateStoreUserKey, cc.BatchJobStateStore)
 	items.Add(TelemetryUserKey, cc.Telemetry)
 	items.Add(ImageOperatorUserKey, cc.ImageOperator)
 	items.Add(ImageManagerUserKey, cc.ImageManager)
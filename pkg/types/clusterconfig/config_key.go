@@ -43,6 +43,11 @@ const (
 	APILoadBalancerSchemeKey               = "api_load_balancer_scheme"
 	OperatorLoadBalancerSchemeKey          = "operator_load_balancer_scheme"
 	APIGatewaySettingKey                   = "api_gateway"
+	AutoscalerMetricsSourceKey             = "autoscaler_metrics_source"
+	PrometheusURLKey                       = "prometheus_url"
+	BatchMaxConcurrentJobsKey              = "batch_max_concurrent_jobs"
+	BatchMaxConcurrentWorkersKey           = "batch_max_concurrent_workers"
+	BatchJobStateStoreKey                  = "batch_job_state_store"
 	TelemetryKey                           = "telemetry"
 	ImageOperatorKey                       = "image_operator"
 	ImageManagerKey                        = "image_manager"
@@ -87,6 +92,11 @@ const (
 	APILoadBalancerSchemeUserKey               = "api load balancer scheme"
 	OperatorLoadBalancerSchemeUserKey          = "operator load balancer scheme"
 	APIGatewaySettingUserKey                   = "api gateway"
+	AutoscalerMetricsSourceUserKey             = "autoscaler metrics source"
+	PrometheusURLUserKey                       = "prometheus url"
+	BatchMaxConcurrentJobsUserKey              = "batch max concurrent jobs"
+	BatchMaxConcurrentWorkersUserKey           = "batch max concurrent workers"
+	BatchJobStateStoreUserKey                  = "batch job state store"
 	TelemetryUserKey                           = "telemetry"
 	ImageOperatorUserKey                       = "operator image"
 	ImageManagerUserKey                        = "manager image"
@@ -54,6 +54,7 @@ const (
 	ErrIOPSTooLarge                           = "clusterconfig.iops_too_large"
 	ErrCantOverrideDefaultTag                 = "clusterconfig.cant_override_default_tag"
 	ErrSSLCertificateARNNotFound              = "clusterconfig.ssl_certificate_arn_not_found"
+	ErrPrometheusURLRequired                  = "clusterconfig.prometheus_url_required"
 )
 
 func ErrorInvalidRegion(region string) error {
@@ -249,3 +250,10 @@ func ErrorSSLCertificateARNNotFound(sslCertificateARN string, region string) err
 		Message: fmt.Sprintf("unable to find the specified ssl certificate in region %s: %s", region, sslCertificateARN),
 	})
 }
+
+func ErrorPrometheusURLRequired() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrPrometheusURLRequired,
+		Message: fmt.Sprintf("%s must be specified when `%s: %s` is set", PrometheusURLKey, AutoscalerMetricsSourceKey, PrometheusMetricsSource),
+	})
+}
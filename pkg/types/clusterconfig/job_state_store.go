@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+type JobStateStore int
+
+const (
+	UnknownJobStateStore JobStateStore = iota
+	S3JobStateStore
+	ConfigMapJobStateStore
+)
+
+var _jobStateStores = []string{
+	"unknown",
+	"s3",
+	"configmap",
+}
+
+func JobStateStoreFromString(s string) JobStateStore {
+	for i := 0; i < len(_jobStateStores); i++ {
+		if s == _jobStateStores[i] {
+			return JobStateStore(i)
+		}
+	}
+	return UnknownJobStateStore
+}
+
+func JobStateStoreStrings() []string {
+	return _jobStateStores[1:]
+}
+
+func (t JobStateStore) String() string {
+	return _jobStateStores[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t JobStateStore) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *JobStateStore) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_jobStateStores); i++ {
+		if enum == _jobStateStores[i] {
+			*t = JobStateStore(i)
+			return nil
+		}
+	}
+
+	*t = UnknownJobStateStore
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *JobStateStore) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t JobStateStore) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
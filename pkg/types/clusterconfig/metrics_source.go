@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterconfig
+
+type MetricsSource int
+
+const (
+	UnknownMetricsSource MetricsSource = iota
+	CloudWatchMetricsSource
+	PrometheusMetricsSource
+)
+
+var _metricsSources = []string{
+	"unknown",
+	"cloudwatch",
+	"prometheus",
+}
+
+func MetricsSourceFromString(s string) MetricsSource {
+	for i := 0; i < len(_metricsSources); i++ {
+		if s == _metricsSources[i] {
+			return MetricsSource(i)
+		}
+	}
+	return UnknownMetricsSource
+}
+
+func MetricsSourceStrings() []string {
+	return _metricsSources[1:]
+}
+
+func (t MetricsSource) String() string {
+	return _metricsSources[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t MetricsSource) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *MetricsSource) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_metricsSources); i++ {
+		if enum == _metricsSources[i] {
+			*t = MetricsSource(i)
+			return nil
+		}
+	}
+
+	*t = UnknownMetricsSource
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *MetricsSource) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t MetricsSource) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+)
+
+type JobProgress struct {
+	BatchesPerSecond          float64    `json:"batches_per_second"`            // rolling throughput over the most recent completed batches
+	BatchesPerSecondPerWorker *float64   `json:"batches_per_second_per_worker"` // nil if no workers are running
+	RemainingBatches          int        `json:"remaining_batches"`
+	EstimatedCompletionTime   *time.Time `json:"estimated_completion_time"` // nil if no batches were completed recently
+}
@@ -115,6 +115,14 @@ func MetadataRoot(apiName string) string {
 	)
 }
 
+func HistoryKey(apiName string) string {
+	return filepath.Join(
+		"apis",
+		apiName,
+		"history.json",
+	)
+}
+
 func ProjectKey(projectID string) string {
 	return filepath.Join(
 		"projects",
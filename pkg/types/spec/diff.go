@@ -0,0 +1,174 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+)
+
+// FieldDiff is a change to a single field of an API's configuration ("-" if the field isn't set; unset and missing fields are equivalent)
+type FieldDiff struct {
+	Field string `json:"field"` // e.g. "predictor.config.threshold" or "autoscaling.schedules[0].cron"
+	Prev  string `json:"prev"`
+	New   string `json:"new"`
+}
+
+// DiffAPIConfigs lists the fields which differ between two configurations of an API, in the order in which they are declared
+func DiffAPIConfigs(prev *userconfig.API, new *userconfig.API) []FieldDiff {
+	prevFields := flattenAPIConfig(prev)
+	newFields := flattenAPIConfig(new)
+
+	prevValues := make(map[string]string, len(prevFields))
+	for _, field := range prevFields {
+		prevValues[field.name] = field.value
+	}
+	newValues := make(map[string]string, len(newFields))
+	for _, field := range newFields {
+		newValues[field.name] = field.value
+	}
+
+	var diffs []FieldDiff
+	for _, field := range newFields {
+		prevValue, ok := prevValues[field.name]
+		if !ok {
+			prevValue = "-"
+		}
+		if prevValue != field.value {
+			diffs = append(diffs, FieldDiff{Field: field.name, Prev: prevValue, New: field.value})
+		}
+	}
+	for _, field := range prevFields {
+		if _, ok := newValues[field.name]; !ok && field.value != "-" {
+			diffs = append(diffs, FieldDiff{Field: field.name, Prev: field.value, New: "-"})
+		}
+	}
+
+	return diffs
+}
+
+type flatField struct {
+	name  string
+	value string
+}
+
+var _stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// the api's name and kind are excluded, since they can't be changed by an update
+func flattenAPIConfig(api *userconfig.API) []flatField {
+	var fields []flatField
+	if api == nil {
+		return fields
+	}
+
+	apiValue := reflect.ValueOf(*api)
+	apiType := apiValue.Type()
+	for i := 0; i < apiType.NumField(); i++ {
+		structField := apiType.Field(i)
+		name := fieldName(structField)
+		if structField.Anonymous || name == "" {
+			continue
+		}
+		flattenValue(name, apiValue.Field(i), &fields)
+	}
+
+	return fields
+}
+
+func flattenValue(name string, value reflect.Value, fields *[]flatField) {
+	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			*fields = append(*fields, flatField{name: name, value: "-"})
+			return
+		}
+		if value.Kind() == reflect.Ptr && value.Type().Implements(_stringerType) {
+			*fields = append(*fields, flatField{name: name, value: value.Interface().(fmt.Stringer).String()})
+			return
+		}
+		flattenValue(name, value.Elem(), fields)
+		return
+	}
+
+	if value.Type().Implements(_stringerType) {
+		*fields = append(*fields, flatField{name: name, value: value.Interface().(fmt.Stringer).String()})
+		return
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			childName := fieldName(valueType.Field(i))
+			if childName == "" {
+				continue
+			}
+			flattenValue(name+"."+childName, value.Field(i), fields)
+		}
+	case reflect.Map:
+		if value.Len() == 0 {
+			*fields = append(*fields, flatField{name: name, value: "-"})
+			return
+		}
+		keys := make([]string, 0, value.Len())
+		keyValues := make(map[string]reflect.Value, value.Len())
+		for _, key := range value.MapKeys() {
+			keyStr := fmt.Sprint(key.Interface())
+			keys = append(keys, keyStr)
+			keyValues[keyStr] = key
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			flattenValue(name+"."+key, value.MapIndex(keyValues[key]), fields)
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Len() == 0 {
+			*fields = append(*fields, flatField{name: name, value: "-"})
+			return
+		}
+		// lists of scalars are shown on a single line
+		elemKind := value.Type().Elem().Kind()
+		if elemKind != reflect.Ptr && elemKind != reflect.Struct && elemKind != reflect.Map && elemKind != reflect.Slice && elemKind != reflect.Interface {
+			elems := make([]string, value.Len())
+			for i := 0; i < value.Len(); i++ {
+				elems[i] = fmt.Sprint(value.Index(i).Interface())
+			}
+			*fields = append(*fields, flatField{name: name, value: "[" + strings.Join(elems, ", ") + "]"})
+			return
+		}
+		for i := 0; i < value.Len(); i++ {
+			flattenValue(fmt.Sprintf("%s[%d]", name, i), value.Index(i), fields)
+		}
+	default:
+		*fields = append(*fields, flatField{name: name, value: fmt.Sprint(value.Interface())})
+	}
+}
+
+// returns "" for fields which aren't part of the user's configuration
+func fieldName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		return ""
+	}
+	yamlTag := strings.TrimSpace(strings.Split(field.Tag.Get("yaml"), ",")[0])
+	if yamlTag == "-" || yamlTag == "" {
+		return ""
+	}
+	return yamlTag
+}
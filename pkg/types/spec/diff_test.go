@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cortexlabs/cortex/pkg/lib/k8s"
+	"github.com/cortexlabs/cortex/pkg/lib/pointer"
+	"github.com/cortexlabs/cortex/pkg/types/userconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffAPIConfigs(t *testing.T) {
+	prev := &userconfig.API{
+		Predictor: &userconfig.Predictor{
+			Type:   userconfig.PythonPredictorType,
+			Path:   "predictor.py",
+			Config: map[string]interface{}{"threshold": 0.5, "labels": []interface{}{"a", "b"}},
+		},
+		Compute: &userconfig.Compute{CPU: k8s.NewQuantity(1), GPU: 0},
+		Autoscaling: &userconfig.Autoscaling{
+			MinReplicas: 1,
+			Window:      time.Minute,
+		},
+		Networking: &userconfig.Networking{Endpoint: pointer.String("/my-api")},
+	}
+
+	new := &userconfig.API{
+		Predictor: &userconfig.Predictor{
+			Type:   userconfig.PythonPredictorType,
+			Path:   "predictor.py",
+			Config: map[string]interface{}{"threshold": 0.7, "labels": []interface{}{"a", "b"}},
+		},
+		Compute: &userconfig.Compute{CPU: k8s.NewQuantity(1), GPU: 1},
+		Autoscaling: &userconfig.Autoscaling{
+			MinReplicas: 1,
+			Window:      2 * time.Minute,
+			Schedules:   []*userconfig.AutoscalingSchedule{{Cron: "0 9 * * *", Duration: time.Hour}},
+		},
+		Networking: &userconfig.Networking{Endpoint: pointer.String("/my-api")},
+	}
+
+	require.Empty(t, DiffAPIConfigs(prev, prev))
+
+	require.Equal(t, []FieldDiff{
+		{Field: "predictor.config.threshold", Prev: "0.5", New: "0.7"},
+		{Field: "compute.gpu", Prev: "0", New: "1"},
+		{Field: "autoscaling.window", Prev: "1m0s", New: "2m0s"},
+		{Field: "autoscaling.schedules[0].cron", Prev: "-", New: "0 9 * * *"},
+		{Field: "autoscaling.schedules[0].duration", Prev: "-", New: "1h0m0s"},
+	}, DiffAPIConfigs(prev, new))
+
+	require.Empty(t, DiffAPIConfigs(&userconfig.API{Autoscaling: &userconfig.Autoscaling{}}, &userconfig.API{Autoscaling: &userconfig.Autoscaling{Schedules: []*userconfig.AutoscalingSchedule{}}}))
+}
@@ -41,8 +41,13 @@ const (
 	ErrMinReplicasGreaterThanMax            = "spec.min_replicas_greater_than_max"
 	ErrInitReplicasGreaterThanMax           = "spec.init_replicas_greater_than_max"
 	ErrInitReplicasLessThanMin              = "spec.init_replicas_less_than_min"
+	ErrScheduleMissingReplicas              = "spec.schedule_missing_replicas"
+	ErrScheduleReplicasOutOfRange           = "spec.schedule_replicas_out_of_range"
 	ErrInvalidSurgeOrUnavailable            = "spec.invalid_surge_or_unavailable"
 	ErrSurgeAndUnavailableBothZero          = "spec.surge_and_unavailable_both_zero"
+	ErrFieldNotSupportedByUpdateStrategy    = "spec.field_not_supported_by_update_strategy"
+	ErrInvalidCanaryStep                    = "spec.invalid_canary_step"
+	ErrCanaryStepsNotIncreasing             = "spec.canary_steps_not_increasing"
 	ErrFileNotFound                         = "spec.file_not_found"
 	ErrDirIsEmpty                           = "spec.dir_is_empty"
 	ErrMustBeRelativeProjectPath            = "spec.must_be_relative_project_path"
@@ -56,6 +61,8 @@ const (
 	ErrDuplicateModelNames                  = "spec.duplicate_model_names"
 	ErrFieldMustBeDefinedForPredictorType   = "spec.field_must_be_defined_for_predictor_type"
 	ErrFieldNotSupportedByPredictorType     = "spec.field_not_supported_by_predictor_type"
+	ErrFieldMustBeDefinedForPolicy          = "spec.field_must_be_defined_for_autoscaling_policy"
+	ErrFieldNotSupportedByPolicy            = "spec.field_not_supported_by_autoscaling_policy"
 	ErrNoAvailableNodeComputeLimit          = "spec.no_available_node_compute_limit"
 	ErrCortexPrefixedEnvVarNotAllowed       = "spec.cortex_prefixed_env_var_not_allowed"
 	ErrLocalPathNotSupportedByAWSProvider   = "spec.local_path_not_supported_by_aws_provider"
@@ -177,6 +184,20 @@ func ErrorInitReplicasLessThanMin(init int32, min int32) error {
 	})
 }
 
+func ErrorScheduleMissingReplicas() error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrScheduleMissingReplicas,
+		Message: fmt.Sprintf("at least one of %s or %s must be specified", userconfig.MinReplicasKey, userconfig.MaxReplicasKey),
+	})
+}
+
+func ErrorScheduleReplicasOutOfRange(key string, val int32, min int32, max int32) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrScheduleReplicasOutOfRange,
+		Message: fmt.Sprintf("%s (%d) must be between the api's %s and %s (%d and %d)", key, val, userconfig.MinReplicasKey, userconfig.MaxReplicasKey, min, max),
+	})
+}
+
 func ErrorInvalidSurgeOrUnavailable(val string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrInvalidSurgeOrUnavailable,
@@ -191,6 +212,27 @@ func ErrorSurgeAndUnavailableBothZero() error {
 	})
 }
 
+func ErrorFieldNotSupportedByUpdateStrategy(fieldKey string, updateStrategyType userconfig.UpdateStrategyType) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldNotSupportedByUpdateStrategy,
+		Message: fmt.Sprintf("%s is not a supported field when %s is %s", fieldKey, userconfig.TypeKey, updateStrategyType.String()),
+	})
+}
+
+func ErrorInvalidCanaryStep(step int32) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrInvalidCanaryStep,
+		Message: fmt.Sprintf("invalid step %d: each step is the percentage of traffic to route to the canary, and must be greater than 0 and less than 100", step),
+	})
+}
+
+func ErrorCanaryStepsNotIncreasing(steps []int32) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrCanaryStepsNotIncreasing,
+		Message: fmt.Sprintf("must be strictly increasing (got %s)", s.ObjFlatNoQuotes(steps)),
+	})
+}
+
 func ErrorFileNotFound(path string) error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrFileNotFound,
@@ -299,6 +341,20 @@ func ErrorFieldNotSupportedByPredictorType(fieldKey string, predictorType userco
 	})
 }
 
+func ErrorFieldMustBeDefinedForPolicy(fieldKey string, policy userconfig.AutoscalingPolicy) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldMustBeDefinedForPolicy,
+		Message: fmt.Sprintf("%s field must be defined when %s is %s", fieldKey, userconfig.PolicyKey, policy.String()),
+	})
+}
+
+func ErrorFieldNotSupportedByPolicy(fieldKey string, policy userconfig.AutoscalingPolicy) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrFieldNotSupportedByPolicy,
+		Message: fmt.Sprintf("%s is not a supported field when %s is %s", fieldKey, userconfig.PolicyKey, policy.String()),
+	})
+}
+
 func ErrorCortexPrefixedEnvVarNotAllowed() error {
 	return errors.WithStack(&errors.Error{
 		Kind:    ErrCortexPrefixedEnvVarNotAllowed,
@@ -50,17 +50,52 @@ func (j JobKey) K8sName() string {
 }
 
 type RuntimeJobConfig struct {
-	Workers int                    `json:"workers"`
-	Config  map[string]interface{} `json:"config"`
+	Workers    int                    `json:"workers"`     // the current number of workers, which can be increased up to max_workers while the job is running
+	MaxWorkers int                    `json:"max_workers"` // 0 if the job isn't autoscaled
+	MaxRetries int                    `json:"max_retries"` // number of times a failed batch is retried before it's moved to the dead-letter queue
+	Priority   int                    `json:"priority"`    // when the cluster's job quota has been reached, queued jobs with a higher priority are started first
+	ResultSink *ResultSink            `json:"result_sink"`
+	Config     map[string]interface{} `json:"config"`
+}
+
+func (c RuntimeJobConfig) IsAutoscaled() bool {
+	return c.MaxWorkers > 0
+}
+
+// ResultSink is where the workers write the outputs of the predictor (one object per batch)
+type ResultSink struct {
+	S3Path string       `json:"s3_path"` // s3://<bucket_name>/<prefix>
+	Format ResultFormat `json:"format"`
+}
+
+// e.g. s3://<bucket_name>/<prefix>/<job_id>/
+func (r ResultSink) JobS3Path(jobID string) string {
+	return s.EnsureSuffix(s.EnsureSuffix(r.S3Path, "/")+jobID, "/")
+}
+
+// e.g. s3://<bucket_name>/<prefix>/<job_id>/manifest.json
+func (r ResultSink) ManifestS3Path(jobID string) string {
+	return r.JobS3Path(jobID) + "manifest.json"
+}
+
+// ResultManifest lists the result objects written by a job's workers
+type ResultManifest struct {
+	JobKey
+	Format      ResultFormat `json:"format"`
+	Results     []string     `json:"results"` // s3 paths, sorted
+	CreatedTime time.Time    `json:"created_time"`
 }
 
 type Job struct {
 	JobKey
 	RuntimeJobConfig
-	APIID           string    `json:"api_id"`
-	SQSUrl          string    `json:"sqs_url"`
-	TotalBatchCount int       `json:"total_batch_count"`
-	StartTime       time.Time `json:"start_time"`
+	InitialWorkers   int       `json:"initial_workers"` // the number of workers which the job was started with (Workers may be increased while it's running)
+	APIID            string    `json:"api_id"`
+	SQSUrl           string    `json:"sqs_url"`
+	DeadLetterSQSUrl string    `json:"dead_letter_sqs_url"`
+	DependsOn        []JobKey  `json:"depends_on"` // jobs which must succeed before this job is enqueued
+	TotalBatchCount  int       `json:"total_batch_count"`
+	StartTime        time.Time `json:"start_time"`
 }
 
 func BatchAPIJobPrefix(apiName string) string {
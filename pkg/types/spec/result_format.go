@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+type ResultFormat int
+
+const (
+	UnknownResultFormat ResultFormat = iota
+	JSONLResultFormat
+	CSVResultFormat
+)
+
+var _resultFormats = []string{
+	"unknown",
+	"jsonl",
+	"csv",
+}
+
+func ResultFormatFromString(s string) ResultFormat {
+	for i := 0; i < len(_resultFormats); i++ {
+		if s == _resultFormats[i] {
+			return ResultFormat(i)
+		}
+	}
+	return UnknownResultFormat
+}
+
+func ResultFormatStrings() []string {
+	return _resultFormats[1:]
+}
+
+func (t ResultFormat) String() string {
+	return _resultFormats[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t ResultFormat) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *ResultFormat) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_resultFormats); i++ {
+		if enum == _resultFormats[i] {
+			*t = ResultFormat(i)
+			return nil
+		}
+	}
+
+	*t = UnknownResultFormat
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *ResultFormat) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t ResultFormat) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/lib/aws"
 	"github.com/cortexlabs/cortex/pkg/lib/cast"
 	cr "github.com/cortexlabs/cortex/pkg/lib/configreader"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
 	"github.com/cortexlabs/cortex/pkg/lib/docker"
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 	"github.com/cortexlabs/cortex/pkg/lib/files"
@@ -272,15 +273,13 @@ func networkingValidation(kind userconfig.Kind) *cr.StructFieldValidation {
 				return userconfig.APIGatewayTypeFromString(str), nil
 			},
 		},
-	}
-	if kind == userconfig.SyncAPIKind {
-		structFieldValidation = append(structFieldValidation, &cr.StructFieldValidation{
+		{
 			StructField: "LocalPort",
 			IntPtrValidation: &cr.IntPtrValidation{
 				GreaterThan:       pointer.Int(0),
 				LessThanOrEqualTo: pointer.Int(math.MaxUint16),
 			},
-		})
+		},
 	}
 	return &cr.StructFieldValidation{
 		StructField: "Networking",
@@ -351,8 +350,8 @@ func autoscalingValidation(provider types.ProviderType) *cr.StructFieldValidatio
 				{
 					StructField: "MinReplicas",
 					Int32Validation: &cr.Int32Validation{
-						Default:     1,
-						GreaterThan: pointer.Int32(0),
+						Default:              1,
+						GreaterThanOrEqualTo: pointer.Int32(0),
 					},
 				},
 				{
@@ -365,16 +364,43 @@ func autoscalingValidation(provider types.ProviderType) *cr.StructFieldValidatio
 				{
 					StructField:  "InitReplicas",
 					DefaultField: "MinReplicas",
+					DefaultFieldFunc: func(val interface{}) interface{} {
+						// APIs which can scale to zero still start with a replica
+						return libmath.MaxInt32(val.(int32), 1)
+					},
 					Int32Validation: &cr.Int32Validation{
 						GreaterThan: pointer.Int32(0),
 					},
 				},
+				{
+					StructField: "Policy",
+					StringValidation: &cr.StringValidation{
+						AllowedValues: userconfig.AutoscalingPolicyStrings(),
+						Default:       userconfig.TargetReplicaConcurrencyAutoscalingPolicy.String(),
+					},
+					Parser: func(str string) (interface{}, error) {
+						return userconfig.AutoscalingPolicyFromString(str), nil
+					},
+				},
 				{
 					StructField: "TargetReplicaConcurrency",
 					Float64PtrValidation: &cr.Float64PtrValidation{
 						GreaterThan: pointer.Float64(0),
 					},
 				},
+				{
+					StructField:         "TargetLatencyP90",
+					StringPtrValidation: &cr.StringPtrValidation{},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThan: pointer.Duration(libtime.MustParseDuration("0s")),
+					}),
+				},
+				{
+					StructField: "TargetRPSPerReplica",
+					Float64PtrValidation: &cr.Float64PtrValidation{
+						GreaterThan: pointer.Float64(0),
+					},
+				},
 				{
 					StructField: "MaxReplicaConcurrency",
 					Int64Validation: &cr.Int64Validation{
@@ -441,6 +467,55 @@ func autoscalingValidation(provider types.ProviderType) *cr.StructFieldValidatio
 						GreaterThanOrEqualTo: pointer.Float64(0),
 					},
 				},
+				{
+					StructField: "ScaleToZeroPeriod",
+					StringValidation: &cr.StringValidation{
+						Default: "10m",
+					},
+					Parser: cr.DurationParser(&cr.DurationValidation{
+						GreaterThanOrEqualTo: &AutoscalingTickInterval,
+					}),
+				},
+				{
+					StructField: "Schedules",
+					StructListValidation: &cr.StructListValidation{
+						Required:         false,
+						TreatNullAsEmpty: true,
+						StructValidation: &cr.StructValidation{
+							StructFieldValidations: []*cr.StructFieldValidation{
+								{
+									StructField: "Cron",
+									StringValidation: &cr.StringValidation{
+										Required:  true,
+										Validator: cronScheduleValidator,
+									},
+								},
+								{
+									StructField: "Duration",
+									StringValidation: &cr.StringValidation{
+										Required: true,
+									},
+									Parser: cr.DurationParser(&cr.DurationValidation{
+										GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1m")),
+										LessThanOrEqualTo:    pointer.Duration(libtime.MustParseDuration("168h")),
+									}),
+								},
+								{
+									StructField: "MinReplicas",
+									Int32PtrValidation: &cr.Int32PtrValidation{
+										GreaterThanOrEqualTo: pointer.Int32(0),
+									},
+								},
+								{
+									StructField: "MaxReplicas",
+									Int32PtrValidation: &cr.Int32PtrValidation{
+										GreaterThan: pointer.Int32(0),
+									},
+								},
+							},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -455,6 +530,16 @@ func updateStrategyValidation(provider types.ProviderType) *cr.StructFieldValida
 			DefaultNil:        defaultNil,
 			AllowExplicitNull: allowExplicitNull,
 			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField: "Type",
+					StringValidation: &cr.StringValidation{
+						AllowedValues: userconfig.UpdateStrategyTypeStrings(),
+						Default:       userconfig.RollingUpdateStrategyType.String(),
+					},
+					Parser: func(str string) (interface{}, error) {
+						return userconfig.UpdateStrategyTypeFromString(str), nil
+					},
+				},
 				{
 					StructField: "MaxSurge",
 					StringValidation: &cr.StringValidation{
@@ -471,9 +556,65 @@ func updateStrategyValidation(provider types.ProviderType) *cr.StructFieldValida
 						Validator: surgeOrUnavailableValidator,
 					},
 				},
+				{
+					StructField:      "Canary",
+					StructValidation: canaryValidation,
+				},
+			},
+		},
+	}
+}
+
+var canaryValidation = &cr.StructValidation{
+	DefaultNil:        true,
+	AllowExplicitNull: true,
+	StructFieldValidations: []*cr.StructFieldValidation{
+		{
+			StructField: "Steps",
+			Int32ListValidation: &cr.Int32ListValidation{
+				Default:   []int32{10, 50},
+				MinLength: 1,
+				Validator: canaryStepsValidator,
 			},
 		},
+		{
+			StructField: "StepDuration",
+			StringValidation: &cr.StringValidation{
+				Default: "5m",
+			},
+			Parser: cr.DurationParser(&cr.DurationValidation{
+				GreaterThanOrEqualTo: pointer.Duration(libtime.MustParseDuration("1m")),
+			}),
+		},
+		{
+			StructField: "MaxErrorRate",
+			Float64Validation: &cr.Float64Validation{
+				Default:              0.01,
+				GreaterThanOrEqualTo: pointer.Float64(0),
+				LessThanOrEqualTo:    pointer.Float64(1),
+			},
+		},
+		{
+			StructField:         "MaxLatency",
+			StringPtrValidation: &cr.StringPtrValidation{},
+			Parser: cr.DurationParser(&cr.DurationValidation{
+				GreaterThan: pointer.Duration(libtime.MustParseDuration("0s")),
+			}),
+		},
+	},
+}
+
+// steps are the percentages of traffic routed to the canary, and must be increasing
+func canaryStepsValidator(steps []int32) ([]int32, error) {
+	for i, step := range steps {
+		if step <= 0 || step >= 100 {
+			return nil, ErrorInvalidCanaryStep(step)
+		}
+		if i > 0 && step <= steps[i-1] {
+			return nil, ErrorCanaryStepsNotIncreasing(steps)
+		}
 	}
+	return steps, nil
 }
 
 func multiModelValidation() *cr.StructFieldValidation {
@@ -543,6 +684,13 @@ func serverSideBatchingValidation() *cr.StructFieldValidation {
 	}
 }
 
+func cronScheduleValidator(str string) (string, error) {
+	if _, err := cron.ParseSchedule(str); err != nil {
+		return "", err
+	}
+	return str, nil
+}
+
 func surgeOrUnavailableValidator(str string) (string, error) {
 	if strings.HasSuffix(str, "%") {
 		parsed, ok := s.ParseInt32(strings.TrimSuffix(str, "%"))
@@ -594,19 +742,11 @@ func ExtractAPIConfigs(configBytes []byte, provider types.ProviderType, configFi
 			kind := userconfig.KindFromString(kindString)
 			err = errors.Wrap(errors.FirstError(errs...), userconfig.IdentifyAPI(configFileName, name, kind, i))
 			switch provider {
-			case types.LocalProviderType:
-				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema for Sync API can be found at https://docs.cortex.dev/v/%s/deployments/syncapi/api-configuration", consts.CortexVersionMinor))
-			case types.AWSProviderType:
+			case types.LocalProviderType, types.AWSProviderType:
 				return nil, errors.Append(err, fmt.Sprintf("\n\napi configuration schema for:\n\nSync API can be found at https://docs.cortex.dev/v/%s/deployments/syncapi/api-configuration\nBatch API can be found at https://docs.cortex.dev/v/%s/deployments/batchapi/api-configuration\nAPI Splitter can be found at https://docs.cortex.dev/v/%s/deployments/syncapi/apisplitter", consts.CortexVersionMinor, consts.CortexVersionMinor, consts.CortexVersionMinor))
 			}
 		}
 
-		if resourceStruct.Kind == userconfig.BatchAPIKind || resourceStruct.Kind == userconfig.APISplitterKind {
-			if provider == types.LocalProviderType {
-				return nil, errors.Wrap(ErrorKindIsNotSupportedByProvider(resourceStruct.Kind, types.LocalProviderType), userconfig.IdentifyAPI(configFileName, resourceStruct.Name, resourceStruct.Kind, i))
-			}
-		}
-
 		errs = cr.Struct(&api, data, apiValidation(provider, resourceStruct))
 		if errors.HasError(errs) {
 			name, _ := data[userconfig.NameKey].(string)
@@ -651,7 +791,7 @@ func ValidateAPI(
 	}
 
 	if api.Autoscaling != nil { // should only be nil for local provider
-		if err := validateAutoscaling(api); err != nil {
+		if err := ValidateAutoscaling(api); err != nil {
 			return errors.Wrap(err, userconfig.AutoscalingKey)
 		}
 	}
@@ -1110,16 +1250,38 @@ func validatePythonPath(predictor *userconfig.Predictor, projectFiles ProjectFil
 	return nil
 }
 
-func validateAutoscaling(api *userconfig.API) error {
+// ValidateAutoscaling validates the autoscaling configuration of a SyncAPI and sets its defaults
+func ValidateAutoscaling(api *userconfig.API) error {
 	autoscaling := api.Autoscaling
 	predictor := api.Predictor
 
-	if autoscaling.TargetReplicaConcurrency == nil {
-		autoscaling.TargetReplicaConcurrency = pointer.Float64(float64(predictor.ProcessesPerReplica * predictor.ThreadsPerProcess))
+	if autoscaling.Policy != userconfig.TargetReplicaConcurrencyAutoscalingPolicy && autoscaling.TargetReplicaConcurrency != nil {
+		return ErrorFieldNotSupportedByPolicy(userconfig.TargetReplicaConcurrencyKey, autoscaling.Policy)
+	}
+	if autoscaling.Policy != userconfig.TargetLatencyP90AutoscalingPolicy && autoscaling.TargetLatencyP90 != nil {
+		return ErrorFieldNotSupportedByPolicy(userconfig.TargetLatencyP90Key, autoscaling.Policy)
+	}
+	if autoscaling.Policy != userconfig.TargetRPSPerReplicaAutoscalingPolicy && autoscaling.TargetRPSPerReplica != nil {
+		return ErrorFieldNotSupportedByPolicy(userconfig.TargetRPSPerReplicaKey, autoscaling.Policy)
 	}
 
-	if *autoscaling.TargetReplicaConcurrency > float64(autoscaling.MaxReplicaConcurrency) {
-		return ErrorConfigGreaterThanOtherConfig(userconfig.TargetReplicaConcurrencyKey, *autoscaling.TargetReplicaConcurrency, userconfig.MaxReplicaConcurrencyKey, autoscaling.MaxReplicaConcurrency)
+	switch autoscaling.Policy {
+	case userconfig.TargetReplicaConcurrencyAutoscalingPolicy:
+		if autoscaling.TargetReplicaConcurrency == nil {
+			autoscaling.TargetReplicaConcurrency = pointer.Float64(float64(predictor.ProcessesPerReplica * predictor.ThreadsPerProcess))
+		}
+
+		if *autoscaling.TargetReplicaConcurrency > float64(autoscaling.MaxReplicaConcurrency) {
+			return ErrorConfigGreaterThanOtherConfig(userconfig.TargetReplicaConcurrencyKey, *autoscaling.TargetReplicaConcurrency, userconfig.MaxReplicaConcurrencyKey, autoscaling.MaxReplicaConcurrency)
+		}
+	case userconfig.TargetLatencyP90AutoscalingPolicy:
+		if autoscaling.TargetLatencyP90 == nil {
+			return ErrorFieldMustBeDefinedForPolicy(userconfig.TargetLatencyP90Key, autoscaling.Policy)
+		}
+	case userconfig.TargetRPSPerReplicaAutoscalingPolicy:
+		if autoscaling.TargetRPSPerReplica == nil {
+			return ErrorFieldMustBeDefinedForPolicy(userconfig.TargetRPSPerReplicaKey, autoscaling.Policy)
+		}
 	}
 
 	if autoscaling.MinReplicas > autoscaling.MaxReplicas {
@@ -1134,6 +1296,12 @@ func validateAutoscaling(api *userconfig.API) error {
 		return ErrorInitReplicasLessThanMin(autoscaling.InitReplicas, autoscaling.MinReplicas)
 	}
 
+	for i, schedule := range autoscaling.Schedules {
+		if err := validateAutoscalingSchedule(schedule, autoscaling); err != nil {
+			return errors.Wrap(err, userconfig.SchedulesKey, s.Index(i))
+		}
+	}
+
 	if api.Compute.Inf > 0 {
 		numNeuronCores := api.Compute.Inf * consts.NeuronCoresPerInf
 		processesPerReplica := int64(predictor.ProcessesPerReplica)
@@ -1145,6 +1313,26 @@ func validateAutoscaling(api *userconfig.API) error {
 	return nil
 }
 
+func validateAutoscalingSchedule(schedule *userconfig.AutoscalingSchedule, autoscaling *userconfig.Autoscaling) error {
+	if schedule.MinReplicas == nil && schedule.MaxReplicas == nil {
+		return ErrorScheduleMissingReplicas()
+	}
+
+	if schedule.MinReplicas != nil && schedule.MaxReplicas != nil && *schedule.MinReplicas > *schedule.MaxReplicas {
+		return ErrorMinReplicasGreaterThanMax(*schedule.MinReplicas, *schedule.MaxReplicas)
+	}
+
+	if schedule.MinReplicas != nil && (*schedule.MinReplicas < autoscaling.MinReplicas || *schedule.MinReplicas > autoscaling.MaxReplicas) {
+		return ErrorScheduleReplicasOutOfRange(userconfig.MinReplicasKey, *schedule.MinReplicas, autoscaling.MinReplicas, autoscaling.MaxReplicas)
+	}
+
+	if schedule.MaxReplicas != nil && (*schedule.MaxReplicas < autoscaling.MinReplicas || *schedule.MaxReplicas > autoscaling.MaxReplicas) {
+		return ErrorScheduleReplicasOutOfRange(userconfig.MaxReplicasKey, *schedule.MaxReplicas, autoscaling.MinReplicas, autoscaling.MaxReplicas)
+	}
+
+	return nil
+}
+
 func validateCompute(api *userconfig.API, providerType types.ProviderType) error {
 	compute := api.Compute
 
@@ -1172,6 +1360,18 @@ func validateUpdateStrategy(updateStrategy *userconfig.UpdateStrategy) error {
 		return ErrorSurgeAndUnavailableBothZero()
 	}
 
+	if updateStrategy.Type != userconfig.CanaryUpdateStrategyType && updateStrategy.Canary != nil {
+		return ErrorFieldNotSupportedByUpdateStrategy(userconfig.CanaryKey, updateStrategy.Type)
+	}
+
+	// populate the default canary configuration
+	if updateStrategy.Type == userconfig.CanaryUpdateStrategyType && updateStrategy.Canary == nil {
+		updateStrategy.Canary = &userconfig.Canary{}
+		if errs := cr.Struct(updateStrategy.Canary, make(map[string]interface{}), canaryValidation); errors.HasError(errs) {
+			return errors.Wrap(errors.FirstError(errs...), userconfig.CanaryKey)
+		}
+	}
+
 	return nil
 }
 
@@ -20,6 +20,7 @@ type JobCode int
 
 const (
 	JobUnknown JobCode = iota
+	JobPending
 	JobEnqueuing
 	JobRunning
 	JobEnqueueFailed
@@ -29,10 +30,13 @@ const (
 	JobWorkerError
 	JobWorkerOOM
 	JobStopped
+	JobDependencyFailed
+	JobQueued
 )
 
 var _jobCodes = []string{
 	"status_unknown",
+	"status_pending",
 	"status_enqueuing",
 	"status_running",
 	"status_enqueue_failed",
@@ -42,12 +46,15 @@ var _jobCodes = []string{
 	"status_worker_error",
 	"status_worker_oom",
 	"status_stopped",
+	"status_dependency_failed",
+	"status_queued",
 }
 
-var _ = [1]int{}[int(JobStopped)-(len(_jobCodes)-1)] // Ensure list length matches
+var _ = [1]int{}[int(JobQueued)-(len(_jobCodes)-1)] // Ensure list length matches
 
 var _jobCodeMessages = []string{
 	"unknown",
+	"pending",
 	"enqueuing",
 	"running",
 	"failed while enqueuing",
@@ -57,16 +64,42 @@ var _jobCodeMessages = []string{
 	"worker error",
 	"out of memory",
 	"stopped",
+	"dependency failed",
+	"queued",
 }
 
-var _ = [1]int{}[int(JobStopped)-(len(_jobCodeMessages)-1)] // Ensure list length matches
+var _ = [1]int{}[int(JobQueued)-(len(_jobCodeMessages)-1)] // Ensure list length matches
 
 func (code JobCode) IsInProgress() bool {
-	return code == JobEnqueuing || code == JobRunning
+	return code == JobPending || code == JobQueued || code == JobEnqueuing || code == JobRunning
 }
 
 func (code JobCode) IsCompleted() bool {
-	return code == JobEnqueueFailed || code == JobCompletedWithFailures || code == JobSucceeded || code == JobUnexpectedError || code == JobWorkerError || code == JobWorkerOOM || code == JobStopped
+	return code == JobEnqueueFailed || code == JobCompletedWithFailures || code == JobSucceeded || code == JobUnexpectedError || code == JobWorkerError || code == JobWorkerOOM || code == JobStopped || code == JobDependencyFailed
+}
+
+// the statuses which a job may move to from each in progress status; completed statuses are final
+var _jobCodeTransitions = map[JobCode][]JobCode{
+	JobUnknown:   {JobPending, JobQueued, JobEnqueuing},
+	JobPending:   {JobQueued, JobEnqueuing, JobDependencyFailed, JobStopped, JobUnexpectedError},
+	JobQueued:    {JobEnqueuing, JobStopped, JobUnexpectedError},
+	JobEnqueuing: {JobRunning, JobEnqueueFailed, JobStopped, JobUnexpectedError},
+	JobRunning:   {JobSucceeded, JobCompletedWithFailures, JobWorkerError, JobWorkerOOM, JobStopped, JobUnexpectedError},
+}
+
+// CanTransitionTo returns whether a job may move from this status to next (in progress statuses may be set again)
+func (code JobCode) CanTransitionTo(next JobCode) bool {
+	if code == next {
+		return code.IsInProgress()
+	}
+
+	for _, allowedCode := range _jobCodeTransitions[code] {
+		if allowedCode == next {
+			return true
+		}
+	}
+
+	return false
 }
 
 func (code JobCode) String() string {
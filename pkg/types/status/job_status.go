@@ -25,9 +25,11 @@ import (
 
 type JobStatus struct {
 	spec.Job
-	EndTime        *time.Time            `json:"end_time"`
-	Status         JobCode               `json:"status"`
-	BatchesInQueue int                   `json:"batches_in_queue"`
-	BatchMetrics   *metrics.BatchMetrics `json:"batch_metrics"`
-	WorkerCounts   *WorkerCounts         `json:"worker_counts"`
+	EndTime                  *time.Time            `json:"end_time"`
+	Status                   JobCode               `json:"status"`
+	BatchesInQueue           int                   `json:"batches_in_queue"`
+	BatchesInDeadLetterQueue int                   `json:"batches_in_dead_letter_queue"` // batches which failed after all retries, retained for 14 days
+	BatchMetrics             *metrics.BatchMetrics `json:"batch_metrics"`
+	WorkerCounts             *WorkerCounts         `json:"worker_counts"`
+	QueuePosition            *int                  `json:"queue_position"` // 1-based position among the jobs waiting for the cluster's job quota, only present if the job is queued
 }
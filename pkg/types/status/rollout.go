@@ -0,0 +1,35 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"time"
+)
+
+// Rollout is the progress of a canary update of a SyncAPI
+type Rollout struct {
+	APIID         string      `json:"api_id"`      // the version being rolled out
+	PrevAPIID     string      `json:"prev_api_id"` // the version being replaced
+	Code          RolloutCode `json:"status_code"`
+	Steps         []int32     `json:"steps"`
+	Step          int         `json:"step"` // index of the current step, or -1 while the canary is starting
+	Weight        int32       `json:"weight"`
+	StartTime     time.Time   `json:"start_time"`
+	StepStartTime time.Time   `json:"step_start_time"`
+	EndTime       *time.Time  `json:"end_time"`
+	Message       string      `json:"message"`
+}
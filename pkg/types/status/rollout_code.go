@@ -0,0 +1,95 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+type RolloutCode int
+
+const (
+	RolloutUnknown RolloutCode = iota
+	RolloutInProgress
+	RolloutPromoting
+	RolloutSucceeded
+	RolloutRolledBack
+)
+
+var _rolloutCodes = []string{
+	"status_unknown",
+	"status_in_progress",
+	"status_promoting",
+	"status_succeeded",
+	"status_rolled_back",
+}
+
+var _ = [1]int{}[int(RolloutRolledBack)-(len(_rolloutCodes)-1)] // Ensure list length matches
+
+var _rolloutCodeMessages = []string{
+	"unknown",
+	"in progress",
+	"promoting",
+	"succeeded",
+	"rolled back",
+}
+
+var _ = [1]int{}[int(RolloutRolledBack)-(len(_rolloutCodeMessages)-1)] // Ensure list length matches
+
+func (code RolloutCode) IsInProgress() bool {
+	return code == RolloutInProgress || code == RolloutPromoting
+}
+
+func (code RolloutCode) String() string {
+	if int(code) < 0 || int(code) >= len(_rolloutCodes) {
+		return _rolloutCodes[RolloutUnknown]
+	}
+	return _rolloutCodes[code]
+}
+
+func (code RolloutCode) Message() string {
+	if int(code) < 0 || int(code) >= len(_rolloutCodeMessages) {
+		return _rolloutCodeMessages[RolloutUnknown]
+	}
+	return _rolloutCodeMessages[code]
+}
+
+// MarshalText satisfies TextMarshaler
+func (code RolloutCode) MarshalText() ([]byte, error) {
+	return []byte(code.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (code *RolloutCode) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_rolloutCodes); i++ {
+		if enum == _rolloutCodes[i] {
+			*code = RolloutCode(i)
+			return nil
+		}
+	}
+
+	*code = RolloutUnknown
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (code *RolloutCode) UnmarshalBinary(data []byte) error {
+	return code.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (code RolloutCode) MarshalBinary() ([]byte, error) {
+	return []byte(code.String()), nil
+}
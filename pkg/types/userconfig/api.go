@@ -22,6 +22,8 @@ import (
 	"time"
 
 	"github.com/cortexlabs/cortex/pkg/consts"
+	"github.com/cortexlabs/cortex/pkg/lib/cron"
+	"github.com/cortexlabs/cortex/pkg/lib/json"
 	"github.com/cortexlabs/cortex/pkg/lib/k8s"
 	s "github.com/cortexlabs/cortex/pkg/lib/strings"
 	"github.com/cortexlabs/cortex/pkg/types"
@@ -93,23 +95,44 @@ type Compute struct {
 }
 
 type Autoscaling struct {
-	MinReplicas                  int32         `json:"min_replicas" yaml:"min_replicas"`
-	MaxReplicas                  int32         `json:"max_replicas" yaml:"max_replicas"`
-	InitReplicas                 int32         `json:"init_replicas" yaml:"init_replicas"`
-	TargetReplicaConcurrency     *float64      `json:"target_replica_concurrency" yaml:"target_replica_concurrency"`
-	MaxReplicaConcurrency        int64         `json:"max_replica_concurrency" yaml:"max_replica_concurrency"`
-	Window                       time.Duration `json:"window" yaml:"window"`
-	DownscaleStabilizationPeriod time.Duration `json:"downscale_stabilization_period" yaml:"downscale_stabilization_period"`
-	UpscaleStabilizationPeriod   time.Duration `json:"upscale_stabilization_period" yaml:"upscale_stabilization_period"`
-	MaxDownscaleFactor           float64       `json:"max_downscale_factor" yaml:"max_downscale_factor"`
-	MaxUpscaleFactor             float64       `json:"max_upscale_factor" yaml:"max_upscale_factor"`
-	DownscaleTolerance           float64       `json:"downscale_tolerance" yaml:"downscale_tolerance"`
-	UpscaleTolerance             float64       `json:"upscale_tolerance" yaml:"upscale_tolerance"`
+	MinReplicas                  int32                  `json:"min_replicas" yaml:"min_replicas"`
+	MaxReplicas                  int32                  `json:"max_replicas" yaml:"max_replicas"`
+	InitReplicas                 int32                  `json:"init_replicas" yaml:"init_replicas"`
+	Policy                       AutoscalingPolicy      `json:"policy" yaml:"policy"`
+	TargetReplicaConcurrency     *float64               `json:"target_replica_concurrency" yaml:"target_replica_concurrency"`
+	TargetLatencyP90             *time.Duration         `json:"target_latency_p90" yaml:"target_latency_p90"`
+	TargetRPSPerReplica          *float64               `json:"target_rps_per_replica" yaml:"target_rps_per_replica"`
+	MaxReplicaConcurrency        int64                  `json:"max_replica_concurrency" yaml:"max_replica_concurrency"`
+	Window                       time.Duration          `json:"window" yaml:"window"`
+	DownscaleStabilizationPeriod time.Duration          `json:"downscale_stabilization_period" yaml:"downscale_stabilization_period"`
+	UpscaleStabilizationPeriod   time.Duration          `json:"upscale_stabilization_period" yaml:"upscale_stabilization_period"`
+	MaxDownscaleFactor           float64                `json:"max_downscale_factor" yaml:"max_downscale_factor"`
+	MaxUpscaleFactor             float64                `json:"max_upscale_factor" yaml:"max_upscale_factor"`
+	DownscaleTolerance           float64                `json:"downscale_tolerance" yaml:"downscale_tolerance"`
+	UpscaleTolerance             float64                `json:"upscale_tolerance" yaml:"upscale_tolerance"`
+	ScaleToZeroPeriod            time.Duration          `json:"scale_to_zero_period" yaml:"scale_to_zero_period"`
+	Schedules                    []*AutoscalingSchedule `json:"schedules" yaml:"schedules"`
+}
+
+type AutoscalingSchedule struct {
+	Cron        string        `json:"cron" yaml:"cron"`
+	Duration    time.Duration `json:"duration" yaml:"duration"`
+	MinReplicas *int32        `json:"min_replicas" yaml:"min_replicas"`
+	MaxReplicas *int32        `json:"max_replicas" yaml:"max_replicas"`
 }
 
 type UpdateStrategy struct {
-	MaxSurge       string `json:"max_surge" yaml:"max_surge"`
-	MaxUnavailable string `json:"max_unavailable" yaml:"max_unavailable"`
+	Type           UpdateStrategyType `json:"type" yaml:"type"`
+	MaxSurge       string             `json:"max_surge" yaml:"max_surge"`
+	MaxUnavailable string             `json:"max_unavailable" yaml:"max_unavailable"`
+	Canary         *Canary            `json:"canary" yaml:"canary"`
+}
+
+type Canary struct {
+	Steps        []int32        `json:"steps" yaml:"steps"`
+	StepDuration time.Duration  `json:"step_duration" yaml:"step_duration"`
+	MaxErrorRate float64        `json:"max_error_rate" yaml:"max_error_rate"`
+	MaxLatency   *time.Duration `json:"max_latency" yaml:"max_latency"`
 }
 
 func (api *API) Identify() string {
@@ -201,7 +224,15 @@ func (api *API) ToK8sAnnotations() map[string]string {
 	if api.Autoscaling != nil {
 		annotations[MinReplicasAnnotationKey] = s.Int32(api.Autoscaling.MinReplicas)
 		annotations[MaxReplicasAnnotationKey] = s.Int32(api.Autoscaling.MaxReplicas)
-		annotations[TargetReplicaConcurrencyAnnotationKey] = s.Float64(*api.Autoscaling.TargetReplicaConcurrency)
+		annotations[PolicyAnnotationKey] = api.Autoscaling.Policy.String()
+		switch api.Autoscaling.Policy {
+		case TargetReplicaConcurrencyAutoscalingPolicy:
+			annotations[TargetReplicaConcurrencyAnnotationKey] = s.Float64(*api.Autoscaling.TargetReplicaConcurrency)
+		case TargetLatencyP90AutoscalingPolicy:
+			annotations[TargetLatencyP90AnnotationKey] = api.Autoscaling.TargetLatencyP90.String()
+		case TargetRPSPerReplicaAutoscalingPolicy:
+			annotations[TargetRPSPerReplicaAnnotationKey] = s.Float64(*api.Autoscaling.TargetRPSPerReplica)
+		}
 		annotations[MaxReplicaConcurrencyAnnotationKey] = s.Int64(api.Autoscaling.MaxReplicaConcurrency)
 		annotations[WindowAnnotationKey] = api.Autoscaling.Window.String()
 		annotations[DownscaleStabilizationPeriodAnnotationKey] = api.Autoscaling.DownscaleStabilizationPeriod.String()
@@ -210,6 +241,10 @@ func (api *API) ToK8sAnnotations() map[string]string {
 		annotations[MaxUpscaleFactorAnnotationKey] = s.Float64(api.Autoscaling.MaxUpscaleFactor)
 		annotations[DownscaleToleranceAnnotationKey] = s.Float64(api.Autoscaling.DownscaleTolerance)
 		annotations[UpscaleToleranceAnnotationKey] = s.Float64(api.Autoscaling.UpscaleTolerance)
+		annotations[ScaleToZeroPeriodAnnotationKey] = api.Autoscaling.ScaleToZeroPeriod.String()
+		if len(api.Autoscaling.Schedules) > 0 {
+			annotations[SchedulesAnnotationKey], _ = json.MarshalJSONStr(api.Autoscaling.Schedules)
+		}
 	}
 	return annotations
 }
@@ -237,11 +272,35 @@ func AutoscalingFromAnnotations(k8sObj kmeta.Object) (*Autoscaling, error) {
 	}
 	a.MaxReplicas = maxReplicas
 
-	targetReplicaConcurrency, err := k8s.ParseFloat64Annotation(k8sObj, TargetReplicaConcurrencyAnnotationKey)
-	if err != nil {
-		return nil, err
+	// APIs which were deployed before autoscaling policies were introduced don't have the policy annotation
+	a.Policy = TargetReplicaConcurrencyAutoscalingPolicy
+	if policyStr, ok := k8sObj.GetAnnotations()[PolicyAnnotationKey]; ok {
+		a.Policy = AutoscalingPolicyFromString(policyStr)
+		if a.Policy == UnknownAutoscalingPolicy {
+			return nil, ErrorUnknownAutoscalingPolicy(policyStr)
+		}
+	}
+
+	switch a.Policy {
+	case TargetReplicaConcurrencyAutoscalingPolicy:
+		targetReplicaConcurrency, err := k8s.ParseFloat64Annotation(k8sObj, TargetReplicaConcurrencyAnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.TargetReplicaConcurrency = &targetReplicaConcurrency
+	case TargetLatencyP90AutoscalingPolicy:
+		targetLatencyP90, err := k8s.ParseDurationAnnotation(k8sObj, TargetLatencyP90AnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.TargetLatencyP90 = &targetLatencyP90
+	case TargetRPSPerReplicaAutoscalingPolicy:
+		targetRPSPerReplica, err := k8s.ParseFloat64Annotation(k8sObj, TargetRPSPerReplicaAnnotationKey)
+		if err != nil {
+			return nil, err
+		}
+		a.TargetRPSPerReplica = &targetRPSPerReplica
 	}
-	a.TargetReplicaConcurrency = &targetReplicaConcurrency
 
 	maxReplicaConcurrency, err := k8s.ParseInt64Annotation(k8sObj, MaxReplicaConcurrencyAnnotationKey)
 	if err != nil {
@@ -291,6 +350,18 @@ func AutoscalingFromAnnotations(k8sObj kmeta.Object) (*Autoscaling, error) {
 	}
 	a.UpscaleTolerance = upscaleTolerance
 
+	scaleToZeroPeriod, err := k8s.ParseDurationAnnotation(k8sObj, ScaleToZeroPeriodAnnotationKey)
+	if err != nil {
+		return nil, err
+	}
+	a.ScaleToZeroPeriod = scaleToZeroPeriod
+
+	if schedulesStr, ok := k8sObj.GetAnnotations()[SchedulesAnnotationKey]; ok {
+		if err := json.Unmarshal([]byte(schedulesStr), &a.Schedules); err != nil {
+			return nil, err
+		}
+	}
+
 	return &a, nil
 }
 
@@ -321,12 +392,12 @@ func (api *API) UserStr(provider types.ProviderType) string {
 		sb.WriteString(s.Indent(api.Compute.UserStr(), "  "))
 	}
 
-	if provider != types.LocalProviderType {
-		if api.Monitoring != nil {
-			sb.WriteString(fmt.Sprintf("%s:\n", MonitoringKey))
-			sb.WriteString(s.Indent(api.Monitoring.UserStr(), "  "))
-		}
+	if api.Monitoring != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", MonitoringKey))
+		sb.WriteString(s.Indent(api.Monitoring.UserStr(), "  "))
+	}
 
+	if provider != types.LocalProviderType {
 		if api.Autoscaling != nil {
 			sb.WriteString(fmt.Sprintf("%s:\n", AutoscalingKey))
 			sb.WriteString(s.Indent(api.Autoscaling.UserStr(), "  "))
@@ -488,7 +559,15 @@ func (autoscaling *Autoscaling) UserStr() string {
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MinReplicasKey, s.Int32(autoscaling.MinReplicas)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxReplicasKey, s.Int32(autoscaling.MaxReplicas)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", InitReplicasKey, s.Int32(autoscaling.InitReplicas)))
-	sb.WriteString(fmt.Sprintf("%s: %s\n", TargetReplicaConcurrencyKey, s.Float64(*autoscaling.TargetReplicaConcurrency)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", PolicyKey, autoscaling.Policy.String()))
+	switch autoscaling.Policy {
+	case TargetReplicaConcurrencyAutoscalingPolicy:
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TargetReplicaConcurrencyKey, s.Float64(*autoscaling.TargetReplicaConcurrency)))
+	case TargetLatencyP90AutoscalingPolicy:
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TargetLatencyP90Key, autoscaling.TargetLatencyP90.String()))
+	case TargetRPSPerReplicaAutoscalingPolicy:
+		sb.WriteString(fmt.Sprintf("%s: %s\n", TargetRPSPerReplicaKey, s.Float64(*autoscaling.TargetRPSPerReplica)))
+	}
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxReplicaConcurrencyKey, s.Int64(autoscaling.MaxReplicaConcurrency)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", WindowKey, autoscaling.Window.String()))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", DownscaleStabilizationPeriodKey, autoscaling.DownscaleStabilizationPeriod.String()))
@@ -497,12 +576,64 @@ func (autoscaling *Autoscaling) UserStr() string {
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxUpscaleFactorKey, s.Float64(autoscaling.MaxUpscaleFactor)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", DownscaleToleranceKey, s.Float64(autoscaling.DownscaleTolerance)))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", UpscaleToleranceKey, s.Float64(autoscaling.UpscaleTolerance)))
+	if autoscaling.MinReplicas == 0 {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", ScaleToZeroPeriodKey, autoscaling.ScaleToZeroPeriod.String()))
+	}
+	if len(autoscaling.Schedules) > 0 {
+		sb.WriteString(fmt.Sprintf("%s:\n", SchedulesKey))
+		for _, schedule := range autoscaling.Schedules {
+			sb.WriteString(s.Indent(schedule.UserStr(), "  "))
+		}
+	}
+	return sb.String()
+}
+
+func (schedule *AutoscalingSchedule) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("- %s: %s\n", CronKey, schedule.Cron))
+	sb.WriteString(fmt.Sprintf(s.Indent("%s: %s\n", "  "), DurationKey, schedule.Duration.String()))
+	if schedule.MinReplicas != nil {
+		sb.WriteString(fmt.Sprintf(s.Indent("%s: %s\n", "  "), MinReplicasKey, s.Int32(*schedule.MinReplicas)))
+	}
+	if schedule.MaxReplicas != nil {
+		sb.WriteString(fmt.Sprintf(s.Indent("%s: %s\n", "  "), MaxReplicasKey, s.Int32(*schedule.MaxReplicas)))
+	}
 	return sb.String()
 }
 
+// Returns the time at which the schedule was most recently activated if it is currently active, otherwise nil
+func (schedule *AutoscalingSchedule) ActiveSince(t time.Time) (*time.Time, error) {
+	cronSchedule, err := cron.ParseSchedule(schedule.Cron)
+	if err != nil {
+		return nil, err
+	}
+
+	activation := cronSchedule.LastActivation(t, schedule.Duration)
+	if activation == nil || !activation.Add(schedule.Duration).After(t) {
+		return nil, nil
+	}
+	return activation, nil
+}
+
 func (updateStrategy *UpdateStrategy) UserStr() string {
 	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", TypeKey, updateStrategy.Type.String()))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxSurgeKey, updateStrategy.MaxSurge))
 	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxUnavailableKey, updateStrategy.MaxUnavailable))
+	if updateStrategy.Canary != nil {
+		sb.WriteString(fmt.Sprintf("%s:\n", CanaryKey))
+		sb.WriteString(s.Indent(updateStrategy.Canary.UserStr(), "  "))
+	}
+	return sb.String()
+}
+
+func (canary *Canary) UserStr() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s: %s\n", StepsKey, s.ObjFlatNoQuotes(canary.Steps)))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", StepDurationKey, canary.StepDuration.String()))
+	sb.WriteString(fmt.Sprintf("%s: %s\n", MaxErrorRateKey, s.Float64(canary.MaxErrorRate)))
+	if canary.MaxLatency != nil {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", MaxLatencyKey, canary.MaxLatency.String()))
+	}
 	return sb.String()
 }
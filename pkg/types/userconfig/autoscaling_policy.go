@@ -0,0 +1,80 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userconfig
+
+type AutoscalingPolicy int
+
+const (
+	UnknownAutoscalingPolicy AutoscalingPolicy = iota
+	TargetReplicaConcurrencyAutoscalingPolicy
+	TargetLatencyP90AutoscalingPolicy
+	TargetRPSPerReplicaAutoscalingPolicy
+)
+
+var _autoscalingPolicies = []string{
+	"unknown",
+	"target_replica_concurrency",
+	"target_latency_p90",
+	"target_rps_per_replica",
+}
+
+func AutoscalingPolicyFromString(s string) AutoscalingPolicy {
+	for i := 0; i < len(_autoscalingPolicies); i++ {
+		if s == _autoscalingPolicies[i] {
+			return AutoscalingPolicy(i)
+		}
+	}
+	return UnknownAutoscalingPolicy
+}
+
+func AutoscalingPolicyStrings() []string {
+	return _autoscalingPolicies[1:]
+}
+
+func (t AutoscalingPolicy) String() string {
+	return _autoscalingPolicies[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t AutoscalingPolicy) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *AutoscalingPolicy) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_autoscalingPolicies); i++ {
+		if enum == _autoscalingPolicies[i] {
+			*t = AutoscalingPolicy(i)
+			return nil
+		}
+	}
+
+	*t = UnknownAutoscalingPolicy
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *AutoscalingPolicy) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t AutoscalingPolicy) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
@@ -72,7 +72,10 @@ const (
 	MinReplicasKey                  = "min_replicas"
 	MaxReplicasKey                  = "max_replicas"
 	InitReplicasKey                 = "init_replicas"
+	PolicyKey                       = "policy"
 	TargetReplicaConcurrencyKey     = "target_replica_concurrency"
+	TargetLatencyP90Key             = "target_latency_p90"
+	TargetRPSPerReplicaKey          = "target_rps_per_replica"
 	MaxReplicaConcurrencyKey        = "max_replica_concurrency"
 	WindowKey                       = "window"
 	DownscaleStabilizationPeriodKey = "downscale_stabilization_period"
@@ -81,10 +84,23 @@ const (
 	MaxUpscaleFactorKey             = "max_upscale_factor"
 	DownscaleToleranceKey           = "downscale_tolerance"
 	UpscaleToleranceKey             = "upscale_tolerance"
+	ScaleToZeroPeriodKey            = "scale_to_zero_period"
+	SchedulesKey                    = "schedules"
+
+	// AutoscalingSchedule
+	CronKey     = "cron"
+	DurationKey = "duration"
 
 	// UpdateStrategy
 	MaxSurgeKey       = "max_surge"
 	MaxUnavailableKey = "max_unavailable"
+	CanaryKey         = "canary"
+
+	// Canary
+	StepsKey        = "steps"
+	StepDurationKey = "step_duration"
+	MaxErrorRateKey = "max_error_rate"
+	MaxLatencyKey   = "max_latency"
 
 	// K8s annotation
 	EndpointAnnotationKey                     = "networking.cortex.dev/endpoint"
@@ -93,7 +109,10 @@ const (
 	ThreadsPerProcessAnnotationKey            = "predictor.cortex.dev/threads-per-process"
 	MinReplicasAnnotationKey                  = "autoscaling.cortex.dev/min-replicas"
 	MaxReplicasAnnotationKey                  = "autoscaling.cortex.dev/max-replicas"
+	PolicyAnnotationKey                       = "autoscaling.cortex.dev/policy"
 	TargetReplicaConcurrencyAnnotationKey     = "autoscaling.cortex.dev/target-replica-concurrency"
+	TargetLatencyP90AnnotationKey             = "autoscaling.cortex.dev/target-latency-p90"
+	TargetRPSPerReplicaAnnotationKey          = "autoscaling.cortex.dev/target-rps-per-replica"
 	MaxReplicaConcurrencyAnnotationKey        = "autoscaling.cortex.dev/max-replica-concurrency"
 	WindowAnnotationKey                       = "autoscaling.cortex.dev/window"
 	DownscaleStabilizationPeriodAnnotationKey = "autoscaling.cortex.dev/downscale-stabilization-period"
@@ -102,4 +121,6 @@ const (
 	MaxUpscaleFactorAnnotationKey             = "autoscaling.cortex.dev/max-upscale-factor"
 	DownscaleToleranceAnnotationKey           = "autoscaling.cortex.dev/downscale-tolerance"
 	UpscaleToleranceAnnotationKey             = "autoscaling.cortex.dev/upscale-tolerance"
+	ScaleToZeroPeriodAnnotationKey            = "autoscaling.cortex.dev/scale-to-zero-period"
+	SchedulesAnnotationKey                    = "autoscaling.cortex.dev/schedules"
 )
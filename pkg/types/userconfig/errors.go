@@ -17,11 +17,14 @@ limitations under the License.
 package userconfig
 
 import (
+	"fmt"
+
 	"github.com/cortexlabs/cortex/pkg/lib/errors"
 )
 
 const (
-	ErrUnknownAPIGatewayType = "userconfig.unknown_api_gateway_type"
+	ErrUnknownAPIGatewayType    = "userconfig.unknown_api_gateway_type"
+	ErrUnknownAutoscalingPolicy = "userconfig.unknown_autoscaling_policy"
 )
 
 func ErrorUnknownAPIGatewayType() error {
@@ -30,3 +33,10 @@ func ErrorUnknownAPIGatewayType() error {
 		Message: "unknown api gateway type",
 	})
 }
+
+func ErrorUnknownAutoscalingPolicy(policy string) error {
+	return errors.WithStack(&errors.Error{
+		Kind:    ErrUnknownAutoscalingPolicy,
+		Message: fmt.Sprintf("unknown autoscaling policy: %s", policy),
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package userconfig
+
+type UpdateStrategyType int
+
+const (
+	UnknownUpdateStrategyType UpdateStrategyType = iota
+	RollingUpdateStrategyType
+	CanaryUpdateStrategyType
+)
+
+var _updateStrategyTypes = []string{
+	"unknown",
+	"rolling_update",
+	"canary",
+}
+
+func UpdateStrategyTypeFromString(s string) UpdateStrategyType {
+	for i := 0; i < len(_updateStrategyTypes); i++ {
+		if s == _updateStrategyTypes[i] {
+			return UpdateStrategyType(i)
+		}
+	}
+	return UnknownUpdateStrategyType
+}
+
+func UpdateStrategyTypeStrings() []string {
+	return _updateStrategyTypes[1:]
+}
+
+func (t UpdateStrategyType) String() string {
+	return _updateStrategyTypes[t]
+}
+
+// MarshalText satisfies TextMarshaler
+func (t UpdateStrategyType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText satisfies TextUnmarshaler
+func (t *UpdateStrategyType) UnmarshalText(text []byte) error {
+	enum := string(text)
+	for i := 0; i < len(_updateStrategyTypes); i++ {
+		if enum == _updateStrategyTypes[i] {
+			*t = UpdateStrategyType(i)
+			return nil
+		}
+	}
+
+	*t = UnknownUpdateStrategyType
+	return nil
+}
+
+// UnmarshalBinary satisfies BinaryUnmarshaler
+// Needed for msgpack
+func (t *UpdateStrategyType) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalText(data)
+}
+
+// MarshalBinary satisfies BinaryMarshaler
+func (t UpdateStrategyType) MarshalBinary() ([]byte, error) {
+	return []byte(t.String()), nil
+}
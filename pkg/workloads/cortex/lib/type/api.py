@@ -15,7 +15,6 @@
 import os
 import base64
 import time
-from pathlib import Path
 import json
 import msgpack
 
@@ -27,6 +26,9 @@ from cortex.lib.type.predictor import Predictor
 from cortex.lib.type.monitoring import Monitoring
 from cortex.lib.storage import S3
 
+LOCAL_METRICS_DIR = "/mnt/workspace/metrics"
+LOCAL_METRICS_MAX_FILE_SIZE = 10 * 1024 * 1024  # bytes; each process keeps one rotated file
+
 
 class API:
     def __init__(self, provider, storage, model_dir, cache_dir=".", **kwargs):
@@ -86,7 +88,12 @@ class API:
             self.post_metrics(metrics)
 
     def post_monitoring_metrics(self, prediction_value=None):
-        if prediction_value is not None:
+        if prediction_value is None:
+            return
+
+        if self.provider == "local":
+            self.store_monitoring_metrics_locally(prediction_value)
+        else:
             metrics = [
                 self.prediction_metrics(self.metric_dimensions(), prediction_value),
                 self.prediction_metrics(self.metric_dimensions_with_id(), prediction_value),
@@ -108,22 +115,29 @@ class API:
             cx_logger().warn("failure encountered while publishing metrics", exc_info=True)
 
     def store_metrics_locally(self, status_code, total_time):
-        status_code_series = int(status_code / 100)
-
-        status_code_file_name = f"/mnt/workspace/{os.getpid()}.{status_code_series}XX"
-        self.increment_counter_file(status_code_file_name, 1)
+        self.append_local_metrics_record({"status_code": status_code, "latency": total_time})
 
-        request_time_file = f"/mnt/workspace/{os.getpid()}.request_time"
-        self.increment_counter_file(request_time_file, total_time)
-
-    def increment_counter_file(self, file_name, value):
-        previous_val = 0
-        if Path(file_name).is_file():
-            with open(file_name, "r") as f:
-                previous_val = json.load(f)  # values are either of type int or float
+    def store_monitoring_metrics_locally(self, prediction_value):
+        if self.monitoring.model_type == "classification":
+            self.append_local_metrics_record({"predicted_class": str(prediction_value)})
+        else:
+            self.append_local_metrics_record({"predicted_value": float(prediction_value)})
 
-        with open(file_name, "w") as f:
-            json.dump(previous_val + value, f)
+    def append_local_metrics_record(self, record):
+        # each process appends to its own file, so concurrent writers never interleave
+        record["timestamp"] = time.time()
+        file_name = os.path.join(LOCAL_METRICS_DIR, f"{os.getpid()}.jsonl")
+        try:
+            os.makedirs(LOCAL_METRICS_DIR, exist_ok=True)
+            if (
+                os.path.isfile(file_name)
+                and os.path.getsize(file_name) >= LOCAL_METRICS_MAX_FILE_SIZE
+            ):
+                os.replace(file_name, file_name + ".1")
+            with open(file_name, "a") as f:
+                f.write(json.dumps(record) + "\n")
+        except:
+            cx_logger().warn("failure encountered while storing metrics", exc_info=True)
 
     def status_code_metric(self, dimensions, status_code):
         status_code_series = int(status_code / 100)
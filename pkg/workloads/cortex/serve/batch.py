@@ -14,6 +14,8 @@
 
 import sys
 import os
+import io
+import csv
 import argparse
 import inspect
 import time
@@ -21,6 +23,8 @@ import json
 import msgpack
 import threading
 import math
+import socket
+import datetime
 
 import boto3
 import botocore
@@ -34,6 +38,7 @@ from cortex.lib.exceptions import UserRuntimeException
 
 API_LIVENESS_UPDATE_PERIOD = 5  # seconds
 MAXIMUM_MESSAGE_VISIBILITY = 60 * 60 * 12  # 12 hours is the maximum message visibility
+MAX_ERROR_LENGTH = 1000  # characters of the error which are kept with a batch in the dead-letter queue
 
 local_cache = {
     "api_spec": None,
@@ -80,7 +85,11 @@ def build_predict_args(payload, batch_id):
     return args
 
 
-def get_job_spec(storage, cache_dir, job_spec_path):
+def get_job_spec(provider, storage, cache_dir, job_spec_path):
+    if provider == "local":
+        with open(job_spec_path) as f:
+            return json.load(f)
+
     local_spec_path = os.path.join(cache_dir, "job_spec.json")
     _, key = S3.deconstruct_s3_path(job_spec_path)
     storage.download_file(key, local_spec_path)
@@ -161,6 +170,7 @@ def sqs_loop():
             WaitTimeSeconds=10,
             VisibilityTimeout=MAXIMUM_MESSAGE_VISIBILITY,
             MessageAttributeNames=["All"],
+            AttributeNames=["ApproximateReceiveCount"],
         )
 
         if response.get("Messages") is None or len(response["Messages"]) == 0:
@@ -186,27 +196,234 @@ def sqs_loop():
                 # sometimes on_job_complete message will be released if there are other messages still to be processed
                 continue
 
-        try:
-            cx_logger().info(f"processing batch {message['MessageId']}")
+        batch_id = message["MessageId"]
+        attempt = int(message.get("Attributes", {}).get("ApproximateReceiveCount", 1))
+        start_time = time.time()
 
-            start_time = time.time()
+        try:
+            cx_logger().info(f"processing batch {batch_id}")
 
             payload = json.loads(message["Body"])
-            batch_id = message["MessageId"]
-            predictor_impl.predict(**build_predict_args(payload, batch_id))
+            result = predictor_impl.predict(**build_predict_args(payload, batch_id))
+            if local_cache.get("result_storage") is not None and result is not None:
+                write_result(result, batch_id)
 
             api_spec.post_metrics(
                 [success_counter_metric(), time_per_batch_metric(time.time() - start_time)]
             )
-        except Exception:
+            sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
+        except Exception as e:
+            if attempt <= job_spec.get("max_retries", 0):
+                cx_logger().exception(
+                    f"failed to process batch {batch_id} (attempt {attempt}), it will be retried"
+                )
+                # make the batch visible again so that it can be received by any worker
+                sqs_client.change_message_visibility(
+                    QueueUrl=queue_url, ReceiptHandle=receipt_handle, VisibilityTimeout=0
+                )
+                continue
+
+            cx_logger().exception(
+                f"failed to process batch {batch_id} (attempt {attempt}), moving it to the dead-letter queue"
+            )
+            try:
+                move_to_dead_letter_queue(message, attempt, e)
+            except Exception:
+                cx_logger().exception(f"failed to move batch {batch_id} to the dead-letter queue")
+
             api_spec.post_metrics(
                 [failed_counter_metric(), time_per_batch_metric(time.time() - start_time)]
             )
-            cx_logger().exception("failed to process batch")
-        finally:
             sqs_client.delete_message(QueueUrl=queue_url, ReceiptHandle=receipt_handle)
 
 
+# the local provider uses a queue of files in the job directory instead of SQS (see cli/local/batch.go)
+def local_queue_loop():
+    job_dir = local_cache["job_dir"]
+    in_progress_dir = os.path.join(job_dir, "in_progress")
+
+    while True:
+        batch_path = claim_local_batch()
+        if batch_path is not None:
+            process_local_batch(batch_path)
+            continue
+
+        # batches which are being processed by other workers may still be retried
+        if len(os.listdir(in_progress_dir)) > 0:
+            time.sleep(1)
+            continue
+
+        handle_local_on_complete()
+        cx_logger().info("no batches left in queue, job has been completed")
+        return
+
+
+def claim_local_batch():
+    job_dir = local_cache["job_dir"]
+    queue_dir = os.path.join(job_dir, "queue")
+    in_progress_dir = os.path.join(job_dir, "in_progress")
+
+    for file_name in sorted(os.listdir(queue_dir)):
+        batch_path = os.path.join(in_progress_dir, file_name)
+        try:
+            # renaming is atomic, so each batch is claimed by exactly one worker
+            os.rename(os.path.join(queue_dir, file_name), batch_path)
+            return batch_path
+        except FileNotFoundError:
+            continue  # claimed by another worker
+
+    return None
+
+
+def process_local_batch(batch_path):
+    job_spec = local_cache["job_spec"]
+    predictor_impl = local_cache["predictor_impl"]
+
+    with open(batch_path) as f:
+        batch = json.load(f)
+
+    batch_id = batch["batch_id"]
+    attempt = batch["attempts"] + 1
+    start_time = time.time()
+
+    try:
+        cx_logger().info(f"processing batch {batch_id}")
+
+        result = predictor_impl.predict(**build_predict_args(batch["payload"], batch_id))
+        if local_cache.get("result_storage") is not None and result is not None:
+            write_result(result, batch_id)
+
+        store_batch_metrics_locally(True, time.time() - start_time)
+        os.remove(batch_path)
+    except Exception as e:
+        if attempt <= job_spec.get("max_retries", 0):
+            cx_logger().exception(
+                f"failed to process batch {batch_id} (attempt {attempt}), it will be retried"
+            )
+            batch["attempts"] = attempt
+            write_json_atomically(
+                batch, os.path.join(local_cache["job_dir"], "queue", os.path.basename(batch_path))
+            )
+            os.remove(batch_path)
+            return
+
+        cx_logger().exception(
+            f"failed to process batch {batch_id} (attempt {attempt}), moving it to the dead-letter queue"
+        )
+        failed_batch = {
+            "batch_id": batch_id,
+            "payload": batch["payload"],
+            "attempts": attempt,
+            "error": f"{type(e).__name__}: {e}"[:MAX_ERROR_LENGTH],
+            "failed_at": datetime.datetime.now(datetime.timezone.utc).isoformat(),
+        }
+        write_json_atomically(
+            failed_batch,
+            os.path.join(local_cache["job_dir"], "dead_letter", os.path.basename(batch_path)),
+        )
+
+        store_batch_metrics_locally(False, time.time() - start_time)
+        os.remove(batch_path)
+
+
+def handle_local_on_complete():
+    predictor_impl = local_cache["predictor_impl"]
+    if not getattr(predictor_impl, "on_job_complete", None):
+        return
+
+    job_dir = local_cache["job_dir"]
+    try:
+        # only the worker which claims the marker runs on_job_complete
+        os.rename(
+            os.path.join(job_dir, "job_complete"), os.path.join(job_dir, "job_complete.claimed")
+        )
+    except FileNotFoundError:
+        return
+
+    cx_logger().info("executing on_job_complete")
+    predictor_impl.on_job_complete()
+
+
+def store_batch_metrics_locally(succeeded, total_time):
+    worker_metrics = local_cache["worker_metrics"]
+    if succeeded:
+        worker_metrics["succeeded"] += 1
+    else:
+        worker_metrics["failed"] += 1
+    worker_metrics["total_time"] += total_time
+
+    metrics_path = os.path.join(local_cache["job_dir"], "metrics", f"{socket.gethostname()}.json")
+    write_json_atomically(worker_metrics, metrics_path)
+
+
+def write_json_atomically(obj, path):
+    # the temporary file is in the job directory so that it's on the same filesystem as path
+    tmp_path = os.path.join(local_cache["job_dir"], f".{socket.gethostname()}.tmp")
+    with open(tmp_path, "w") as f:
+        json.dump(obj, f)
+    os.rename(tmp_path, path)
+
+
+def write_result(result, batch_id):
+    job_spec = local_cache["job_spec"]
+    result_storage = local_cache["result_storage"]
+    result_format = job_spec["result_sink"]["format"]
+
+    # a list is treated as one result per line/row, anything else as a single result
+    if not isinstance(result, list):
+        result = [result]
+
+    if result_format == "csv":
+        body = to_csv(result)
+    else:
+        body = "".join(json.dumps(item) + "\n" for item in result)
+
+    key = os.path.join(local_cache["result_prefix"], f"{batch_id}.{result_format}")
+    result_storage.put_str(body, key)
+
+
+def to_csv(rows):
+    buffer = io.StringIO()
+
+    if all(isinstance(row, dict) for row in rows):
+        fieldnames = []
+        for row in rows:
+            fieldnames += [key for key in row.keys() if key not in fieldnames]
+        writer = csv.DictWriter(buffer, fieldnames=fieldnames)
+        writer.writeheader()
+        writer.writerows(rows)
+    else:
+        writer = csv.writer(buffer)
+        for row in rows:
+            writer.writerow(row if isinstance(row, (list, tuple)) else [row])
+
+    return buffer.getvalue()
+
+
+def move_to_dead_letter_queue(message, attempts, exception):
+    job_spec = local_cache["job_spec"]
+    sqs_client = local_cache["sqs_client"]
+
+    dead_letter_queue_url = job_spec.get("dead_letter_sqs_url")
+    if not dead_letter_queue_url:
+        return
+
+    sqs_client.send_message(
+        QueueUrl=dead_letter_queue_url,
+        MessageBody=message["Body"],
+        MessageGroupId=message["MessageId"],
+        MessageDeduplicationId=message["MessageId"],
+        MessageAttributes={
+            "batch_id": {"DataType": "String", "StringValue": message["MessageId"]},
+            "attempts": {"DataType": "Number", "StringValue": str(attempts)},
+            "error": {
+                "DataType": "String",
+                "StringValue": f"{type(exception).__name__}: {exception}"[:MAX_ERROR_LENGTH],
+            },
+        },
+    )
+
+
 def start():
     cache_dir = os.environ["CORTEX_CACHE_DIR"]
     provider = os.environ["CORTEX_PROVIDER"]
@@ -218,7 +435,10 @@ def start():
     tf_serving_port = os.getenv("CORTEX_TF_BASE_SERVING_PORT", "9000")
     tf_serving_host = os.getenv("CORTEX_TF_SERVING_HOST", "localhost")
 
-    storage = S3(bucket=os.environ["CORTEX_BUCKET"], region=os.environ["AWS_REGION"])
+    if provider == "local":
+        storage = LocalStorage(cache_dir)
+    else:
+        storage = S3(bucket=os.environ["CORTEX_BUCKET"], region=os.environ["AWS_REGION"])
 
     has_multiple_servers = os.getenv("CORTEX_MULTIPLE_TF_SERVERS")
     if has_multiple_servers:
@@ -235,7 +455,7 @@ def start():
                 f.truncate()
 
     raw_api_spec = get_spec(provider, storage, cache_dir, api_spec_path)
-    job_spec = get_job_spec(storage, cache_dir, job_spec_path)
+    job_spec = get_job_spec(provider, storage, cache_dir, job_spec_path)
 
     api = API(
         provider=provider, storage=storage, model_dir=model_dir, cache_dir=cache_dir, **raw_api_spec
@@ -252,12 +472,26 @@ def start():
     local_cache["job_spec"] = job_spec
     local_cache["predictor_impl"] = predictor_impl
     local_cache["predict_fn_args"] = inspect.getfullargspec(predictor_impl.predict).args
-    local_cache["sqs_client"] = boto3.client("sqs", region_name=os.environ["AWS_REGION"])
+    if provider == "local":
+        local_cache["job_dir"] = os.path.dirname(job_spec_path)
+        local_cache["worker_metrics"] = {"succeeded": 0, "failed": 0, "total_time": 0}
+    else:
+        local_cache["sqs_client"] = boto3.client("sqs", region_name=os.environ["AWS_REGION"])
+
+    if job_spec.get("result_sink") is not None:
+        # results are written to s3://<bucket>/<prefix>/<job_id>/<batch_id>.<format>
+        result_s3_path = util.trim_prefix(job_spec["result_sink"]["s3_path"], "s3://")
+        bucket, _, prefix = result_s3_path.partition("/")
+        local_cache["result_storage"] = S3(bucket=bucket, region=os.environ["AWS_REGION"])
+        local_cache["result_prefix"] = os.path.join(prefix, job_spec["job_id"])
 
     open("/mnt/workspace/api_readiness.txt", "a").close()
 
     cx_logger().info("polling for batches...")
-    sqs_loop()
+    if provider == "local":
+        local_queue_loop()
+    else:
+        sqs_loop()
 
 
 if __name__ == "__main__":
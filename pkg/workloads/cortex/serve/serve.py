@@ -199,12 +199,13 @@ def predict(request: Request):
             ) from e
         response = Response(content=json_string, media_type="application/json")
 
-    if local_cache["provider"] != "local" and api.monitoring is not None:
+    if api.monitoring is not None:
         try:
             predicted_value = api.monitoring.extract_predicted_value(prediction)
             api.post_monitoring_metrics(predicted_value)
             if (
-                api.monitoring.model_type == "classification"
+                local_cache["provider"] != "local"
+                and api.monitoring.model_type == "classification"
                 and predicted_value not in local_cache["class_set"]
             ):
                 tasks.add_task(api.upload_class, class_name=predicted_value)